@@ -69,7 +69,7 @@ func (p *PatchStrategicMergeTransformerPlugin) Transform(m resmap.ResMap) error
 		if err != nil {
 			return err
 		}
-		err = target.Patch(patch.Kunstructured)
+		err = p.h.ResmapFactory().ApplySmPatch(target, patch)
 		if err != nil {
 			return err
 		}
@@ -15,6 +15,7 @@ import (
 )
 
 type PatchTransformerPlugin struct {
+	h            *resmap.PluginHelpers
 	loadedPatch  *resource.Resource
 	decodedPatch jsonpatch.Patch
 	Path         string          `json:"path,omitempty" yaml:"path,omitempty"`
@@ -24,6 +25,7 @@ type PatchTransformerPlugin struct {
 
 func (p *PatchTransformerPlugin) Config(
 	h *resmap.PluginHelpers, c []byte) (err error) {
+	p.h = h
 	err = yaml.Unmarshal(c, p)
 	if err != nil {
 		return err
@@ -76,7 +78,7 @@ func (p *PatchTransformerPlugin) Transform(m resmap.ResMap) error {
 		if err != nil {
 			return err
 		}
-		err = target.Patch(p.loadedPatch.Kunstructured)
+		err = p.h.ResmapFactory().ApplySmPatch(target, p.loadedPatch)
 		if err != nil {
 			return err
 		}
@@ -112,7 +114,7 @@ func (p *PatchTransformerPlugin) Transform(m resmap.ResMap) error {
 			patchCopy.SetName(res.GetName())
 			patchCopy.SetNamespace(res.GetNamespace())
 			patchCopy.SetGvk(res.GetGvk())
-			err = res.Patch(patchCopy.Kunstructured)
+			err = p.h.ResmapFactory().ApplySmPatch(res, patchCopy)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,216 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package depgraph holds the graph of what kustomizations
+// include: bases, components, remote bases, and the files
+// of resources, patches, generator inputs and the like.
+package depgraph
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// NodeKind is the kind of a node of the graph.
+type NodeKind string
+
+const (
+	// A directory holding a kustomization.
+	Kustomization NodeKind = "Kustomization"
+	// A directory holding a component.
+	Component NodeKind = "Component"
+	// A remote base, which isn't walked.
+	Remote NodeKind = "Remote"
+	// A file read by a kustomization.
+	File NodeKind = "File"
+	// A directory read as a whole, e.g. a local helm chart;
+	// it includes every file below it.
+	Directory NodeKind = "Directory"
+)
+
+// Node is a kustomization, a remote base, a file or a directory.
+type Node struct {
+	// The path of the directory or file, or for a
+	// remote base, its URL as written.
+	ID   string   `json:"id" yaml:"id"`
+	Kind NodeKind `json:"kind" yaml:"kind"`
+}
+
+// Edge says that a kustomization includes a node.
+type Edge struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+	// The field of the kustomization naming the node,
+	// e.g. resources, or kustomization for its own file.
+	Field string `json:"field" yaml:"field"`
+}
+
+// Graph is the directed graph of what kustomizations
+// include, walked from its roots.
+type Graph struct {
+	Roots []string `json:"roots" yaml:"roots"`
+	Nodes []Node   `json:"nodes" yaml:"nodes"`
+	Edges []Edge   `json:"edges" yaml:"edges"`
+
+	nodes map[string]NodeKind
+	edges map[Edge]bool
+}
+
+// New returns an empty graph.
+func New() *Graph {
+	return &Graph{
+		nodes: make(map[string]NodeKind),
+		edges: make(map[Edge]bool),
+	}
+}
+
+// AddRoot records that id was walked from.
+func (g *Graph) AddRoot(id string) {
+	for _, r := range g.Roots {
+		if r == id {
+			return
+		}
+	}
+	g.Roots = append(g.Roots, id)
+}
+
+// AddNode adds a node, returning false if
+// there's already one with the id.
+func (g *Graph) AddNode(id string, kind NodeKind) bool {
+	if _, ok := g.nodes[id]; ok {
+		return false
+	}
+	g.nodes[id] = kind
+	g.Nodes = append(g.Nodes, Node{ID: id, Kind: kind})
+	return true
+}
+
+// AddEdge adds an edge, unless it's there already.
+func (g *Graph) AddEdge(from, to, field string) {
+	e := Edge{From: from, To: to, Field: field}
+	if g.edges[e] {
+		return
+	}
+	g.edges[e] = true
+	g.Edges = append(g.Edges, e)
+}
+
+// HasNode is true if the graph has a node with the id.
+func (g *Graph) HasNode(id string) bool {
+	_, ok := g.nodes[id]
+	return ok
+}
+
+// Relabel returns a copy of the graph with each id
+// replaced by what f returns for it, e.g. to make
+// paths relative.
+func (g *Graph) Relabel(f func(id string) string) *Graph {
+	result := New()
+	for _, r := range g.Roots {
+		result.AddRoot(f(r))
+	}
+	for _, n := range g.Nodes {
+		result.AddNode(f(n.ID), n.Kind)
+	}
+	for _, e := range g.Edges {
+		result.AddEdge(f(e.From), f(e.To), e.Field)
+	}
+	return result
+}
+
+// Dependents returns the sorted ids of the nodes that
+// include the node with the given id, directly or not.
+func (g *Graph) Dependents(id string) []string {
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+	for len(queue) > 0 {
+		to := queue[0]
+		queue = queue[1:]
+		for _, e := range g.Edges {
+			if e.To == to && !seen[e.From] {
+				seen[e.From] = true
+				queue = append(queue, e.From)
+			}
+		}
+	}
+	delete(seen, id)
+	result := make([]string, 0, len(seen))
+	for n := range seen {
+		result = append(result, n)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// RootsIncluding returns the sorted roots that include
+// the node with the given id, or are that node; e.g. the
+// roots to build again when a file changes.  A file below
+// a Directory node is included wherever the directory is.
+func (g *Graph) RootsIncluding(id string) []string {
+	dependents := make(map[string]bool)
+	for _, n := range g.Nodes {
+		if n.ID == id || n.Kind == Directory &&
+			strings.HasPrefix(id, n.ID+string(filepath.Separator)) {
+			dependents[n.ID] = true
+			for _, d := range g.Dependents(n.ID) {
+				dependents[d] = true
+			}
+		}
+	}
+	if len(dependents) == 0 {
+		return nil
+	}
+	var result []string
+	for _, r := range g.Roots {
+		if dependents[r] {
+			result = append(result, r)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// WriteDot writes the graph in the DOT language of Graphviz.
+func (g *Graph) WriteDot(w io.Writer) error {
+	roots := make(map[string]bool)
+	for _, r := range g.Roots {
+		roots[r] = true
+	}
+	if _, err := fmt.Fprintln(w, "digraph kustomize {"); err != nil {
+		return err
+	}
+	for _, n := range g.Nodes {
+		attrs := fmt.Sprintf("shape=%s", shapes[n.Kind])
+		if roots[n.ID] {
+			attrs += ", style=bold"
+		}
+		if _, err := fmt.Fprintf(w, "  %s [%s];\n", dotID(n.ID), attrs); err != nil {
+			return err
+		}
+	}
+	for _, e := range g.Edges {
+		_, err := fmt.Fprintf(w, "  %s -> %s [label=%s];\n",
+			dotID(e.From), dotID(e.To), dotID(e.Field))
+		if err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+var shapes = map[NodeKind]string{
+	Kustomization: "box",
+	Component:     "component",
+	Remote:        "box3d",
+	File:          "note",
+	Directory:     "folder",
+}
+
+// dotID quotes s as a DOT identifier.
+func dotID(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
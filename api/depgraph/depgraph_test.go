@@ -0,0 +1,51 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package depgraph_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "sigs.k8s.io/kustomize/api/depgraph"
+)
+
+func TestGraph(t *testing.T) {
+	g := New()
+	g.AddRoot("/r/prod")
+	g.AddNode("/r/prod", Kustomization)
+	g.AddNode("/r/base", Kustomization)
+	if g.AddNode("/r/base", Kustomization) {
+		t.Fatalf("expected the node to be there already")
+	}
+	g.AddNode("/r/base/a.yaml", File)
+	g.AddEdge("/r/prod", "/r/base", "resources")
+	g.AddEdge("/r/prod", "/r/base", "resources")
+	g.AddEdge("/r/base", "/r/base/a.yaml", "resources")
+
+	if d := g.Dependents("/r/base/a.yaml"); !reflect.DeepEqual(
+		d, []string{"/r/base", "/r/prod"}) {
+		t.Fatalf("unexpected dependents %v", d)
+	}
+	rel := g.Relabel(func(id string) string {
+		return strings.TrimPrefix(id, "/r/")
+	})
+	b, err := json.Marshal(rel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"roots":["prod"],` +
+		`"nodes":[{"id":"prod","kind":"Kustomization"},` +
+		`{"id":"base","kind":"Kustomization"},` +
+		`{"id":"base/a.yaml","kind":"File"}],` +
+		`"edges":[{"from":"prod","to":"base","field":"resources"},` +
+		`{"from":"base","to":"base/a.yaml","field":"resources"}]}`
+	if string(b) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b)
+	}
+	if r := rel.RootsIncluding("base/a.yaml"); !reflect.DeepEqual(r, []string{"prod"}) {
+		t.Fatalf("unexpected roots %v", r)
+	}
+}
@@ -0,0 +1,301 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package fieldpath parses paths to the fields of resources,
+// as written in the fieldSpecs of transformer configurations,
+// the fieldPath of vars and the fieldrefs of replacements, and
+// follows them to get or change the fields.
+//
+// A path is a list of map keys, separated by dots, or by
+// slashes in a fieldSpec.  A key containing the separator
+// escapes it with a backslash, or is quoted in brackets:
+//
+//   metadata.annotations.example\.com/owner
+//   metadata.annotations['example.com/owner']
+//   metadata/annotations/example.com\/owner
+//
+// A key may be followed by selectors of list items:
+//
+//   containers[0]         the first item
+//   containers[name=app]  the items whose name is app
+//   containers[*]         all items
+//
+// so that, e.g.
+//
+//   spec.template.spec.containers[name=app].env[name=FOO].value
+//
+// is the value of the FOO variable of the app container.
+package fieldpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StepKind is the kind of a step of a path.
+type StepKind int
+
+const (
+	// Key selects the value of a map key.
+	Key StepKind = iota
+	// Index selects a list item by position.
+	Index
+	// Match selects the list items having a field
+	// with a given value.
+	Match
+	// All selects all list items.
+	All
+)
+
+// Step is one step of a path.
+type Step struct {
+	Kind StepKind
+	// Key is the map key of a Key step, or the
+	// field compared by a Match step.
+	Key string
+	// Value is the value compared by a Match step.
+	Value string
+	// Index is the position selected by an Index step.
+	Index int
+}
+
+// Path is a parsed field path.
+type Path []Step
+
+// Parse parses a dot separated path.
+func Parse(path string) (Path, error) {
+	return FromSegments(Split(path, '.'))
+}
+
+// Split splits path on sep, but not on a sep escaped
+// by a backslash, quoted or in brackets.  The backslashes
+// escaping sep are removed.
+func Split(path string, sep rune) []string {
+	var result []string
+	var b strings.Builder
+	var quote rune
+	depth, escaped := 0, false
+	for _, c := range path {
+		switch {
+		case escaped:
+			if c != sep {
+				b.WriteRune('\\')
+			}
+			escaped = false
+		case c == '\\':
+			escaped = true
+			continue
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case depth > 0 && (c == '\'' || c == '"'):
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == sep && depth <= 0:
+			result = append(result, b.String())
+			b.Reset()
+			continue
+		}
+		b.WriteRune(c)
+	}
+	if escaped {
+		b.WriteRune('\\')
+	}
+	return append(result, b.String())
+}
+
+// FromSegments parses the segments of a path, as
+// split by Split, each being a key, its selectors,
+// or both.
+func FromSegments(segments []string) (Path, error) {
+	var result Path
+	for _, s := range segments {
+		steps, err := parseSegment(s)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"invalid field path %q: %v", strings.Join(segments, "."), err)
+		}
+		result = append(result, steps...)
+	}
+	return result, nil
+}
+
+// parseSegment parses one segment of a path; in brackets,
+// a quoted string or a word that's not a number is a key,
+// as in the downward API, e.g. labels['app'].
+func parseSegment(s string) (Path, error) {
+	var result Path
+	key, rest, quoted, err := readKey(s)
+	if err != nil {
+		return nil, err
+	}
+	if key != "" || quoted {
+		result = append(result, Step{Kind: Key, Key: key})
+	} else if rest == "" {
+		return nil, fmt.Errorf("empty key")
+	}
+	for rest != "" {
+		if rest[0] != '[' {
+			return nil, fmt.Errorf("unexpected %q after selector", rest)
+		}
+		end := closingBracket(rest)
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed bracket in %q", s)
+		}
+		step, err := parseSelector(rest[1:end])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, step)
+		rest = rest[end+1:]
+	}
+	return result, nil
+}
+
+// readKey reads the key at the start of s, up to its
+// first selector, unescaping or unquoting it.
+func readKey(s string) (key, rest string, quoted bool, err error) {
+	if len(s) > 0 && (s[0] == '\'' || s[0] == '"') {
+		end := strings.IndexByte(s[1:], s[0])
+		if end < 0 {
+			return "", "", false, fmt.Errorf("unclosed quote in %q", s)
+		}
+		return s[1 : end+1], s[end+2:], true, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+			}
+		case '[':
+			return b.String(), s[i:], false, nil
+		case ']':
+			return "", "", false, fmt.Errorf("unopened bracket in %q", s)
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String(), "", false, nil
+}
+
+// closingBracket returns the index of the bracket
+// closing the one that s starts with, or -1.
+func closingBracket(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '\\':
+			i++
+		case c == '[':
+			return -1
+		case c == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+func parseSelector(s string) (Step, error) {
+	switch {
+	case s == "*" || s == "":
+		return Step{Kind: All}, nil
+	case isQuoted(s):
+		return Step{Kind: Key, Key: s[1 : len(s)-1]}, nil
+	}
+	if kv := strings.SplitN(s, "=", 2); len(kv) == 2 {
+		if kv[0] == "" {
+			return Step{}, fmt.Errorf("empty key in selector %q", s)
+		}
+		v := kv[1]
+		if isQuoted(v) {
+			v = v[1 : len(v)-1]
+		}
+		return Step{Kind: Match, Key: kv[0], Value: v}, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 {
+			return Step{}, fmt.Errorf("negative index %d", n)
+		}
+		return Step{Kind: Index, Index: n}, nil
+	}
+	return Step{Kind: Key, Key: s}, nil
+}
+
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '\'' || s[0] == '"') &&
+		s[len(s)-1] == s[0]
+}
+
+// IsSelector is true for the steps selecting list items.
+func (s Step) IsSelector() bool {
+	return s.Kind != Key
+}
+
+// Selects is true if the selector s selects
+// item, which is at index i of its list.
+func (s Step) Selects(i int, item interface{}) bool {
+	switch s.Kind {
+	case Index:
+		return i == s.Index
+	case Match:
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		v, ok := m[s.Key]
+		return ok && fmt.Sprintf("%v", v) == s.Value
+	case All:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the step as written in a path.
+func (s Step) String() string {
+	switch s.Kind {
+	case Index:
+		return fmt.Sprintf("[%d]", s.Index)
+	case Match:
+		return fmt.Sprintf("[%s=%s]", s.Key, s.Value)
+	case All:
+		return "[*]"
+	}
+	if !s.isBracketed() {
+		return s.Key
+	}
+	if strings.Contains(s.Key, "'") {
+		return `["` + s.Key + `"]`
+	}
+	return "['" + s.Key + "']"
+}
+
+// isBracketed is true for the keys written in brackets.
+func (s Step) isBracketed() bool {
+	return s.Kind == Key &&
+		(s.Key == "" || strings.ContainsAny(s.Key, `./[]\'"`))
+}
+
+// String returns the path, dot separated.
+func (p Path) String() string {
+	var b strings.Builder
+	for i, s := range p {
+		if i > 0 && !s.IsSelector() && !s.isBracketed() {
+			b.WriteByte('.')
+		}
+		b.WriteString(s.String())
+	}
+	return b.String()
+}
@@ -0,0 +1,344 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package fieldpath_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "sigs.k8s.io/kustomize/api/fieldpath"
+	"sigs.k8s.io/yaml"
+)
+
+func key(k string) Step {
+	return Step{Kind: Key, Key: k}
+}
+
+func index(i int) Step {
+	return Step{Kind: Index, Index: i}
+}
+
+func match(k, v string) Step {
+	return Step{Kind: Match, Key: k, Value: v}
+}
+
+func TestParse(t *testing.T) {
+	testCases := map[string]struct {
+		path     string
+		expected Path
+		errMsg   string
+	}{
+		"oneField": {
+			path:     "kind",
+			expected: Path{key("kind")},
+		},
+		"fields": {
+			path:     "spec.ports.port",
+			expected: Path{key("spec"), key("ports"), key("port")},
+		},
+		"index": {
+			path:     "those[1].field2[0]",
+			expected: Path{key("those"), index(1), key("field2"), index(0)},
+		},
+		"sliceInSlice": {
+			path:     "that[1][0]",
+			expected: Path{key("that"), index(1), index(0)},
+		},
+		"match": {
+			path: "spec.template.spec.containers[name=app].env[name=FOO].value",
+			expected: Path{key("spec"), key("template"), key("spec"),
+				key("containers"), match("name", "app"),
+				key("env"), match("name", "FOO"), key("value")},
+		},
+		"matchDottedValue": {
+			path:     "rules[host=a.example.com].http",
+			expected: Path{key("rules"), match("host", "a.example.com"), key("http")},
+		},
+		"matchQuotedValue": {
+			path:     `items[key="x]y"]`,
+			expected: Path{key("items"), match("key", "x]y")},
+		},
+		"wildcard": {
+			path:     "containers[*].image",
+			expected: Path{key("containers"), {Kind: All}, key("image")},
+		},
+		"emptyBrackets": {
+			path:     "containers[].image",
+			expected: Path{key("containers"), {Kind: All}, key("image")},
+		},
+		"escapedDot": {
+			path:     `metadata.annotations.example\.com/owner`,
+			expected: Path{key("metadata"), key("annotations"), key("example.com/owner")},
+		},
+		"downwardAPI": {
+			path:     `metadata.labels["app.kubernetes.io/component"]`,
+			expected: Path{key("metadata"), key("labels"), key("app.kubernetes.io/component")},
+		},
+		"downwardAPIWord": {
+			path:     "this.is[aFloat]",
+			expected: Path{key("this"), key("is"), key("aFloat")},
+		},
+		"quotedFields": {
+			path: "'complextree'[1].field2[1].'stringsubfield'",
+			expected: Path{key("complextree"), index(1),
+				key("field2"), index(1), key("stringsubfield")},
+		},
+		"empty": {
+			path:   "",
+			errMsg: `invalid field path "": empty key`,
+		},
+		"emptyKey": {
+			path:   "metadata..name",
+			errMsg: `invalid field path "metadata..name": empty key`,
+		},
+		"nestedBrackets": {
+			path:   "complextree[1[0]]",
+			errMsg: `unclosed bracket in "complextree[1[0]]"`,
+		},
+		"extraBracket": {
+			path:   "complextree[1]]",
+			errMsg: `unexpected "]" after selector`,
+		},
+		"negativeIndex": {
+			path:   "ports[-1]",
+			errMsg: "negative index -1",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			actual, err := Parse(tc.path)
+			if tc.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+					t.Fatalf("expected error %q, got %v", tc.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Fatalf("expected %#v, got %#v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	testCases := map[string][]string{
+		"spec/metadata/annotations": {"spec", "metadata", "annotations"},
+		`metadata/annotations/nginx.ingress.kubernetes.io\/auth-secret`: {
+			"metadata", "annotations", "nginx.ingress.kubernetes.io/auth-secret"},
+		"spec/containers[name=a/b]/image": {"spec", "containers[name=a/b]", "image"},
+		`data/a\.b`:                       {"data", `a\.b`},
+	}
+	for path, expected := range testCases {
+		actual := Split(path, '/')
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("%s: expected %q, got %q", path, expected, actual)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	for _, path := range []string{
+		"spec.template.spec.containers[name=app].env[name=FOO].value",
+		"spec.ports[0].port",
+		"spec.containers[*].image",
+		"metadata.annotations['example.com/owner']",
+	} {
+		p, err := Parse(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p.String() != path {
+			t.Fatalf("expected %s, got %s", path, p)
+		}
+	}
+}
+
+const deployment = `
+metadata:
+  name: app
+  annotations:
+    example.com/owner: team-a
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v1
+        env:
+        - name: FOO
+          value: foo
+        - name: BAR
+          value: bar
+      - name: sidecar
+        image: sidecar:v1
+        env:
+        - name: FOO
+          value: foo
+`
+
+func makeObj(t *testing.T) map[string]interface{} {
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal([]byte(deployment), &obj); err != nil {
+		t.Fatal(err)
+	}
+	return obj
+}
+
+func mustParse(t *testing.T, path string) Path {
+	p, err := Parse(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return p
+}
+
+func TestGet(t *testing.T) {
+	obj := makeObj(t)
+	testCases := map[string]struct {
+		expected interface{}
+		errMsg   string
+	}{
+		"spec.template.spec.containers[name=app].env[name=FOO].value": {
+			expected: "foo",
+		},
+		"spec.template.spec.containers[1].image": {
+			expected: "sidecar:v1",
+		},
+		`metadata.annotations.example\.com/owner`: {
+			expected: "team-a",
+		},
+		"spec.template.spec.containers[name=other].image": {
+			errMsg: `no item of "containers" has name=other`,
+		},
+		"spec.template.spec.containers[2].image": {
+			errMsg: `index 2 out of bounds of "containers"`,
+		},
+		"spec.template.spec.containers[*].image": {
+			errMsg: "spec.template.spec.containers[*].image selects 2 fields",
+		},
+		"spec.template.spec.containers.image": {
+			errMsg: `"containers" is not a map`,
+		},
+		"metadata.name[0]": {
+			errMsg: `"name" is not a list`,
+		},
+		"spec.replicas": {
+			errMsg: `field "replicas" not found`,
+		},
+	}
+	for path, tc := range testCases {
+		actual, err := mustParse(t, path).Get(obj)
+		if tc.errMsg != "" {
+			if err == nil || err.Error() != tc.errMsg {
+				t.Fatalf("%s: expected error %q, got %v", path, tc.errMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", path, err)
+		}
+		if actual != tc.expected {
+			t.Fatalf("%s: expected %v, got %v", path, tc.expected, actual)
+		}
+	}
+}
+
+func set(v interface{}) MutateFunc {
+	return func(interface{}) (interface{}, error) {
+		return v, nil
+	}
+}
+
+func TestSet(t *testing.T) {
+	obj := makeObj(t)
+	err := mustParse(t, "spec.template.spec.containers[*].env[name=FOO].value").
+		Set(obj, false, set("x"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, path := range []string{
+		"spec.template.spec.containers[name=app].env[name=FOO].value",
+		"spec.template.spec.containers[name=sidecar].env[name=FOO].value",
+	} {
+		if v, _ := mustParse(t, path).Get(obj); v != "x" {
+			t.Fatalf("%s: expected x, got %v", path, v)
+		}
+	}
+	if v, _ := mustParse(t, "spec.template.spec.containers[0].env[1].value").
+		Get(obj); v != "bar" {
+		t.Fatalf("expected bar, got %v", v)
+	}
+
+	err = mustParse(t, "spec.strategy.type").Set(obj, false, set("Recreate"))
+	if err == nil || err.Error() != `field "strategy" not found` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var old interface{} = "unset"
+	err = mustParse(t, "spec.strategy.type").Set(obj, true,
+		func(v interface{}) (interface{}, error) {
+			old = v
+			return "Recreate", nil
+		})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if old != nil {
+		t.Fatalf("expected nil for a created field, got %v", old)
+	}
+	if v, _ := mustParse(t, "spec.strategy.type").Get(obj); v != "Recreate" {
+		t.Fatalf("expected Recreate, got %v", v)
+	}
+}
+
+func TestMutate(t *testing.T) {
+	obj := makeObj(t)
+	// Lists are traversed by keys, and missing
+	// fields are skipped.
+	p, err := FromSegments(
+		[]string{"spec", "template", "spec", "containers", "env[name=BAR]", "value"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = p.Mutate(obj, false, set("x")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, _ := mustParse(t, "spec.template.spec.containers[0].env[1].value").
+		Get(obj); v != "x" {
+		t.Fatalf("expected x, got %v", v)
+	}
+	if err = mustParse(t, "spec.replicas.count").Mutate(obj, false, set(1)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = mustParse(t, "spec.replicas").Get(obj); err == nil {
+		t.Fatalf("expected spec.replicas not to be created")
+	}
+
+	// Keys, but not list items, are created.
+	var created interface{}
+	err = mustParse(t, "metadata.labels").Mutate(obj, true,
+		func(v interface{}) (interface{}, error) {
+			created = v
+			return map[string]interface{}{"app": "a"}, nil
+		})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(created, map[string]interface{}{}) {
+		t.Fatalf("expected an empty map for a created field, got %v", created)
+	}
+	if v, _ := mustParse(t, "metadata.labels.app").Get(obj); v != "a" {
+		t.Fatalf("expected a, got %v", v)
+	}
+	err = mustParse(t, "spec.volumes[name=data].name").Mutate(obj, true, set("x"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = mustParse(t, "spec.volumes").Get(obj); err == nil {
+		t.Fatalf("expected spec.volumes not to be created")
+	}
+}
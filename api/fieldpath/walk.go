@@ -0,0 +1,169 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package fieldpath
+
+import (
+	"fmt"
+	"log"
+)
+
+// MutateFunc returns the new value of a field, given its old value.
+type MutateFunc func(interface{}) (interface{}, error)
+
+// Get returns the value of the field that p selects in obj,
+// or an error if there's no such field, or more than one.
+func (p Path) Get(obj map[string]interface{}) (interface{}, error) {
+	var result []interface{}
+	err := p.Set(obj, false, func(v interface{}) (interface{}, error) {
+		result = append(result, v)
+		return v, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result) != 1 {
+		return nil, fmt.Errorf("%s selects %d fields", p, len(result))
+	}
+	return result[0], nil
+}
+
+// Set replaces the value of each field that p selects in
+// obj with the result of f.  It's an error for a field on
+// the path to be missing, unless create is true, in which
+// case missing maps are created, and the value of a missing
+// field passed to f is nil.  List items are only reached by
+// selectors.
+func (p Path) Set(
+	obj map[string]interface{}, create bool, f MutateFunc) error {
+	w := &walker{f: f, create: create, strict: true}
+	_, err := w.walk(obj, p, "")
+	return err
+}
+
+// Mutate replaces the value of each field that p selects
+// in obj with the result of f, as transformers do with the
+// fields of their fieldSpecs.  A key applied to a list is
+// applied to each of its items.  Missing fields, and fields
+// of unexpected types, are skipped, unless create is true,
+// in which case missing keys are created with an empty map
+// as their value, and passed to f as such.  Only keys are
+// created, not list items.
+func (p Path) Mutate(
+	obj map[string]interface{}, create bool, f MutateFunc) error {
+	w := &walker{f: f, create: create}
+	_, err := w.walk(obj, p, "")
+	return err
+}
+
+type walker struct {
+	f      MutateFunc
+	create bool
+	// strict makes missing fields and fields of
+	// unexpected types errors, rather than skipped.
+	strict bool
+}
+
+// walk returns the value of v with the fields at
+// path replaced; name is that of the field holding v.
+func (w *walker) walk(
+	v interface{}, path Path, name string) (interface{}, error) {
+	if len(path) == 0 {
+		return w.f(v)
+	}
+	step := path[0]
+	if step.IsSelector() {
+		return w.walkItems(v, path, name)
+	}
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		return typed, w.walkKey(typed, path)
+	case []interface{}:
+		if w.strict {
+			return nil, fmt.Errorf("%q is not a map", name)
+		}
+		for i := range typed {
+			item, ok := typed[i].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%#v is expected to be %T", typed[i], item)
+			}
+			if err := w.walkKey(item, path); err != nil {
+				return nil, err
+			}
+		}
+		return typed, nil
+	case nil:
+		if w.strict {
+			return nil, fmt.Errorf("%q is not a map", name)
+		}
+		log.Printf("nil value at `%s` ignored in mutation attempt", name)
+		return v, nil
+	default:
+		// If an inline could not be resolved, a field that was supposed to map or slice
+		// is still in the tree as a string, a.k.a "field: $(INLINE)"
+		// Hence we should not return an error and not attempt to mutate the field.
+		if w.strict {
+			return nil, fmt.Errorf("%q is not a map", name)
+		}
+		return v, nil
+	}
+}
+
+func (w *walker) walkKey(m map[string]interface{}, path Path) error {
+	key := path[0].Key
+	v, found := m[key]
+	if !found {
+		// Keys preceding selectors aren't created,
+		// as they'd hold lists, which have no items.
+		if !w.create || (len(path) > 1 && path[1].IsSelector()) {
+			if w.strict {
+				return fmt.Errorf("field %q not found", key)
+			}
+			return nil
+		}
+		if len(path) > 1 || !w.strict {
+			v = map[string]interface{}{}
+		}
+	}
+	v, err := w.walk(v, path[1:], key)
+	if err != nil {
+		return err
+	}
+	m[key] = v
+	return nil
+}
+
+func (w *walker) walkItems(
+	v interface{}, path Path, name string) (interface{}, error) {
+	step := path[0]
+	l, ok := v.([]interface{})
+	if !ok {
+		if w.strict {
+			return nil, fmt.Errorf("%q is not a list", name)
+		}
+		return v, nil
+	}
+	found := false
+	for i := range l {
+		if !step.Selects(i, l[i]) {
+			continue
+		}
+		found = true
+		item, err := w.walk(l[i], path[1:], fmt.Sprintf("%s%s", name, step))
+		if err != nil {
+			return nil, err
+		}
+		l[i] = item
+	}
+	if !found && w.strict {
+		switch step.Kind {
+		case Index:
+			return nil, fmt.Errorf(
+				"index %d out of bounds of %q", step.Index, name)
+		case Match:
+			return nil, fmt.Errorf(
+				"no item of %q has %s=%s", name, step.Key, step.Value)
+		}
+	}
+	return l, nil
+}
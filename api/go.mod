@@ -3,17 +3,21 @@ module sigs.k8s.io/kustomize/api
 go 1.13
 
 require (
+	filippo.io/age v1.0.0
 	github.com/evanphx/json-patch v4.5.0+incompatible
-	github.com/go-openapi/spec v0.19.4
+	github.com/go-git/go-git/v5 v5.1.0
+	github.com/go-openapi/spec v0.19.5
 	github.com/google/shlex v0.0.0-20191202100458-e7afc7fbc510
-	github.com/pkg/errors v0.8.1
+	github.com/pkg/errors v0.9.1
 	github.com/yujunz/go-getter v1.4.1-lite
-	golang.org/x/sys v0.0.0-20190922100055-0a153f010e69 // indirect
-	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
-	gopkg.in/yaml.v2 v2.2.4
+	go.mozilla.org/sops/v3 v3.7.3
+	golang.org/x/crypto v0.0.0-20220411220226-7b82a4e95df4
+	gopkg.in/yaml.v2 v2.4.0
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 	k8s.io/api v0.17.0
 	k8s.io/apimachinery v0.17.0
 	k8s.io/client-go v0.17.0
 	k8s.io/kube-openapi v0.0.0-20191107075043-30be4d16710a
+	sigs.k8s.io/kustomize/kyaml v0.1.3
 	sigs.k8s.io/yaml v1.1.0
 )
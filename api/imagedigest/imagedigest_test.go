@@ -0,0 +1,200 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package imagedigest_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	. "sigs.k8s.io/kustomize/api/imagedigest"
+	"sigs.k8s.io/kustomize/api/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	registrytest_test "sigs.k8s.io/kustomize/api/testutils/registrytest"
+	resmaptest_test "sigs.k8s.io/kustomize/api/testutils/resmaptest"
+)
+
+func TestParseReference(t *testing.T) {
+	testCases := map[string]Reference{
+		"nginx": {
+			Registry: "docker.io", Repository: "library/nginx", Tag: "latest"},
+		"nginx:1.19": {
+			Registry: "docker.io", Repository: "library/nginx", Tag: "1.19"},
+		"someteam/app:v1": {
+			Registry: "docker.io", Repository: "someteam/app", Tag: "v1"},
+		"gcr.io/project/app": {
+			Registry: "gcr.io", Repository: "project/app", Tag: "latest"},
+		"localhost/app:v1": {
+			Registry: "localhost", Repository: "app", Tag: "v1"},
+		"127.0.0.1:5000/team/app:v1": {
+			Registry: "127.0.0.1:5000", Repository: "team/app", Tag: "v1"},
+		"app@sha256:abc123": {
+			Registry: "docker.io", Repository: "library/app", Digest: "sha256:abc123"},
+		"app:v1@sha256:abc123": {
+			Registry: "docker.io", Repository: "library/app",
+			Tag: "v1", Digest: "sha256:abc123"},
+	}
+	for image, expected := range testCases {
+		actual, err := ParseReference(image)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", image, err)
+		}
+		if actual != expected {
+			t.Fatalf("%s: expected %+v, got %+v", image, expected, actual)
+		}
+	}
+	for _, image := range []string{"", ":v1", "app@sha256"} {
+		if _, err := ParseReference(image); err == nil {
+			t.Fatalf("%q: expected error", image)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	reg := registrytest_test.NewRegistry()
+	defer reg.Close()
+	digest := reg.Push("team/app", "v1")
+	r := NewResolver()
+	for _, tc := range []struct {
+		name         string
+		requireToken bool
+		omitDigest   bool
+	}{
+		{name: "head"},
+		{name: "token", requireToken: true},
+		{name: "body", omitDigest: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reg.RequireToken, reg.OmitDigest = tc.requireToken, tc.omitDigest
+			d, err := r.Resolve(reg.Host + "/team/app:v1")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if d != digest {
+				t.Fatalf("expected %s, got %s", digest, d)
+			}
+		})
+	}
+	_, err := r.Resolve(reg.Host + "/team/app:v2")
+	if err == nil || !strings.Contains(err.Error(),
+		"resolving digest of image "+reg.Host+"/team/app:v2") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLockFile(t *testing.T) {
+	reg := registrytest_test.NewRegistry()
+	defer reg.Close()
+	digest := reg.Push("app", "v1")
+	image := reg.Host + "/app:v1"
+	fSys := filesys.MakeFsInMemory()
+
+	l, err := ReadLockFile(fSys, "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := l.Resolve(NewResolver(), image)
+	if err != nil || d != digest {
+		t.Fatalf("expected %s, got %s, %v", digest, d, err)
+	}
+	l.Set("nginx:1.19", "sha256:0123")
+	if err = l.Write(fSys, "/app"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := fSys.ReadFile("/app/" + LockFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `images:
+- digest: ` + digest + `
+  image: ` + image + `
+- digest: sha256:0123
+  image: nginx:1.19
+`
+	if string(b) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b)
+	}
+
+	l, err = ReadLockFile(fSys, "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := reg.Requests()
+	d, err = l.Resolve(NewResolver(), image)
+	if err != nil || d != digest {
+		t.Fatalf("expected %s, got %s, %v", digest, d, err)
+	}
+	if reg.Requests() != requests {
+		t.Fatalf("expected the locked digest to be used")
+	}
+}
+
+var resourceFactory = resource.NewFactory(kunstruct.NewKunstructuredFactoryImpl())
+
+func makeResMap(t *testing.T) resmap.ResMap {
+	return resmaptest_test.NewRmBuilder(t, resourceFactory).
+		Add(map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "deploy"},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"initContainers": []interface{}{
+							map[string]interface{}{
+								"name":  "init",
+								"image": "busybox@sha256:beef",
+							},
+						},
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "app",
+								"image": "nginx:1.19",
+							},
+						},
+					},
+				},
+			},
+		}).
+		Add(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "cm"},
+			"data":       map[string]interface{}{"image": "nginx:1.19"},
+		}).ResMap()
+}
+
+func TestPinAndCheck(t *testing.T) {
+	m := makeResMap(t)
+	err := Check(m)
+	if err == nil || err.Error() != `images not pinned by digest:
+  apps_v1_Deployment|~X|deploy: spec.template.spec.containers[0].image: nginx:1.19` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var resolved []string
+	err = Pin(m, func(image string) (string, error) {
+		resolved = append(resolved, image)
+		return "sha256:0123", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resolved) != 1 || resolved[0] != "nginx:1.19" {
+		t.Fatalf("unexpected resolutions: %v", resolved)
+	}
+	if err = Check(m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	images := Images(m)
+	if len(images) != 1 {
+		t.Fatalf("unexpected images: %v", images)
+	}
+	for _, fields := range images {
+		if fields[0].Image != "nginx@sha256:0123" ||
+			fields[1].Image != "busybox@sha256:beef" {
+			t.Fatalf("unexpected images: %v", fields)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package imagedigest
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/yaml"
+)
+
+// LockFileName is the name of the lock file, kept
+// next to the kustomization file.
+const LockFileName = "images.lock"
+
+// LockFile records the digests that image tags
+// were resolved to.
+type LockFile struct {
+	Images []LockedImage `json:"images,omitempty" yaml:"images,omitempty"`
+
+	// Set when entries were added since reading.
+	changed bool
+}
+
+// LockedImage is one resolution of an image tag.
+type LockedImage struct {
+	// Image as written in a resource, e.g. nginx:1.19
+	Image  string `json:"image" yaml:"image"`
+	Digest string `json:"digest" yaml:"digest"`
+}
+
+// ReadLockFile reads the lock file in dir; if there's
+// none, it returns an empty one.
+func ReadLockFile(fSys filesys.FileSystem, dir string) (*LockFile, error) {
+	l := &LockFile{}
+	path := filepath.Join(dir, LockFileName)
+	if !fSys.Exists(path) {
+		return l, nil
+	}
+	b, err := fSys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.Unmarshal(b, l); err != nil {
+		return nil, errors.Wrapf(err, "reading lock file '%s'", path)
+	}
+	return l, nil
+}
+
+// Digest returns the digest recorded for image.
+func (l *LockFile) Digest(image string) (string, bool) {
+	for _, e := range l.Images {
+		if e.Image == image {
+			return e.Digest, true
+		}
+	}
+	return "", false
+}
+
+// Set records the digest of image.
+func (l *LockFile) Set(image, digest string) {
+	for i, e := range l.Images {
+		if e.Image == image {
+			if e.Digest != digest {
+				l.Images[i].Digest = digest
+				l.changed = true
+			}
+			return
+		}
+	}
+	l.Images = append(l.Images, LockedImage{Image: image, Digest: digest})
+	sort.Slice(l.Images, func(i, j int) bool {
+		return l.Images[i].Image < l.Images[j].Image
+	})
+	l.changed = true
+}
+
+// Write writes the lock file to dir, if it changed.
+func (l *LockFile) Write(fSys filesys.FileSystem, dir string) error {
+	if !l.changed {
+		return nil
+	}
+	b, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	if err = fSys.WriteFile(filepath.Join(dir, LockFileName), b); err != nil {
+		return err
+	}
+	l.changed = false
+	return nil
+}
+
+// Resolve returns the digest of image, from the lock
+// file if recorded there, else from the resolver, in
+// which case it's recorded.
+func (l *LockFile) Resolve(r *Resolver, image string) (string, error) {
+	if d, ok := l.Digest(image); ok {
+		return d, nil
+	}
+	d, err := r.Resolve(image)
+	if err != nil {
+		return "", err
+	}
+	l.Set(image, d)
+	return d, nil
+}
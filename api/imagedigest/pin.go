@@ -0,0 +1,145 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package imagedigest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+// The fields holding lists of containers, wherever
+// they are in a resource, as for the images transformer.
+var containerFields = []string{
+	"containers", "initContainers", "ephemeralContainers"}
+
+// ImageField is an image of a container in a resource.
+type ImageField struct {
+	// Path of the field, e.g. spec.template.spec.containers[0].image
+	Path  string
+	Image string
+
+	set func(string)
+}
+
+// Images returns the image fields of the resources in m,
+// by resource.
+func Images(m resmap.ResMap) map[string][]ImageField {
+	result := make(map[string][]ImageField)
+	for _, r := range m.Resources() {
+		var fields []ImageField
+		findImages(r.Map(), "", &fields)
+		if len(fields) > 0 {
+			result[r.CurId().String()] = fields
+		}
+	}
+	return result
+}
+
+func findImages(obj map[string]interface{}, path string, fields *[]ImageField) {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		switch v := obj[k].(type) {
+		case map[string]interface{}:
+			findImages(v, p, fields)
+		case []interface{}:
+			isContainers := isContainerField(k)
+			for i, e := range v {
+				c, ok := e.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				ep := fmt.Sprintf("%s[%d]", p, i)
+				if image, ok := c["image"].(string); ok && isContainers {
+					*fields = append(*fields, ImageField{
+						Path:  ep + ".image",
+						Image: image,
+						set:   func(s string) { c["image"] = s },
+					})
+					continue
+				}
+				findImages(c, ep, fields)
+			}
+		}
+	}
+}
+
+func isContainerField(k string) bool {
+	for _, f := range containerFields {
+		if k == f {
+			return true
+		}
+	}
+	return false
+}
+
+// Pin replaces the tag of each container image in m
+// with the digest that resolve returns for the image.
+func Pin(m resmap.ResMap, resolve func(image string) (string, error)) error {
+	for _, fields := range Images(m) {
+		for _, f := range fields {
+			ref, err := ParseReference(f.Image)
+			if err != nil {
+				return err
+			}
+			if ref.IsPinned() {
+				continue
+			}
+			d, err := resolve(f.Image)
+			if err != nil {
+				return err
+			}
+			f.set(WithDigest(f.Image, d))
+		}
+	}
+	return nil
+}
+
+// WithDigest returns image with its tag, if any,
+// replaced by digest.
+func WithDigest(image, digest string) string {
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+	return name + "@" + digest
+}
+
+// Check returns an error listing each container image
+// in m that isn't pinned by digest.
+func Check(m resmap.ResMap) error {
+	images := Images(m)
+	ids := make([]string, 0, len(images))
+	for id := range images {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var b strings.Builder
+	for _, id := range ids {
+		for _, f := range images[id] {
+			ref, err := ParseReference(f.Image)
+			if err == nil && ref.IsPinned() {
+				continue
+			}
+			fmt.Fprintf(&b, "\n  %s: %s: %s", id, f.Path, f.Image)
+		}
+	}
+	if b.Len() == 0 {
+		return nil
+	}
+	return fmt.Errorf("images not pinned by digest:%s", b.String())
+}
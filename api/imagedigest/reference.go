@@ -0,0 +1,85 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package imagedigest pins container images by digest,
+// resolving tags with the OCI distribution API of their
+// registries, and recording the resolutions in a lock file
+// so that later builds are reproducible, and offline.
+package imagedigest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	dockerHub         = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+	defaultTag        = "latest"
+)
+
+var digestPattern = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-zA-Z0-9=_-]+$`)
+
+// Reference is a parsed image reference, e.g.
+// gcr.io/project/app:v1 or nginx@sha256:...
+type Reference struct {
+	// Registry host, and port if any, e.g. gcr.io.
+	Registry string
+	// Repository in the registry, e.g. project/app.
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference parses an image reference, filling
+// in the registry and tag defaults as docker does.
+func ParseReference(image string) (Reference, error) {
+	var r Reference
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		r.Digest = name[i+1:]
+		name = name[:i]
+		if !digestPattern.MatchString(r.Digest) {
+			return r, fmt.Errorf("invalid digest in image %q", image)
+		}
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		r.Tag = name[i+1:]
+		name = name[:i]
+	}
+	if name == "" {
+		return r, fmt.Errorf("invalid image %q", image)
+	}
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") ||
+		parts[0] == "localhost") {
+		r.Registry, r.Repository = parts[0], parts[1]
+	} else {
+		r.Registry, r.Repository = dockerHub, name
+	}
+	if r.Registry == dockerHub && !strings.Contains(r.Repository, "/") {
+		r.Repository = "library/" + r.Repository
+	}
+	if r.Tag == "" && r.Digest == "" {
+		r.Tag = defaultTag
+	}
+	return r, nil
+}
+
+// IsPinned is true if the reference has a digest.
+func (r Reference) IsPinned() bool {
+	return r.Digest != ""
+}
+
+// String returns the reference in its full form.
+func (r Reference) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
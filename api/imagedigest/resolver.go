@@ -0,0 +1,201 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package imagedigest
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The manifest types a tag may refer to, preferring
+// those of multi-platform images, whose digest is the
+// one to pin.
+var manifestTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// defaultClient makes the requests of a Resolver without
+// a Client, giving up on registries that don't answer.
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
+// Resolver resolves image tags to digests with the
+// OCI distribution API.  Registries on the loopback
+// interface, as for docker, are reached by plain HTTP,
+// others by HTTPS.  Anonymous bearer tokens are
+// fetched as registries ask for them.
+type Resolver struct {
+	// Client makes the requests; if nil, a client
+	// whose requests time out after 30 seconds.
+	Client *http.Client
+}
+
+// NewResolver returns a Resolver using the default client.
+func NewResolver() *Resolver {
+	return &Resolver{}
+}
+
+// Resolve returns the digest of the manifest that
+// the tag of the given image refers to.
+func (r *Resolver) Resolve(image string) (string, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	if ref.IsPinned() {
+		return ref.Digest, nil
+	}
+	d, err := r.resolve(ref)
+	if err != nil {
+		return "", errors.Wrapf(err, "resolving digest of image %s", image)
+	}
+	return d, nil
+}
+
+func (r *Resolver) resolve(ref Reference) (string, error) {
+	u := fmt.Sprintf("%s/v2/%s/manifests/%s",
+		baseURL(ref.Registry), ref.Repository, ref.Tag)
+	resp, err := r.get(http.MethodHead, u, "")
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := r.token(resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return "", err
+		}
+		resp, err = r.get(http.MethodHead, u, token)
+		if err != nil {
+			return "", err
+		}
+		resp.Body.Close()
+		if d := resp.Header.Get("Docker-Content-Digest"); d != "" &&
+			resp.StatusCode == http.StatusOK {
+			return d, nil
+		}
+		return r.digestOfBody(u, token)
+	}
+	if d := resp.Header.Get("Docker-Content-Digest"); d != "" &&
+		resp.StatusCode == http.StatusOK {
+		return d, nil
+	}
+	return r.digestOfBody(u, "")
+}
+
+// digestOfBody gets the manifest, for registries that
+// don't report its digest, or don't answer HEAD.
+func (r *Resolver) digestOfBody(u, token string) (string, error) {
+	resp, err := r.get(http.MethodGet, u, token)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s %s: %s", http.MethodGet, u, resp.Status)
+	}
+	if d := resp.Header.Get("Docker-Content-Digest"); d != "" {
+		return d, nil
+	}
+	h := sha256.New()
+	if _, err = io.Copy(h, resp.Body); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
+
+func (r *Resolver) get(method, u, token string) (*http.Response, error) {
+	req, err := http.NewRequest(method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	c := r.Client
+	if c == nil {
+		c = defaultClient
+	}
+	return c.Do(req)
+}
+
+// token gets an anonymous token per the challenge of
+// a registry, e.g.
+// Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull"
+func (r *Resolver) token(challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+	params := make(map[string]string)
+	for _, p := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(p), "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("bad realm in authentication challenge %q", challenge)
+	}
+	q := realm.Query()
+	for _, k := range []string{"service", "scope"} {
+		if v, ok := params[k]; ok {
+			q.Set(k, v)
+		}
+	}
+	realm.RawQuery = q.Encode()
+	resp, err := r.get(http.MethodGet, realm.String(), "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("getting token from %s: %s", realm, resp.Status)
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	var t struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.Unmarshal(b, &t); err != nil {
+		return "", errors.Wrapf(err, "reading token from %s", realm)
+	}
+	if t.Token == "" {
+		t.Token = t.AccessToken
+	}
+	return t.Token, nil
+}
+
+func baseURL(registry string) string {
+	if registry == dockerHub {
+		return "https://" + dockerHubRegistry
+	}
+	host := registry
+	if h, _, err := net.SplitHostPort(registry); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return "http://" + registry
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return "http://" + registry
+	}
+	return "https://" + registry
+}
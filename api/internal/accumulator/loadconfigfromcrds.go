@@ -4,11 +4,14 @@
 package accumulator
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
 	"strings"
 
 	"github.com/go-openapi/spec"
 	"github.com/pkg/errors"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/kube-openapi/pkg/common"
 	"sigs.k8s.io/kustomize/api/filesys"
 	"sigs.k8s.io/kustomize/api/ifc"
@@ -46,7 +49,54 @@ func LoadConfigFromCRDs(
 	return tc, nil
 }
 
+// LoadSchemasFromCRDs parses the CRD files at the given paths,
+// returning the OpenAPI definitions they hold, by type name.
+func LoadSchemasFromCRDs(
+	ldr ifc.Loader, paths []string) (spec.Definitions, error) {
+	result := spec.Definitions{}
+	for _, path := range paths {
+		content, err := ldr.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		m, err := makeNameToApiMap(content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse open API definition from '%s'", path)
+		}
+		for name, api := range m {
+			result[name] = api.Schema
+		}
+	}
+	return result, nil
+}
+
+// LoadSchemasFromOpenAPI parses the file of OpenAPI definitions,
+// such as a cluster serves at /openapi/v2, at the given path,
+// returning its definitions, by type name.
+func LoadSchemasFromOpenAPI(
+	ldr ifc.Loader, path string) (spec.Definitions, error) {
+	content, err := ldr.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Definitions spec.Definitions `json:"definitions"`
+	}
+	err = yaml.Unmarshal(content, &doc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse open API definitions from '%s'", path)
+	}
+	return doc.Definitions, nil
+}
+
+// makeNameToApiMap parses either a map of OpenAPI definitions,
+// by Go type name, as made by kube-openapi, or CustomResourceDefinition
+// documents, whose schemas are named by apiVersion and kind,
+// e.g. example.com/v1.Bee.
 func makeNameToApiMap(content []byte) (result nameToApiMap, err error) {
+	if isCrdDocument(content) {
+		return makeNameToApiMapFromCrds(content)
+	}
 	if content[0] == '{' {
 		err = json.Unmarshal(content, &result)
 	} else {
@@ -55,6 +105,93 @@ func makeNameToApiMap(content []byte) (result nameToApiMap, err error) {
 	return
 }
 
+// crd holds the fields of a CustomResourceDefinition,
+// v1 or v1beta1, that hold its schemas.
+type crd struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Group string `json:"group"`
+		Names struct {
+			Kind string `json:"kind"`
+		} `json:"names"`
+		// Version and Validation are those of v1beta1.
+		Version    string         `json:"version"`
+		Validation *crdValidation `json:"validation"`
+		Versions   []struct {
+			Name   string         `json:"name"`
+			Schema *crdValidation `json:"schema"`
+		} `json:"versions"`
+	} `json:"spec"`
+}
+
+type crdValidation struct {
+	OpenAPIV3Schema *spec.Schema `json:"openAPIV3Schema"`
+}
+
+func isCrdDocument(content []byte) bool {
+	var c crd
+	err := k8syaml.NewYAMLOrJSONDecoder(
+		bytes.NewReader(content), 1024).Decode(&c)
+	return err == nil && c.Kind == "CustomResourceDefinition"
+}
+
+func makeNameToApiMapFromCrds(content []byte) (nameToApiMap, error) {
+	result := nameToApiMap{}
+	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 1024)
+	for {
+		var c crd
+		err := decoder.Decode(&c)
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if c.Kind != "CustomResourceDefinition" {
+			continue
+		}
+		versions := map[string]*crdValidation{}
+		if c.Spec.Version != "" {
+			versions[c.Spec.Version] = c.Spec.Validation
+		}
+		for _, v := range c.Spec.Versions {
+			versions[v.Name] = c.Spec.Validation
+			if v.Schema != nil {
+				versions[v.Name] = v.Schema
+			}
+		}
+		for version, v := range versions {
+			if v == nil || v.OpenAPIV3Schema == nil {
+				continue
+			}
+			name := c.Spec.Group + "/" + version + "." + c.Spec.Names.Kind
+			result[name] = common.OpenAPIDefinition{
+				Schema: withTypeMeta(*v.OpenAPIV3Schema)}
+		}
+	}
+}
+
+// withTypeMeta returns the schema of a CRD, which
+// needn't mention apiVersion, kind and metadata,
+// with them, so that it looks like a k8s type.
+func withTypeMeta(s spec.Schema) spec.Schema {
+	props := map[string]spec.Schema{}
+	for name, p := range s.Properties {
+		props[name] = p
+	}
+	for _, name := range []string{"apiVersion", "kind"} {
+		if _, ok := props[name]; !ok {
+			props[name] = *spec.StringProperty()
+		}
+	}
+	if _, ok := props["metadata"]; !ok {
+		props["metadata"] = spec.Schema{
+			SchemaProps: spec.SchemaProps{Type: []string{"object"}}}
+	}
+	s.Properties = props
+	return s
+}
+
 func makeConfigFromApiMap(m nameToApiMap) (*builtinconfig.TransformerConfig, error) {
 	result := builtinconfig.MakeEmptyConfig()
 	for name, api := range m {
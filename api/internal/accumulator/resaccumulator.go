@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/go-openapi/spec"
 	"sigs.k8s.io/kustomize/api/internal/plugins/builtinconfig"
 	"sigs.k8s.io/kustomize/api/resid"
 	"sigs.k8s.io/kustomize/api/resmap"
@@ -25,6 +26,7 @@ type ResAccumulator struct {
 	varSet         types.VarSet
 	patchSet       []types.Patch
 	unresolvedVars types.VarSet
+	crdSchemas     spec.Definitions
 }
 
 func MakeEmptyAccumulator() *ResAccumulator {
@@ -34,6 +36,7 @@ func MakeEmptyAccumulator() *ResAccumulator {
 	ra.varSet = types.NewVarSet()
 	ra.patchSet = []types.Patch{}
 	ra.unresolvedVars = types.NewVarSet()
+	ra.crdSchemas = spec.Definitions{}
 	return ra
 }
 
@@ -145,6 +148,20 @@ func (ra *ResAccumulator) GetTransformerConfig() *builtinconfig.TransformerConfi
 	return ra.tConfig
 }
 
+// MergeCrdSchemas adds the given OpenAPI definitions, by
+// type name, to those of the CRDs accumulated so far.
+func (ra *ResAccumulator) MergeCrdSchemas(defs spec.Definitions) {
+	for name, s := range defs {
+		ra.crdSchemas[name] = s
+	}
+}
+
+// CrdSchemas returns the OpenAPI definitions, by type
+// name, of the CRDs accumulated so far.
+func (ra *ResAccumulator) CrdSchemas() spec.Definitions {
+	return ra.crdSchemas
+}
+
 // AppendUnresolvedVars accumulates the non conflicting and unresolved variables
 // from one accumulator into another. Returns an error if a conflict is detected.
 func (ra *ResAccumulator) AppendUnresolvedVars(otherSet types.VarSet) error {
@@ -266,6 +283,7 @@ func (ra *ResAccumulator) MergeAccumulator(other *ResAccumulator) error {
 	if err != nil {
 		return err
 	}
+	ra.MergeCrdSchemas(other.crdSchemas)
 	err = ra.AppendUnresolvedVars(other.unresolvedVars)
 	if err != nil {
 		return err
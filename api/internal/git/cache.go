@@ -0,0 +1,231 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// A ref naming a commit, which, unlike a branch,
+// can't come to name some other commit.
+var commitHash = regexp.MustCompile("^[0-9a-fA-F]{7,40}$")
+
+// CachingCloner returns a cloner that copies clones from the
+// cache configured by c, and that uses the given cloner to
+// fetch, and add to the cache, repos that aren't cached or
+// that need refreshing.
+//
+// The clone handed to the loader is always a copy, so that
+// the loader may remove it as usual, and so that pruning
+// the cache can't disturb a build in progress.
+func CachingCloner(c *types.GitCacheConfig, fetch Cloner) Cloner {
+	return func(rs *RepoSpec) error {
+		entry := CacheEntry(c.AbsCacheDir, rs)
+		_, err := os.Stat(entry)
+		cached := err == nil
+		if cached && (c.Offline || !c.Refresh || commitHash.MatchString(rs.Ref)) {
+			return useCacheEntry(entry, rs)
+		}
+		if c.Offline {
+			return fmt.Errorf(
+				"offline, and %s at ref '%s' isn't in the cache at '%s'",
+				rs.CloneSpec(), rs.Ref, c.AbsCacheDir)
+		}
+		err = fetch(rs)
+		if err != nil {
+			return err
+		}
+		return errors.Wrapf(
+			storeCacheEntry(rs.Dir.String(), entry),
+			"caching %s", rs.CloneSpec())
+	}
+}
+
+// CacheEntry returns the directory in the given cache that
+// holds the clone of the repo, at the ref, named by the spec.
+// Entries are always three levels down, e.g.
+//   github.com/someOrg%2FsomeRepo/v1.0.0
+func CacheEntry(cacheDir string, rs *RepoSpec) string {
+	ref := rs.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	return filepath.Join(
+		cacheDir, hostKey(rs.Host),
+		entryKey(strings.Trim(rs.OrgRepo, "/")),
+		entryKey(ref))
+}
+
+// entryKey escapes s to a single directory name.  Names
+// never start with a dot, so can't be . or .., which would
+// take an entry out of its place, e.g. for ?ref=.., nor
+// be mistaken for a temporary directory made by
+// storeCacheEntry.  An empty s becomes %, which escaping
+// never otherwise yields.
+func entryKey(s string) string {
+	k := url.PathEscape(s)
+	if strings.HasPrefix(k, ".") {
+		k = "%2E" + k[1:]
+	}
+	if k == "" {
+		k = "%"
+	}
+	return k
+}
+
+// hostKey reduces a host, like git@github.com: or
+// https://github.com/, to a directory name.  Since a
+// repo's content doesn't depend on the protocol used
+// to clone it, the protocol is dropped.
+func hostKey(host string) string {
+	h := strings.TrimPrefix(strings.ToLower(host), "git::")
+	if i := strings.Index(h, "://"); i >= 0 {
+		h = h[i+len("://"):]
+	}
+	h = strings.TrimPrefix(h, "git@")
+	h = strings.Trim(h, "/:")
+	if h == "" {
+		return "local"
+	}
+	return entryKey(h)
+}
+
+// useCacheEntry copies the cached clone into a new
+// temporary directory, which it assigns to the spec.
+func useCacheEntry(entry string, rs *RepoSpec) error {
+	dir, err := filesys.NewTmpConfirmedDir()
+	if err != nil {
+		return err
+	}
+	err = copyTree(entry, dir.String())
+	if err != nil {
+		os.RemoveAll(dir.String())
+		return errors.Wrapf(err, "copying cached clone '%s'", entry)
+	}
+	rs.Dir = dir
+	// The modification time of an entry records its last
+	// use, for pruning.
+	now := time.Now()
+	return os.Chtimes(entry, now, now)
+}
+
+// storeCacheEntry copies a clone into the cache, replacing
+// any previous entry.  The copy is made aside then renamed
+// into place, so a reader never sees a partial entry.
+func storeCacheEntry(clone, entry string) error {
+	err := os.MkdirAll(filepath.Dir(entry), 0700)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempDir(filepath.Dir(entry), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	err = copyTree(clone, tmp)
+	if err != nil {
+		return err
+	}
+	old := tmp + ".old"
+	if err = os.Rename(entry, old); err == nil {
+		defer os.RemoveAll(old)
+	}
+	err = os.Rename(tmp, entry)
+	if err != nil {
+		if _, statErr := os.Stat(entry); statErr == nil {
+			// Stored concurrently by another build.
+			return nil
+		}
+	}
+	return err
+}
+
+// copyTree copies the directory tree at src, less any
+// .git directory, into the existing directory dst.
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if rel == filesys.SelfDir {
+			return nil
+		}
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case info.IsDir():
+			return os.Mkdir(target, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target, info.Mode().Perm())
+		}
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err2 := out.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
+
+// PruneCache removes from the given cache the clones that
+// haven't been used for at least the given duration, and
+// returns the directories it removed.
+func PruneCache(cacheDir string, maxAge time.Duration) ([]string, error) {
+	entries, err := filepath.Glob(filepath.Join(cacheDir, "*", "*", "*"))
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	cutoff := time.Now().Add(-maxAge)
+	for _, entry := range entries {
+		info, err := os.Stat(entry)
+		if err != nil {
+			return removed, err
+		}
+		if !info.IsDir() || info.ModTime().After(cutoff) {
+			continue
+		}
+		err = os.RemoveAll(entry)
+		if err != nil {
+			return removed, err
+		}
+		removed = append(removed, entry)
+	}
+	return removed, nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// fakeFetcher returns a cloner that writes a file holding
+// the given content, and counts how often it's called.
+func fakeFetcher(t *testing.T, content *string, count *int) Cloner {
+	return func(rs *RepoSpec) error {
+		*count++
+		dir, err := filesys.NewTmpConfirmedDir()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rs.Dir = dir
+		err = os.MkdirAll(dir.Join(".git"), 0700)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ioutil.WriteFile(dir.Join("file.txt"), []byte(*content), 0600)
+	}
+}
+
+func cloneAndRead(t *testing.T, cloner Cloner, url string) (string, error) {
+	rs, err := NewRepoSpecFromUrl(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cloner(rs)
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(rs.Dir.String())
+	b, err := ioutil.ReadFile(rs.Dir.Join("file.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b), nil
+}
+
+func TestCachingCloner(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "kustomize-cache-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cacheDir)
+	c := &types.GitCacheConfig{AbsCacheDir: cacheDir}
+	content, count := "one", 0
+	cloner := CachingCloner(c, fakeFetcher(t, &content, &count))
+	const branch = "github.com/someOrg/someRepo?ref=someBranch"
+	const commit = "github.com/someOrg/someRepo?ref=0123abcd"
+
+	expect := func(url, expected string, expectedCount int) {
+		t.Helper()
+		actual, err := cloneAndRead(t, cloner, url)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if actual != expected || count != expectedCount {
+			t.Fatalf("expected %q after %d fetches, got %q after %d",
+				expected, expectedCount, actual, count)
+		}
+	}
+
+	expect(branch, "one", 1)
+	content = "two"
+	// Cached, so not fetched.
+	expect(branch, "one", 1)
+	// A different ref is a different entry.
+	expect(commit, "two", 2)
+
+	c.Refresh = true
+	content = "three"
+	// Refreshed, since a branch may move.
+	expect(branch, "three", 3)
+	// Not refreshed, since a commit can't.
+	expect(commit, "two", 3)
+
+	c.Offline = true
+	expect(branch, "three", 3)
+	_, err = cloneAndRead(t, cloner, "github.com/someOrg/otherRepo")
+	if err == nil || !strings.Contains(err.Error(), "offline") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("fetched while offline")
+	}
+
+	entry := CacheEntry(cacheDir, &RepoSpec{
+		Host: "https://github.com/", OrgRepo: "someOrg/someRepo", Ref: "someBranch"})
+	if _, err := os.Stat(filepath.Join(entry, ".git")); err == nil {
+		t.Fatalf(".git directory was cached")
+	}
+}
+
+func TestCacheEntry(t *testing.T) {
+	for url, expected := range map[string]string{
+		"github.com/someOrg/someRepo?ref=v1.0.0":    "/c/github.com/someOrg%2FsomeRepo/v1.0.0",
+		"git@github.com:someOrg/someRepo.git":       "/c/github.com/someOrg%2FsomeRepo/HEAD",
+		"https://example.com/a/b.git?ref=feature/x": "/c/example.com/a%2Fb/feature%2Fx",
+		"file:///srv/repos/a.git?ref=v1":            "/c/local/srv%2Frepos%2Fa/v1",
+	} {
+		rs, err := NewRepoSpecFromUrl(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := CacheEntry("/c", rs); actual != expected {
+			t.Errorf("%s: expected %s, got %s", url, expected, actual)
+		}
+	}
+	// No part of a spec may take its entry out of its place.
+	for _, rs := range []*RepoSpec{
+		{Host: "https://github.com/", OrgRepo: "someOrg/someRepo", Ref: ".."},
+		{Host: "https://github.com/", OrgRepo: "..", Ref: "v1"},
+		{Host: "https://github.com/", OrgRepo: "", Ref: "v1"},
+		{Host: "https://../", OrgRepo: "someOrg/someRepo", Ref: "."},
+		{Host: "https://github.com/", OrgRepo: "someOrg/someRepo", Ref: ".tmp-1"},
+	} {
+		entry := CacheEntry("/c", rs)
+		parts := strings.Split(strings.TrimPrefix(entry, "/c/"), "/")
+		if len(parts) != 3 {
+			t.Errorf("%v: entry %s isn't three levels down", rs, entry)
+		}
+		for _, p := range parts {
+			if strings.HasPrefix(p, ".") {
+				t.Errorf("%v: entry %s has a part starting with a dot", rs, entry)
+			}
+		}
+	}
+}
+
+func TestPruneCache(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "kustomize-cache-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cacheDir)
+	old := filepath.Join(cacheDir, "example.com", "a%2Fb", "v1")
+	recent := filepath.Join(cacheDir, "example.com", "a%2Fb", "v2")
+	for _, dir := range []string{old, recent} {
+		// A clone whose top level holds only directories.
+		err = os.MkdirAll(filepath.Join(dir, "base"), 0700)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	then := time.Now().Add(-48 * time.Hour)
+	err = os.Chtimes(old, then, then)
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed, err := PruneCache(cacheDir, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 || removed[0] != old {
+		t.Fatalf("unexpected removals: %v", removed)
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Fatalf("recent entry removed: %v", err)
+	}
+}
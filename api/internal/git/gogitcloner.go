@@ -0,0 +1,119 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/filesys"
+)
+
+// ClonerUsingGoGit uses go-git, a git implementation in Go,
+// rather than a local git install, to obtain a local clone
+// of a single ref of a remote repo.  Branches and tags are
+// cloned shallowly; a commit hash that is neither requires
+// a full clone.
+func ClonerUsingGoGit(repoSpec *RepoSpec) error {
+	url := goGitUrl(repoSpec)
+	refName, hash, err := resolveRef(url, repoSpec.Ref)
+	if err != nil {
+		return err
+	}
+	repoSpec.Dir, err = filesys.NewTmpConfirmedDir()
+	if err != nil {
+		return err
+	}
+	opts := &gogit.CloneOptions{
+		URL:               url,
+		ReferenceName:     refName,
+		SingleBranch:      true,
+		Depth:             1,
+		RecurseSubmodules: gogit.DefaultSubmoduleRecursionDepth,
+		Tags:              gogit.NoTags,
+	}
+	if refName == "" {
+		// There's no asking for a single commit,
+		// so take everything.
+		opts.SingleBranch = false
+		opts.Depth = 0
+		opts.NoCheckout = true
+	}
+	repo, err := gogit.PlainClone(repoSpec.Dir.String(), false, opts)
+	if err != nil {
+		return errors.Wrapf(
+			err, "trouble cloning git repo %v in %s",
+			url, repoSpec.Dir.String())
+	}
+	if refName != "" {
+		return nil
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	err = wt.Checkout(&gogit.CheckoutOptions{Hash: hash})
+	if err != nil {
+		return errors.Wrapf(
+			err, "trouble checking out commit %s of %s", hash, url)
+	}
+	return nil
+}
+
+// goGitUrl returns the clone spec, less any shorthand
+// that only a git program configured to expand it, e.g.
+// with url.<base>.insteadOf, could understand.
+func goGitUrl(repoSpec *RepoSpec) string {
+	spec := repoSpec.CloneSpec()
+	if strings.HasPrefix(spec, "gh:") {
+		return "https://github.com/" + strings.TrimPrefix(spec, "gh:")
+	}
+	return spec
+}
+
+// resolveRef asks the remote repo for the reference with the
+// given name, trying it as a branch, then as a tag.  Failing
+// that, a name that looks like a commit hash is returned as
+// a hash, with an empty reference name.  An empty name is
+// the remote's HEAD.
+func resolveRef(
+	url, ref string) (plumbing.ReferenceName, plumbing.Hash, error) {
+	if ref == "" {
+		return plumbing.HEAD, plumbing.ZeroHash, nil
+	}
+	remote := gogit.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{url},
+	})
+	refs, err := remote.List(&gogit.ListOptions{})
+	if err != nil {
+		return "", plumbing.ZeroHash, errors.Wrapf(
+			err, "trouble listing refs of git repo %s", url)
+	}
+	for _, name := range []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName(ref),
+		plumbing.NewTagReferenceName(ref),
+	} {
+		for _, r := range refs {
+			if r.Name() == name {
+				return name, r.Hash(), nil
+			}
+		}
+	}
+	if commitHash.MatchString(ref) {
+		if len(ref) < 40 {
+			return "", plumbing.ZeroHash, fmt.Errorf(
+				"abbreviated commit hash '%s' of %s unsupported; "+
+					"use all 40 characters", ref, url)
+		}
+		return "", plumbing.NewHash(ref), nil
+	}
+	return "", plumbing.ZeroHash, fmt.Errorf(
+		"git repo %s has no branch or tag named '%s'", url, ref)
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// makeRepo makes a repo in dir with two commits of file.txt,
+// the first tagged v1, and returns the hash of the first.
+func makeRepo(t *testing.T, dir string) string {
+	repo, err := gogit.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hashes []string
+	for _, content := range []string{"one", "two"} {
+		err = ioutil.WriteFile(
+			filepath.Join(dir, "file.txt"), []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = wt.Add("file.txt"); err != nil {
+			t.Fatal(err)
+		}
+		h, err := wt.Commit(content, &gogit.CommitOptions{
+			Author: &object.Signature{
+				Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, h.String())
+	}
+	h, err := repo.ResolveRevision("HEAD~1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = repo.CreateTag("v1", *h, nil); err != nil {
+		t.Fatal(err)
+	}
+	return hashes[0]
+}
+
+func TestClonerUsingGoGit(t *testing.T) {
+	// The file transport of go-git runs git-upload-pack.
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("no git program on path")
+	}
+	dir, err := ioutil.TempDir("", "kustomize-gogit-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	repo := filepath.Join(dir, "someOrg", "someRepo.git")
+	first := makeRepo(t, repo)
+	url := "file://" + repo
+
+	for ref, expected := range map[string]string{
+		"":       "two",
+		"v1":     "one",
+		"master": "two",
+		first:    "one",
+	} {
+		u := url
+		if ref != "" {
+			u += "?ref=" + ref
+		}
+		actual, err := cloneAndRead(t, ClonerUsingGoGit, u)
+		if err != nil {
+			t.Fatalf("ref '%s': unexpected error: %v", ref, err)
+		}
+		if actual != expected {
+			t.Fatalf("ref '%s': expected %q, got %q", ref, expected, actual)
+		}
+	}
+
+	for ref, expected := range map[string]string{
+		"v2":      "no branch or tag named 'v2'",
+		first[:7]: "abbreviated commit hash",
+	} {
+		_, err := cloneAndRead(t, ClonerUsingGoGit, url+"?ref="+ref)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("ref '%s': unexpected error: %v", ref, err)
+		}
+	}
+}
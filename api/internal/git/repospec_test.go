@@ -180,6 +180,12 @@ func TestNewRepoSpecFromUrl_CloneSpecs(t *testing.T) {
 			absPath:   notCloned.String(),
 			ref:       "",
 		},
+		{
+			input:     "file:///srv/repos/someorg/somerepo.git/somedir?ref=v1.0.0",
+			cloneSpec: "file:///srv/repos/someorg/somerepo.git",
+			absPath:   notCloned.Join("somedir"),
+			ref:       "v1.0.0",
+		},
 	}
 	for _, testcase := range testcases {
 		rs, err := NewRepoSpecFromUrl(testcase.input)
@@ -27,6 +27,12 @@ func makeFreshConfigMap(
 // MakeConfigMap returns a new ConfigMap, or nil and an error.
 func (f *Factory) MakeConfigMap(
 	args *types.ConfigMapArgs) (*corev1.ConfigMap, error) {
+	// The cleartext of encrypted files belongs in secrets.
+	if len(args.EncryptedFileSources) > 0 || len(args.EncryptedEnvSources) > 0 {
+		return nil, fmt.Errorf(
+			"configMap %s: encryptedFiles and encryptedEnvs "+
+				"are only allowed in secrets", args.Name)
+	}
 	all, err := f.kvLdr.Load(args.KvPairSources)
 	if err != nil {
 		return nil, errors.Wrap(err, "loading KV pairs")
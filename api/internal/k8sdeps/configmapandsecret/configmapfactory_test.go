@@ -203,3 +203,21 @@ func TestConstructConfigMap(t *testing.T) {
 		}
 	}
 }
+
+func TestConstructConfigMapRejectsEncryptedSources(t *testing.T) {
+	kvLdr := kv.NewLoader(
+		loader.NewFileLoaderAtRoot(filesys.MakeFsInMemory()),
+		valtest_test.MakeFakeValidator())
+	for _, sources := range []types.KvPairSources{
+		{EncryptedFileSources: []string{"tls.key"}},
+		{EncryptedEnvSources: []string{"db.env"}},
+	} {
+		args := types.ConfigMapArgs{GeneratorArgs: types.GeneratorArgs{
+			Name: "cm", KvPairSources: sources}}
+		_, err := NewFactory(kvLdr, nil).MakeConfigMap(&args)
+		if err == nil || err.Error() != "configMap cm: encryptedFiles "+
+			"and encryptedEnvs are only allowed in secrets" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
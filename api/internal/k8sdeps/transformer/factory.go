@@ -5,6 +5,7 @@
 package transformer
 
 import (
+	"github.com/go-openapi/spec"
 	"sigs.k8s.io/kustomize/api/internal/k8sdeps/transformer/patch"
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/resource"
@@ -13,6 +14,8 @@ import (
 // FactoryImpl makes patch transformer and name hash transformer
 type FactoryImpl struct{}
 
+var _ resmap.CrdPatchFactory = &FactoryImpl{}
+
 // NewFactoryImpl makes a new factoryImpl instance
 func NewFactoryImpl() *FactoryImpl {
 	return &FactoryImpl{}
@@ -21,5 +24,16 @@ func NewFactoryImpl() *FactoryImpl {
 func (p *FactoryImpl) MergePatches(patches []*resource.Resource,
 	rf *resource.Factory) (
 	resmap.ResMap, error) {
-	return patch.MergePatches(patches, rf)
+	return patch.MergePatches(patches, rf, nil)
+}
+
+func (p *FactoryImpl) MergeCrdPatches(patches []*resource.Resource,
+	rf *resource.Factory, crdSchemas spec.Definitions) (
+	resmap.ResMap, error) {
+	return patch.MergePatches(patches, rf, crdSchemas)
+}
+
+func (p *FactoryImpl) ApplySmPatch(target, smPatch *resource.Resource,
+	crdSchemas spec.Definitions) error {
+	return patch.ApplySmPatch(target, smPatch, crdSchemas)
 }
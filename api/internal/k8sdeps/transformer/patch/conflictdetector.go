@@ -8,11 +8,13 @@ import (
 	"fmt"
 
 	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/go-openapi/spec"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/mergepatch"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/kustomize/api/internal/openapi"
 	"sigs.k8s.io/kustomize/api/resid"
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/resource"
@@ -88,10 +90,9 @@ type strategicMergePatch struct {
 var _ conflictDetector = &strategicMergePatch{}
 
 func newSMPConflictDetector(
-	versionedObj runtime.Object,
-	rf *resource.Factory) (conflictDetector, error) {
-	lookupPatchMeta, err := strategicpatch.NewPatchMetaFromStruct(versionedObj)
-	return &strategicMergePatch{lookupPatchMeta: lookupPatchMeta, rf: rf}, err
+	lookupPatchMeta strategicpatch.LookupPatchMeta,
+	rf *resource.Factory) conflictDetector {
+	return &strategicMergePatch{lookupPatchMeta: lookupPatchMeta, rf: rf}
 }
 
 func (smp *strategicMergePatch) hasConflict(p1, p2 *resource.Resource) (bool, error) {
@@ -137,7 +138,8 @@ func (smp *strategicMergePatch) mergePatches(patch1, patch2 *resource.Resource)
 // MergePatches merge and index patches by OrgId.
 // It errors out if there is conflict between patches.
 func MergePatches(patches []*resource.Resource,
-	rf *resource.Factory) (resmap.ResMap, error) {
+	rf *resource.Factory, crdSchemas spec.Definitions) (resmap.ResMap, error) {
+	schemas := openapi.NewSchemas(crdSchemas)
 	rc := resmap.New()
 	for ix, patch := range patches {
 		id := patch.OrgId()
@@ -150,18 +152,15 @@ func MergePatches(patches []*resource.Resource,
 			return nil, fmt.Errorf("self conflict in patches")
 		}
 
-		versionedObj, err := scheme.Scheme.New(toSchemaGvk(id.Gvk))
-		if err != nil && !runtime.IsNotRegisteredError(err) {
+		lookupPatchMeta, err := lookupPatchMetaFor(patch, schemas)
+		if err != nil {
 			return nil, err
 		}
 		var cd conflictDetector
-		if err != nil {
+		if lookupPatchMeta == nil {
 			cd = newJMPConflictDetector(rf)
 		} else {
-			cd, err = newSMPConflictDetector(versionedObj, rf)
-			if err != nil {
-				return nil, err
-			}
+			cd = newSMPConflictDetector(lookupPatchMeta, rf)
 		}
 
 		conflict, err := cd.hasConflict(existing[0], patch)
@@ -186,6 +185,30 @@ func MergePatches(patches []*resource.Resource,
 	return rc, nil
 }
 
+// lookupPatchMetaFor returns the strategic merge patch
+// metadata of the type of the resource, from its Go struct
+// if it's a built in type, or from its custom schema, or
+// nil if it has neither.
+func lookupPatchMetaFor(r *resource.Resource,
+	schemas *openapi.Schemas) (strategicpatch.LookupPatchMeta, error) {
+	gvk := r.OrgId().Gvk
+	versionedObj, err := scheme.Scheme.New(toSchemaGvk(gvk))
+	switch {
+	case err == nil:
+		return strategicpatch.NewPatchMetaFromStruct(versionedObj)
+	case !runtime.IsNotRegisteredError(err):
+		return nil, err
+	}
+	return schemas.PatchMeta(apiVersion(gvk), gvk.Kind), nil
+}
+
+func apiVersion(x resid.Gvk) string {
+	if x.Group == "" {
+		return x.Version
+	}
+	return x.Group + "/" + x.Version
+}
+
 // toSchemaGvk converts to a schema.GroupVersionKind.
 func toSchemaGvk(x resid.Gvk) schema.GroupVersionKind {
 	return schema.GroupVersionKind{
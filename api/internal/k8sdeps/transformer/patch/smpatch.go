@@ -0,0 +1,45 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package patch
+
+import (
+	"github.com/go-openapi/spec"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/kustomize/api/internal/openapi"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+// ApplySmPatch applies a strategic merge patch to target.
+// Custom resources whose schemas are among crdSchemas are
+// patched per those; others are patched as by target.Patch,
+// i.e. per their Go structs if built in, or else with a JSON
+// merge patch.
+func ApplySmPatch(target, patch *resource.Resource,
+	crdSchemas spec.Definitions) error {
+	gvk := patch.GetGvk()
+	_, err := scheme.Scheme.New(toSchemaGvk(gvk))
+	if !runtime.IsNotRegisteredError(err) {
+		return target.Patch(patch.Kunstructured)
+	}
+	lookupPatchMeta := openapi.NewSchemas(crdSchemas).PatchMeta(
+		apiVersion(gvk), gvk.Kind)
+	if lookupPatchMeta == nil {
+		return target.Patch(patch.Kunstructured)
+	}
+	// Keep the name of the target, which may have been
+	// changed, unless the patch deletes it.
+	saveName := target.GetName()
+	merged, err := strategicpatch.StrategicMergeMapPatchUsingLookupPatchMeta(
+		target.Map(), patch.Map(), lookupPatchMeta)
+	if err != nil {
+		return err
+	}
+	target.SetMap(merged)
+	if len(target.Map()) != 0 {
+		target.SetName(saveName)
+	}
+	return nil
+}
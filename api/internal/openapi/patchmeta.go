@@ -0,0 +1,113 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"github.com/go-openapi/spec"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/kube-openapi/pkg/util/proto"
+)
+
+const (
+	xPatchStrategy = "x-kubernetes-patch-strategy"
+	xPatchMergeKey = "x-kubernetes-patch-merge-key"
+	xListType      = "x-kubernetes-list-type"
+	xListMapKeys   = "x-kubernetes-list-map-keys"
+)
+
+// PatchMeta returns the strategic merge patch metadata of
+// the resources with the given apiVersion and kind, if a
+// custom definition of them is known, or else nil.
+//
+// Lists merge by key per x-kubernetes-patch-strategy and
+// x-kubernetes-patch-merge-key, as in the schemas of built
+// in types, or per x-kubernetes-list-type: map and the
+// first of x-kubernetes-list-map-keys, as in the structural
+// schemas of CRDs.  Lists of type set merge as sets.
+func (s *Schemas) PatchMeta(
+	apiVersion, kind string) strategicpatch.LookupPatchMeta {
+	d := s.Custom(apiVersion, kind)
+	if d == nil {
+		return nil
+	}
+	return &patchMeta{schemas: s, s: d}
+}
+
+// patchMeta is the patch metadata of the fields of
+// a schema.  A nil schema, that of unknown fields,
+// has no metadata, so that they're replaced.
+type patchMeta struct {
+	schemas *Schemas
+	s       *spec.Schema
+}
+
+var _ strategicpatch.LookupPatchMeta = &patchMeta{}
+
+func (m *patchMeta) LookupPatchMetadataForStruct(
+	key string) (strategicpatch.LookupPatchMeta, strategicpatch.PatchMeta, error) {
+	f := m.field(key)
+	return &patchMeta{schemas: m.schemas, s: f}, metaOf(f), nil
+}
+
+func (m *patchMeta) LookupPatchMetadataForSlice(
+	key string) (strategicpatch.LookupPatchMeta, strategicpatch.PatchMeta, error) {
+	f := m.field(key)
+	var items *spec.Schema
+	if f != nil && f.Items != nil {
+		items = m.schemas.Resolve(f.Items.Schema)
+	}
+	return &patchMeta{schemas: m.schemas, s: items}, metaOf(f), nil
+}
+
+func (m *patchMeta) Name() string {
+	return ""
+}
+
+// field returns the schema of the given field, or nil.
+func (m *patchMeta) field(key string) *spec.Schema {
+	if m.s == nil {
+		return nil
+	}
+	if p, ok := m.s.Properties[key]; ok {
+		return m.schemas.Resolve(&p)
+	}
+	if m.s.AdditionalProperties != nil {
+		return m.schemas.Resolve(m.s.AdditionalProperties.Schema)
+	}
+	return nil
+}
+
+// metaOf returns the patch metadata in the extensions
+// of the schema of a field.
+func metaOf(s *spec.Schema) strategicpatch.PatchMeta {
+	ext := map[string]interface{}{}
+	if s != nil {
+		for _, k := range []string{xPatchStrategy, xPatchMergeKey} {
+			if v, ok := s.Extensions[k].(string); ok {
+				ext[k] = v
+			}
+		}
+		switch s.Extensions[xListType] {
+		case "map":
+			keys, _ := s.Extensions[xListMapKeys].([]interface{})
+			if len(keys) > 0 {
+				if k, ok := keys[0].(string); ok {
+					ext[xPatchStrategy] = "merge"
+					ext[xPatchMergeKey] = k
+				}
+			}
+		case "set":
+			ext[xPatchStrategy] = "merge"
+		}
+	}
+	// PatchMeta can't be made outside of its package, but
+	// for one of the fields of a proto.Kind, whose metadata
+	// is read from its extensions.
+	_, meta, _ := strategicpatch.NewPatchMetaFromOpenAPI(&proto.Kind{
+		Fields: map[string]proto.Schema{
+			"f": &proto.Arbitrary{BaseSchema: proto.BaseSchema{Extensions: ext}},
+		},
+	}).LookupPatchMetadataForStruct("f")
+	return meta
+}
@@ -0,0 +1,110 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-openapi/spec"
+)
+
+const appSchema = `{
+  "properties": {
+    "apiVersion": {"type": "string"},
+    "kind": {"type": "string"},
+    "metadata": {"type": "object"},
+    "spec": {
+      "properties": {
+        "byMergeKey": {
+          "type": "array",
+          "x-kubernetes-patch-strategy": "merge",
+          "x-kubernetes-patch-merge-key": "name",
+          "items": {"$ref": "#/definitions/com.example.v1.Item"}
+        },
+        "byMapKeys": {
+          "type": "array",
+          "x-kubernetes-list-type": "map",
+          "x-kubernetes-list-map-keys": ["id", "protocol"]
+        },
+        "set": {"type": "array", "x-kubernetes-list-type": "set"},
+        "atomic": {"type": "array", "x-kubernetes-list-type": "atomic"},
+        "labels": {"additionalProperties": {"type": "string"}}
+      }
+    }
+  }
+}`
+
+const itemSchema = `{
+  "properties": {
+    "ports": {
+      "type": "array",
+      "x-kubernetes-list-type": "map",
+      "x-kubernetes-list-map-keys": ["port"]
+    }
+  }
+}`
+
+func makeSchemas(t *testing.T) *Schemas {
+	defs := spec.Definitions{}
+	for name, s := range map[string]string{
+		"example.com/v1.App":  appSchema,
+		"com.example.v1.Item": itemSchema,
+	} {
+		var d spec.Schema
+		if err := json.Unmarshal([]byte(s), &d); err != nil {
+			t.Fatal(err)
+		}
+		defs[name] = d
+	}
+	return NewSchemas(defs)
+}
+
+func TestPatchMeta(t *testing.T) {
+	s := makeSchemas(t)
+	if m := s.PatchMeta("v1", "ConfigMap"); m != nil {
+		t.Fatalf("expected no custom patch meta of a ConfigMap")
+	}
+	app := s.PatchMeta("example.com/v1", "App")
+	if app == nil {
+		t.Fatalf("expected patch meta of App")
+	}
+	appSpec, _, err := app.LookupPatchMetadataForStruct("spec")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for field, expected := range map[string]struct {
+		strategies []string
+		mergeKey   string
+	}{
+		"byMergeKey": {[]string{"merge"}, "name"},
+		"byMapKeys":  {[]string{"merge"}, "id"},
+		"set":        {[]string{"merge"}, ""},
+		"atomic":     {[]string{}, ""},
+		"unknown":    {[]string{}, ""},
+	} {
+		_, meta, err := appSpec.LookupPatchMetadataForSlice(field)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", field, err)
+		}
+		if !reflect.DeepEqual(meta.GetPatchStrategies(), expected.strategies) ||
+			meta.GetPatchMergeKey() != expected.mergeKey {
+			t.Fatalf("%s: expected %v, got %v %q", field, expected,
+				meta.GetPatchStrategies(), meta.GetPatchMergeKey())
+		}
+	}
+	// The items of lists, and fields unknown to the
+	// schema, have metadata of their own.
+	item, _, _ := appSpec.LookupPatchMetadataForSlice("byMergeKey")
+	_, meta, _ := item.LookupPatchMetadataForSlice("ports")
+	if meta.GetPatchMergeKey() != "port" {
+		t.Fatalf("expected merge key port, got %q", meta.GetPatchMergeKey())
+	}
+	unknown, _, _ := appSpec.LookupPatchMetadataForStruct("unknown")
+	_, meta, _ = unknown.LookupPatchMetadataForSlice("ports")
+	if meta.GetPatchMergeKey() != "" {
+		t.Fatalf("expected no merge key, got %q", meta.GetPatchMergeKey())
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	kopenapi "sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	definitionsPrefix = "#/definitions/"
+	xGroupVersionKind = "x-kubernetes-group-version-kind"
+)
+
+// Schemas are the OpenAPI definitions of custom types,
+// on top of the schema of Kubernetes built in to kyaml.
+type Schemas struct {
+	// Definitions, by type name.
+	defs spec.Definitions
+	// Definitions of resources, by apiVersion and kind,
+	// e.g. example.com/v1.Bee.
+	types map[string]*spec.Schema
+	// Definitions of resources, by kind alone.
+	kinds map[string]*spec.Schema
+}
+
+// NewSchemas returns the given definitions, by type name.
+// A definition is that of the resources of a type if it
+// has an x-kubernetes-group-version-kind extension naming
+// it, or else if its name is the apiVersion and kind of
+// the type, as for those of CRD files.  Failing that, a
+// definition is matched to resources by kind alone, as
+// for those named by Go type, as in kube-openapi.
+func NewSchemas(defs spec.Definitions) *Schemas {
+	s := &Schemas{
+		defs:  defs,
+		types: make(map[string]*spec.Schema),
+		kinds: make(map[string]*spec.Schema),
+	}
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		d := defs[name]
+		if !looksLikeAk8sType(&d) {
+			continue
+		}
+		for _, gvk := range groupVersionKinds(&d) {
+			s.types[gvk] = &d
+		}
+		s.types[name] = &d
+		kind := name[strings.LastIndex(name, ".")+1:]
+		if _, ok := s.kinds[kind]; !ok {
+			s.kinds[kind] = &d
+		}
+	}
+	return s
+}
+
+func looksLikeAk8sType(s *spec.Schema) bool {
+	for _, p := range []string{"kind", "apiVersion", "metadata"} {
+		if _, ok := s.Properties[p]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// groupVersionKinds returns the apiVersion and kind, e.g.
+// example.com/v1.Bee, of each type in the extension.
+func groupVersionKinds(s *spec.Schema) []string {
+	gvks, _ := s.Extensions[xGroupVersionKind].([]interface{})
+	var result []string
+	for _, x := range gvks {
+		gvk, ok := x.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		group, _ := gvk["group"].(string)
+		version, _ := gvk["version"].(string)
+		kind, _ := gvk["kind"].(string)
+		result = append(result, apiVersionKind(group, version, kind))
+	}
+	return result
+}
+
+func apiVersionKind(group, version, kind string) string {
+	if group == "" {
+		return version + "." + kind
+	}
+	return group + "/" + version + "." + kind
+}
+
+// Custom returns the definition of the resources with the
+// given apiVersion and kind, if a custom one is known.  A
+// definition matched by kind alone doesn't replace that of
+// a built in type, e.g. a Go type named Service that of v1
+// Services.
+func (s *Schemas) Custom(apiVersion, kind string) *spec.Schema {
+	if d, ok := s.types[apiVersion+"."+kind]; ok {
+		return d
+	}
+	if d, ok := s.kinds[kind]; ok && builtIn(apiVersion, kind) == nil {
+		return d
+	}
+	return nil
+}
+
+// ForType returns the definition of the resources with the
+// given apiVersion and kind, custom or built in, or nil.
+func (s *Schemas) ForType(apiVersion, kind string) *spec.Schema {
+	if d := s.Custom(apiVersion, kind); d != nil {
+		return d
+	}
+	return builtIn(apiVersion, kind)
+}
+
+// builtIn returns the definition of the resources with
+// the given apiVersion and kind in the built in schema,
+// or nil.
+func builtIn(apiVersion, kind string) *spec.Schema {
+	rs := kopenapi.SchemaForResourceType(
+		yaml.TypeMeta{APIVersion: apiVersion, Kind: kind})
+	if rs.IsEmpty() {
+		return nil
+	}
+	return rs.Schema
+}
+
+// Resolve follows references, returning nil for one
+// that can't be followed.  Custom definitions refer to
+// one another by name, as in #/definitions/com.example.v1.BeeSpec,
+// or by Go type name, and to built in types by their Go names,
+// as in k8s.io/api/core/v1.PodSpec, which the built in schema
+// calls io.k8s.api.core.v1.PodSpec.
+func (s *Schemas) Resolve(schema *spec.Schema) *spec.Schema {
+	for schema != nil && schema.Ref.String() != "" {
+		ref := schema.Ref.String()
+		if d, ok := s.defs[strings.TrimPrefix(ref, definitionsPrefix)]; ok {
+			schema = &d
+			continue
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			ref = definitionsPrefix + swaggerName(ref)
+		}
+		sr := spec.MustCreateRef(ref)
+		r, err := kopenapi.Resolve(&sr)
+		if err != nil {
+			return nil
+		}
+		schema = r
+	}
+	return schema
+}
+
+// swaggerName converts a Go type name, e.g.
+// k8s.io/api/core/v1.PodSpec, to io.k8s.api.core.v1.PodSpec.
+func swaggerName(goName string) string {
+	parts := strings.SplitN(goName, "/", 2)
+	domain := strings.Split(parts[0], ".")
+	for i, j := 0, len(domain)-1; i < j; i, j = i+1, j-1 {
+		domain[i], domain[j] = domain[j], domain[i]
+	}
+	result := strings.Join(domain, ".")
+	if len(parts) > 1 {
+		result += "." + strings.Replace(parts[1], "/", ".", -1)
+	}
+	return result
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+)
+
+func TestCustomByKindAlone(t *testing.T) {
+	defs := spec.Definitions{}
+	for _, name := range []string{
+		"com.example.v1.Service", "com.example.v1.Deployment"} {
+		var d spec.Schema
+		if err := json.Unmarshal([]byte(appSchema), &d); err != nil {
+			t.Fatal(err)
+		}
+		defs[name] = d
+	}
+	s := NewSchemas(defs)
+	for _, tc := range []struct {
+		apiVersion, kind string
+		isCustom         bool
+	}{
+		{"example.com/v1", "Service", true},
+		{"example.com/v1", "Deployment", true},
+		{"v1", "Service", false},
+		{"apps/v1", "Deployment", false},
+	} {
+		d := s.Custom(tc.apiVersion, tc.kind)
+		if (d != nil) != tc.isCustom {
+			t.Fatalf("%s %s: expected custom %v",
+				tc.apiVersion, tc.kind, tc.isCustom)
+		}
+		if m := s.PatchMeta(tc.apiVersion, tc.kind); (m != nil) != tc.isCustom {
+			t.Fatalf("%s %s: expected custom patch meta %v",
+				tc.apiVersion, tc.kind, tc.isCustom)
+		}
+		d = s.ForType(tc.apiVersion, tc.kind)
+		if d == nil {
+			t.Fatalf("%s %s: expected a definition", tc.apiVersion, tc.kind)
+		}
+		if _, ok := d.Properties["spec"].Properties["byMergeKey"]; ok != tc.isCustom {
+			t.Fatalf("%s %s: expected the custom definition %v",
+				tc.apiVersion, tc.kind, tc.isCustom)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package openapi checks resources against the OpenAPI
+// schema of Kubernetes built in to kyaml, and against the
+// OpenAPI definitions found in CRD files.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+// Violation is a place where a resource disagrees
+// with its schema.
+type Violation struct {
+	ResId resid.ResId
+	// Path to the offending field, e.g.
+	// spec.template.spec.containers[0].image
+	Path    string
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s: %s", v.ResId, v.Path, v.Message)
+}
+
+// Validator checks resources against their schemas.
+type Validator struct {
+	schemas *Schemas
+}
+
+// NewValidator returns a Validator using, in addition to
+// the built in schema, the given CRD definitions; see
+// NewSchemas for how they're matched to resources.
+func NewValidator(crdDefs spec.Definitions) *Validator {
+	return &Validator{schemas: NewSchemas(crdDefs)}
+}
+
+// Validate checks each resource in m that has a schema,
+// returning the violations found, ordered by resource,
+// then by path.  Resources of unknown types are skipped.
+func (v *Validator) Validate(m resmap.ResMap) []Violation {
+	var result []Violation
+	for _, r := range m.Resources() {
+		obj := r.Map()
+		s := v.schemaFor(obj)
+		if s == nil {
+			continue
+		}
+		w := &walker{v: v, id: r.CurId()}
+		w.walk(obj, s, "")
+		sort.SliceStable(w.violations, func(i, j int) bool {
+			return w.violations[i].Path < w.violations[j].Path
+		})
+		result = append(result, w.violations...)
+	}
+	return result
+}
+
+func (v *Validator) schemaFor(obj map[string]interface{}) *spec.Schema {
+	kind, _ := obj["kind"].(string)
+	apiVersion, _ := obj["apiVersion"].(string)
+	return v.schemas.ForType(apiVersion, kind)
+}
+
+type walker struct {
+	v          *Validator
+	id         resid.ResId
+	violations []Violation
+}
+
+func (w *walker) add(path, format string, args ...interface{}) {
+	w.violations = append(w.violations, Violation{
+		ResId:   w.id,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (w *walker) walk(value interface{}, s *spec.Schema, path string) {
+	if isQuantity(s) {
+		// Typed as a string in the schema, but may be
+		// written as a number, e.g. cpu: 1
+		if _, ok := value.(string); !ok {
+			if _, ok := asNumber(value); !ok && value != nil {
+				w.add(path, "expected quantity, got %s", describe(value))
+			}
+		}
+		return
+	}
+	s = w.v.schemas.Resolve(s)
+	if s == nil || value == nil {
+		return
+	}
+	switch {
+	case s.Format == "int-or-string":
+		// Typed as a string in the schema.
+		if _, ok := value.(string); !ok {
+			if f, ok := asNumber(value); !ok || f != math.Trunc(f) {
+				w.add(path, "expected integer or string, got %s", describe(value))
+			}
+		}
+	case s.Type.Contains("object"):
+		w.walkObject(value, s, path)
+	case s.Type.Contains("array"):
+		w.walkArray(value, s, path)
+	case s.Type.Contains("string"):
+		if _, ok := value.(string); !ok {
+			w.add(path, "expected string, got %s", describe(value))
+		}
+	case s.Type.Contains("integer"):
+		if f, ok := asNumber(value); !ok || f != math.Trunc(f) {
+			w.add(path, "expected integer, got %s", describe(value))
+		}
+	case s.Type.Contains("number"):
+		if _, ok := asNumber(value); !ok {
+			w.add(path, "expected number, got %s", describe(value))
+		}
+	case s.Type.Contains("boolean"):
+		if _, ok := value.(bool); !ok {
+			w.add(path, "expected boolean, got %s", describe(value))
+		}
+	case len(s.Type) == 0 && len(s.Properties) > 0:
+		w.walkObject(value, s, path)
+	}
+}
+
+func isQuantity(s *spec.Schema) bool {
+	if s == nil {
+		return false
+	}
+	ref := s.Ref.String()
+	return strings.HasSuffix(ref, "api.resource.Quantity") ||
+		strings.HasSuffix(ref, "api/resource.Quantity")
+}
+
+func (w *walker) walkObject(value interface{}, s *spec.Schema, path string) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		w.add(path, "expected object, got %s", describe(value))
+		return
+	}
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			w.add(join(path, name), "required field is missing")
+		}
+	}
+	if preservesUnknownFields(s) {
+		return
+	}
+	for name, fieldValue := range obj {
+		if p, ok := s.Properties[name]; ok {
+			w.walk(fieldValue, &p, join(path, name))
+			continue
+		}
+		if ap := s.AdditionalProperties; ap != nil {
+			if ap.Schema != nil {
+				w.walk(fieldValue, ap.Schema, join(path, name))
+			} else if !ap.Allows {
+				w.add(join(path, name), "unknown field")
+			}
+			continue
+		}
+		if len(s.Properties) > 0 {
+			w.add(join(path, name), "unknown field")
+		}
+		// Otherwise, a free-form object.
+	}
+}
+
+func preservesUnknownFields(s *spec.Schema) bool {
+	b, ok := s.Extensions.GetBool("x-kubernetes-preserve-unknown-fields")
+	return ok && b
+}
+
+func (w *walker) walkArray(value interface{}, s *spec.Schema, path string) {
+	list, ok := value.([]interface{})
+	if !ok {
+		w.add(path, "expected array, got %s", describe(value))
+		return
+	}
+	if s.Items == nil || s.Items.Schema == nil {
+		return
+	}
+	for i, e := range list {
+		w.walk(e, s.Items.Schema, fmt.Sprintf("%s[%d]", path, i))
+	}
+}
+
+func asNumber(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func describe(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return fmt.Sprintf("string %q", value)
+	case bool:
+		return fmt.Sprintf("boolean %v", value)
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	if _, ok := asNumber(value); ok {
+		return fmt.Sprintf("number %v", value)
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
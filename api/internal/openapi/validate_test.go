@@ -0,0 +1,119 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"sigs.k8s.io/kustomize/api/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/api/resource"
+	resmaptest_test "sigs.k8s.io/kustomize/api/testutils/resmaptest"
+)
+
+func TestSwaggerName(t *testing.T) {
+	for goName, expected := range map[string]string{
+		"k8s.io/api/core/v1.PodSpec":                      "io.k8s.api.core.v1.PodSpec",
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta": "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta",
+	} {
+		if actual := swaggerName(goName); actual != expected {
+			t.Errorf("%s: expected %s, got %s", goName, expected, actual)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	rf := resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl())
+	m := resmaptest_test.NewRmBuilder(t, rf).Add(
+		map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata": map[string]interface{}{
+				"name":              "svc",
+				"creationTimestamp": nil,
+			},
+			"spec": map[string]interface{}{
+				"ports": []interface{}{
+					map[string]interface{}{"port": 80, "targetPort": "http"},
+					map[string]interface{}{"port": 81, "targetPort": 8081},
+					map[string]interface{}{"port": 82, "targetPort": true},
+				},
+			},
+		}).Add(
+		map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]interface{}{
+				"name": "pod",
+			},
+			"spec": map[string]interface{}{
+				"hostNetwork": "yes",
+				"containers": []interface{}{
+					map[string]interface{}{
+						"image": "nginx",
+						"resources": map[string]interface{}{
+							"limits": map[string]interface{}{
+								"cpu":    1,
+								"memory": "1Gi",
+							},
+						},
+					},
+				},
+			},
+		}).Add(
+		map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Unknown",
+			"metadata": map[string]interface{}{
+				"name": "whatever",
+			},
+			"anything": "goes",
+		}).Add(
+		map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata": map[string]interface{}{
+				"name": "widget",
+			},
+			"spec": map[string]interface{}{
+				"size": 1.5,
+			},
+		}).ResMap()
+
+	crdDefs := spec.Definitions{
+		"example.com/widgets/v1.Widget": spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"apiVersion": *spec.StringProperty(),
+					"kind":       *spec.StringProperty(),
+					"metadata":   *spec.RefSchema("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+					"spec":       *spec.RefSchema("example.com/widgets/v1.WidgetSpec"),
+				},
+			},
+		},
+		"example.com/widgets/v1.WidgetSpec": spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Properties: map[string]spec.Schema{
+					"size": *spec.Int64Property(),
+				},
+			},
+		},
+	}
+	var actual []string
+	for _, v := range NewValidator(crdDefs).Validate(m) {
+		actual = append(actual, v.String())
+	}
+	expected := []string{
+		`~G_v1_Service|~X|svc: spec.ports[2].targetPort: expected integer or string, got boolean true`,
+		`~G_v1_Pod|~X|pod: spec.containers[0].name: required field is missing`,
+		`~G_v1_Pod|~X|pod: spec.hostNetwork: expected boolean, got string "yes"`,
+		`example.com_v1_Widget|~X|widget: spec.size: expected integer, got number 1.5`,
+	}
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected\n%s\nbut got\n%s",
+			strings.Join(expected, "\n"), strings.Join(actual, "\n"))
+	}
+}
@@ -12,24 +12,27 @@ func _() {
 	_ = x[AnnotationsTransformer-1]
 	_ = x[ConfigMapGenerator-2]
 	_ = x[HashTransformer-3]
-	_ = x[ImageTagTransformer-4]
-	_ = x[InventoryTransformer-5]
-	_ = x[KindFilterTransformer-6]
-	_ = x[KindOrderTransformer-7]
-	_ = x[LabelTransformer-8]
-	_ = x[LegacyOrderTransformer-9]
-	_ = x[NamespaceTransformer-10]
-	_ = x[PatchJson6902Transformer-11]
-	_ = x[PatchStrategicMergeTransformer-12]
-	_ = x[PatchTransformer-13]
-	_ = x[PrefixSuffixTransformer-14]
-	_ = x[ReplicaCountTransformer-15]
-	_ = x[SecretGenerator-16]
+	_ = x[HelmChartInflationGenerator-4]
+	_ = x[ImageTagTransformer-5]
+	_ = x[InventoryTransformer-6]
+	_ = x[KindFilterTransformer-7]
+	_ = x[KindOrderTransformer-8]
+	_ = x[LabelTransformer-9]
+	_ = x[LegacyOrderTransformer-10]
+	_ = x[NamespaceTransformer-11]
+	_ = x[PatchJson6902Transformer-12]
+	_ = x[PatchStrategicMergeTransformer-13]
+	_ = x[PatchTransformer-14]
+	_ = x[PrefixSuffixTransformer-15]
+	_ = x[ReplacementTransformer-16]
+	_ = x[ReplicaCountTransformer-17]
+	_ = x[SecretGenerator-18]
+	_ = x[StarlarkTransformer-19]
 }
 
-const _BuiltinPluginType_name = "UnknownAnnotationsTransformerConfigMapGeneratorHashTransformerImageTagTransformerInventoryTransformerKindFilterTransformerKindOrderTransformerLabelTransformerLegacyOrderTransformerNamespaceTransformerPatchJson6902TransformerPatchStrategicMergeTransformerPatchTransformerPrefixSuffixTransformerReplicaCountTransformerSecretGenerator"
+const _BuiltinPluginType_name = "UnknownAnnotationsTransformerConfigMapGeneratorHashTransformerHelmChartInflationGeneratorImageTagTransformerInventoryTransformerKindFilterTransformerKindOrderTransformerLabelTransformerLegacyOrderTransformerNamespaceTransformerPatchJson6902TransformerPatchStrategicMergeTransformerPatchTransformerPrefixSuffixTransformerReplacementTransformerReplicaCountTransformerSecretGeneratorStarlarkTransformer"
 
-var _BuiltinPluginType_index = [...]uint16{0, 7, 29, 47, 62, 81, 101, 122, 142, 158, 180, 200, 224, 254, 270, 293, 316, 331}
+var _BuiltinPluginType_index = [...]uint16{0, 7, 29, 47, 62, 89, 108, 128, 149, 169, 185, 207, 227, 251, 281, 297, 320, 342, 365, 380, 399}
 
 func (i BuiltinPluginType) String() string {
 	if i < 0 || i >= BuiltinPluginType(len(_BuiltinPluginType_index)-1) {
@@ -16,6 +16,7 @@ const (
 	AnnotationsTransformer
 	ConfigMapGenerator
 	HashTransformer
+	HelmChartInflationGenerator
 	ImageTagTransformer
 	InventoryTransformer
 	KindFilterTransformer
@@ -27,8 +28,10 @@ const (
 	PatchStrategicMergeTransformer
 	PatchTransformer
 	PrefixSuffixTransformer
+	ReplacementTransformer
 	ReplicaCountTransformer
 	SecretGenerator
+	StarlarkTransformer
 )
 
 var stringToBuiltinPluginTypeMap map[string]BuiltinPluginType
@@ -57,8 +60,9 @@ func GetBuiltinPluginType(n string) BuiltinPluginType {
 }
 
 var GeneratorFactories = map[BuiltinPluginType]func() resmap.GeneratorPlugin{
-	ConfigMapGenerator: builtins.NewConfigMapGeneratorPlugin,
-	SecretGenerator:    builtins.NewSecretGeneratorPlugin,
+	ConfigMapGenerator:          builtins.NewConfigMapGeneratorPlugin,
+	HelmChartInflationGenerator: builtins.NewHelmChartInflationGeneratorPlugin,
+	SecretGenerator:             builtins.NewSecretGeneratorPlugin,
 }
 
 var TransformerFactories = map[BuiltinPluginType]func() resmap.TransformerPlugin{
@@ -75,5 +79,7 @@ var TransformerFactories = map[BuiltinPluginType]func() resmap.TransformerPlugin
 	PatchStrategicMergeTransformer: builtins.NewPatchStrategicMergeTransformerPlugin,
 	PatchTransformer:               builtins.NewPatchTransformerPlugin,
 	PrefixSuffixTransformer:        builtins.NewPrefixSuffixTransformerPlugin,
+	ReplacementTransformer:         builtins.NewReplacementTransformerPlugin,
 	ReplicaCountTransformer:        builtins.NewReplicaCountTransformerPlugin,
+	StarlarkTransformer:            builtins.NewStarlarkTransformerPlugin,
 }
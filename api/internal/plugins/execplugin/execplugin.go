@@ -4,11 +4,9 @@
 package execplugin
 
 import (
-	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
 
@@ -17,6 +15,7 @@ import (
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/api/resid"
 	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
 	"sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/yaml"
 )
@@ -42,6 +41,23 @@ type ExecPlugin struct {
 	// Plugin configuration data.
 	cfg []byte
 
+	// True if the plugin speaks the ResourceList protocol
+	// of KRM functions, rather than reading its configuration
+	// from a file and resources as a YAML stream.
+	resourceList bool
+
+	// How the plugin's process runs.
+	opts types.ExecPluginOptions
+
+	// If set, the content of the executable, e.g. as checked
+	// against a plugin policy, which is run from a private
+	// copy, rather than from path, where it might be replaced.
+	content []byte
+
+	// The apiVersion, kind and name of the configuration,
+	// naming the plugin in errors.
+	name string
+
 	// PluginHelpers
 	h *resmap.PluginHelpers
 }
@@ -50,6 +66,13 @@ func NewExecPlugin(p string) *ExecPlugin {
 	return &ExecPlugin{path: p}
 }
 
+// NewExecPluginWithOptions returns a plugin running
+// the executable at the given path per the options.
+func NewExecPluginWithOptions(
+	p string, opts types.ExecPluginOptions) *ExecPlugin {
+	return &ExecPlugin{path: p, opts: opts}
+}
+
 func (p *ExecPlugin) ErrIfNotExecutable() error {
 	f, err := os.Stat(p.path)
 	if err != nil {
@@ -65,6 +88,12 @@ func (p *ExecPlugin) Path() string {
 	return p.path
 }
 
+// PinContent makes the plugin run the given content,
+// rather than whatever is at its path when it runs.
+func (p *ExecPlugin) PinContent(content []byte) {
+	p.content = content
+}
+
 func (p *ExecPlugin) Args() []string {
 	return p.args
 }
@@ -76,9 +105,28 @@ func (p *ExecPlugin) Cfg() []byte {
 func (p *ExecPlugin) Config(h *resmap.PluginHelpers, config []byte) error {
 	p.h = h
 	p.cfg = config
+	p.resourceList = isFunctionConfig(config)
+	p.name = configName(config, p.path)
 	return p.processOptionalArgsFields()
 }
 
+// configName returns the apiVersion, kind and name of
+// the configuration, e.g. someteam.example.com/v1/SedTransformer/sed,
+// or else the given default.
+func configName(cfg []byte, dflt string) string {
+	var c struct {
+		APIVersion string `json:"apiVersion,omitempty"`
+		Kind       string `json:"kind,omitempty"`
+		Metadata   struct {
+			Name string `json:"name,omitempty"`
+		} `json:"metadata,omitempty"`
+	}
+	if err := yaml.Unmarshal(cfg, &c); err != nil || c.Kind == "" {
+		return dflt
+	}
+	return strings.Join(nonEmpty(c.APIVersion, c.Kind, c.Metadata.Name), "/")
+}
+
 type argsConfig struct {
 	ArgsOneLiner string `json:"argsOneLiner,omitempty" yaml:"argsOneLiner,omitempty"`
 	ArgsFromFile string `json:"argsFromFile,omitempty" yaml:"argsFromFile,omitempty"`
@@ -106,7 +154,13 @@ func (p *ExecPlugin) processOptionalArgsFields() error {
 }
 
 func (p *ExecPlugin) Generate() (resmap.ResMap, error) {
-	output, err := p.invokePlugin(nil)
+	var output []byte
+	var err error
+	if p.resourceList {
+		output, err = p.invokeFunction(nil)
+	} else {
+		output, err = p.invokePlugin(nil)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -124,6 +178,14 @@ func (p *ExecPlugin) Transform(rm resmap.ResMap) error {
 		return err
 	}
 
+	if p.resourceList {
+		output, err := p.invokeFunction(inputRM)
+		if err != nil {
+			return err
+		}
+		return p.updateResMapItems(output, rm)
+	}
+
 	// encode the ResMap so it can be fed to the plugin
 	resources, err := inputRM.AsYaml()
 	if err != nil {
@@ -143,14 +205,17 @@ func (p *ExecPlugin) Transform(rm resmap.ResMap) error {
 // invokePlugin writes plugin config to a temp file, then
 // passes the full temp file path as the first arg to a process
 // running the plugin binary.  Process output is returned.
+// The temp file is removed, whether the plugin fails or not.
 func (p *ExecPlugin) invokePlugin(input []byte) ([]byte, error) {
 	f, err := ioutil.TempFile("", tmpConfigFilePrefix)
 	if err != nil {
 		return nil, errors.Wrap(
 			err, "creating tmp plugin config file")
 	}
+	defer os.Remove(f.Name())
 	_, err = f.Write(p.cfg)
 	if err != nil {
+		f.Close()
 		return nil, errors.Wrap(
 			err, "writing plugin config to "+f.Name())
 	}
@@ -159,26 +224,26 @@ func (p *ExecPlugin) invokePlugin(input []byte) ([]byte, error) {
 		return nil, errors.Wrap(
 			err, "closing plugin config file "+f.Name())
 	}
-	//nolint:gosec
-	cmd := exec.Command(
-		p.path, append([]string{f.Name()}, p.args...)...)
-	cmd.Env = p.getEnv()
-	cmd.Stdin = bytes.NewReader(input)
-	cmd.Stderr = os.Stderr
-	if _, err := os.Stat(p.h.Loader().Root()); err == nil {
-		cmd.Dir = p.h.Loader().Root()
-	}
-	result, err := cmd.Output()
+	result, err := p.run(append([]string{f.Name()}, p.args...), input)
 	if err != nil {
-		return nil, errors.Wrapf(
-			err, "failure in plugin configured via %s; %v",
-			f.Name(), err.Error())
+		return nil, err
 	}
-	return result, os.Remove(f.Name())
+	return result, nil
 }
 
+// getEnv returns the environment of the plugin, that of
+// kustomize, or just the variables of it the options name,
+// plus the plugin's configuration.
 func (p *ExecPlugin) getEnv() []string {
 	env := os.Environ()
+	if p.opts.Env != nil {
+		env = nil
+		for _, k := range p.opts.Env {
+			if v, ok := os.LookupEnv(k); ok {
+				env = append(env, k+"="+v)
+			}
+		}
+	}
 	env = append(env,
 		"KUSTOMIZE_PLUGIN_CONFIG_STRING="+string(p.cfg),
 		"KUSTOMIZE_PLUGIN_CONFIG_ROOT="+p.h.Loader().Root())
@@ -241,6 +306,65 @@ func (p *ExecPlugin) updateResMapValues(output []byte, rm resmap.ResMap) error {
 	return nil
 }
 
+// updateResMapItems updates the given ResMap to hold the
+// resources in output, the items emitted by a function, which,
+// unlike a plain exec transformer, may add and drop resources.
+// An emitted resource with the id annotation replaces the value
+// of the resource with that id, as for updateResMapValues.
+// Others, including second copies of a resource, are added,
+// and resources not emitted at all are removed.
+func (p *ExecPlugin) updateResMapItems(output []byte, rm resmap.ResMap) error {
+	outputRM, err := p.h.ResmapFactory().NewResMapFromBytes(output)
+	if err != nil {
+		return err
+	}
+	kept := make(map[*resource.Resource]bool)
+	var added []*resource.Resource
+	for _, r := range outputRM.Resources() {
+		annotations := r.GetAnnotations()
+		idString, ok := annotations[idAnnotation]
+		delete(annotations, idAnnotation)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		r.SetAnnotations(annotations)
+		var res *resource.Resource
+		if ok {
+			id := resid.ResId{}
+			err := yaml.Unmarshal([]byte(idString), &id)
+			if err != nil {
+				return err
+			}
+			res, err = rm.GetByCurrentId(id)
+			if err != nil {
+				return fmt.Errorf("unable to find unique match to %s", id.String())
+			}
+		}
+		if res == nil || kept[res] {
+			added = append(added, r)
+			continue
+		}
+		kept[res] = true
+		res.Kunstructured = r.Kunstructured
+	}
+	for _, res := range rm.Resources() {
+		if kept[res] {
+			continue
+		}
+		err = rm.Remove(res.CurId())
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range added {
+		err = rm.Append(r)
+		if err != nil {
+			return errors.Wrapf(err, "adding resource emitted by %s", p.name)
+		}
+	}
+	return nil
+}
+
 // updateResourceOptions updates the generator options for each resource in the
 // given ResMap based on plugin provided annotations.
 func (p *ExecPlugin) UpdateResourceOptions(rm resmap.ResMap) (resmap.ResMap, error) {
@@ -5,8 +5,12 @@ package execplugin_test
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"sigs.k8s.io/kustomize/api/filesys"
 	. "sigs.k8s.io/kustomize/api/internal/plugins/execplugin"
@@ -193,3 +197,230 @@ func TestUpdateResourceOptionsWithInvalidHashAnnotationValues(t *testing.T) {
 		}
 	}
 }
+
+const generatorConfig = `
+apiVersion: someteam.example.com/v1
+kind: SomeGenerator
+metadata:
+  name: gen
+`
+
+// runGenerator runs the given shell script as the
+// generator configured by generatorConfig.
+func runGenerator(
+	t *testing.T, script string,
+	opts types.ExecPluginOptions) (resmap.ResMap, error) {
+	dir, err := ioutil.TempDir("", "kustomize-exec-plugin-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	p, _ := makePlugin(t, dir, script, generatorConfig, opts)
+	return p.Generate()
+}
+
+// makePlugin configures, with the given configuration, the
+// given shell script as an exec plugin in dir, returning
+// it and the factory for its resources.
+func makePlugin(
+	t *testing.T, dir, script, config string,
+	opts types.ExecPluginOptions) (*ExecPlugin, *resmap.Factory) {
+	path := filepath.Join(dir, "SomePlugin")
+	//nolint:gosec
+	err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"+script), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ldr, err := fLdr.NewLoader(
+		fLdr.RestrictionRootOnly, dir, filesys.MakeFsOnDisk())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rf := resmap.NewFactory(
+		resource.NewFactory(
+			kunstruct.NewKunstructuredFactoryImpl()), nil)
+	p := NewExecPluginWithOptions(path, opts)
+	err = p.Config(resmap.NewPluginHelpers(
+		ldr, valtest_test.MakeFakeValidator(), rf), []byte(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, rf
+}
+
+func TestExecPluginEnv(t *testing.T) {
+	for k, v := range map[string]string{"KEEP": "k", "DROP": "d"} {
+		keep, isSet := os.LookupEnv(k)
+		os.Setenv(k, v)
+		if isSet {
+			defer os.Setenv(k, keep)
+		} else {
+			defer os.Unsetenv(k)
+		}
+	}
+	m, err := runGenerator(t, `
+cat <<EOF
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: env
+data:
+  keep: "$KEEP"
+  drop: "$DROP"
+  root: "$KUSTOMIZE_PLUGIN_CONFIG_ROOT"
+EOF
+`, types.ExecPluginOptions{Env: []string{"KEEP"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := m.Resources()[0].GetStringMap("data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data["keep"] != "k" || data["drop"] != "" || data["root"] == "" {
+		t.Fatalf("unexpected environment: %v", data)
+	}
+}
+
+func TestExecPluginFailure(t *testing.T) {
+	_, err := runGenerator(t, `
+echo "config in $1" >&2
+echo oops >&2
+exit 3
+`, types.ExecPluginOptions{})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	prefix := "failure in plugin configured via " +
+		"someteam.example.com/v1/SomeGenerator/gen: exit status 3; stderr:\nconfig in "
+	if !strings.HasPrefix(err.Error(), prefix) ||
+		!strings.HasSuffix(err.Error(), "\noops") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfgFile := strings.TrimSuffix(
+		strings.TrimPrefix(err.Error(), prefix), "\noops")
+	if _, err = os.Stat(cfgFile); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed, got %v", cfgFile, err)
+	}
+}
+
+func TestExecPluginTimeout(t *testing.T) {
+	// The plugin's children are terminated too, else
+	// they'd hold its stdout open until they exit.
+	start := time.Now()
+	_, err := runGenerator(t, `
+sleep 30 &
+wait
+`, types.ExecPluginOptions{Timeout: 100 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(),
+		"someteam.example.com/v1/SomeGenerator/gen: timed out after 100ms") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("plugin ran for %v", elapsed)
+	}
+}
+
+func TestExecPluginTempWorkDir(t *testing.T) {
+	m, err := runGenerator(t, `
+cat <<EOF
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: dir
+data:
+  dir: "$(pwd)"
+EOF
+`, types.ExecPluginOptions{TempWorkDir: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dir, err := m.Resources()[0].GetString("data.dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(filepath.Base(dir), "kust-plugin-dir-") {
+		t.Fatalf("unexpected working directory %s", dir)
+	}
+	if _, err = os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed, got %v", dir, err)
+	}
+}
+
+// A function may add resources to, or drop them from,
+// the items of the ResourceList it's given.
+func TestExecPluginFunctionAddsAndDrops(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-exec-plugin-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// Edits the items, drops the one named drop,
+	// and adds one named added.
+	p, rf := makePlugin(t, dir, `
+sed s/one/two/ | awk '
+function flush() {
+  if (item !~ /name: drop/) printf "%s", item
+  item = ""
+}
+/^[a-zA-Z]/ {
+  flush()
+  if (inItems) print "- apiVersion: v1\n  kind: ConfigMap\n  metadata:\n    name: added"
+  inItems = $0 == "items:"
+  print
+  next
+}
+inItems && /^- / { flush() }
+inItems { item = item $0 "\n"; next }
+{ print }
+'
+`, `
+apiVersion: someteam.example.com/v1
+kind: SomeFunction
+metadata:
+  name: fn
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: example.com/some-function
+`, types.ExecPluginOptions{})
+	m, err := rf.NewResMapFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: keep
+data:
+  a: one
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: drop
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.Transform(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actual, err := m.AsYaml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = `apiVersion: v1
+data:
+  a: two
+kind: ConfigMap
+metadata:
+  name: keep
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: added
+`
+	if string(actual) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, actual)
+	}
+}
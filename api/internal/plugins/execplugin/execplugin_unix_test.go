@@ -0,0 +1,63 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !windows
+
+package execplugin_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func TestExecPluginInterrupted(t *testing.T) {
+	// As on timing out, the plugin's children are
+	// terminated too, else the run would wait for them.
+	dir, err := ioutil.TempDir("", "kustomize-interrupt-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	pidFile := filepath.Join(dir, "child.pid")
+	keep, isSet := os.LookupEnv("PID_FILE")
+	os.Setenv("PID_FILE", pidFile)
+	if isSet {
+		defer os.Setenv("PID_FILE", keep)
+	} else {
+		defer os.Unsetenv("PID_FILE")
+	}
+
+	// Once the plugin has started a child, kustomize
+	// is interrupted, as by Ctrl-C.
+	go func() {
+		deadline := time.Now().Add(10 * time.Second)
+		for time.Now().Before(deadline) {
+			if _, err := os.Stat(pidFile); err == nil {
+				_ = syscall.Kill(os.Getpid(), syscall.SIGINT)
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	start := time.Now()
+	_, err = runGenerator(t, `
+sleep 30 &
+echo $! > "$PID_FILE.tmp"
+mv "$PID_FILE.tmp" "$PID_FILE"
+wait
+`, types.ExecPluginOptions{Env: []string{"PID_FILE"}})
+	if err == nil || !strings.Contains(err.Error(),
+		"someteam.example.com/v1/SomeGenerator/gen: stopped on signal: interrupt") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("plugin ran for %v", elapsed)
+	}
+}
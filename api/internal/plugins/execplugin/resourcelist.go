@@ -0,0 +1,183 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package execplugin
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/kio/filters"
+	"sigs.k8s.io/yaml"
+)
+
+// resourceList is what a plugin speaking the ResourceList
+// protocol of KRM functions, as run by kustomize config run,
+// reads on stdin and writes on stdout.
+type resourceList struct {
+	APIVersion     string                   `json:"apiVersion"`
+	Kind           string                   `json:"kind"`
+	Items          []map[string]interface{} `json:"items"`
+	FunctionConfig map[string]interface{}   `json:"functionConfig,omitempty"`
+	Results        *result                  `json:"results,omitempty"`
+}
+
+// result is what a function found in the resources,
+// such as the violations of a validator.
+type result struct {
+	Name  string       `json:"name,omitempty"`
+	Items []resultItem `json:"items,omitempty"`
+}
+
+type resultItem struct {
+	Message     string `json:"message,omitempty"`
+	Severity    string `json:"severity,omitempty"`
+	ResourceRef struct {
+		APIVersion string `json:"apiVersion,omitempty"`
+		Kind       string `json:"kind,omitempty"`
+		Name       string `json:"name,omitempty"`
+		Namespace  string `json:"namespace,omitempty"`
+	} `json:"resourceRef,omitempty"`
+	Field struct {
+		Path string `json:"path,omitempty"`
+	} `json:"field,omitempty"`
+	File struct {
+		Path string `json:"path,omitempty"`
+	} `json:"file,omitempty"`
+}
+
+const severityError = "error"
+
+func (r resultItem) String() string {
+	var b strings.Builder
+	if r.Severity != "" {
+		fmt.Fprintf(&b, "[%s] ", r.Severity)
+	}
+	ref := r.ResourceRef
+	if ref.Kind != "" || ref.Name != "" {
+		b.WriteString(strings.Join(nonEmpty(
+			ref.APIVersion, ref.Kind, ref.Namespace, ref.Name), "/"))
+		if r.Field.Path != "" {
+			b.WriteString(" " + r.Field.Path)
+		}
+		b.WriteString(": ")
+	} else if r.File.Path != "" {
+		b.WriteString(r.File.Path + ": ")
+	}
+	b.WriteString(r.Message)
+	return b.String()
+}
+
+func nonEmpty(s ...string) []string {
+	var result []string
+	for _, x := range s {
+		if x != "" {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+// isFunctionConfig is true for the configuration of a
+// KRM function, which has the config.kubernetes.io/function
+// annotation, naming the function for kustomize config run.
+// Kustomize runs the plugin at the usual path, speaking
+// the ResourceList protocol to it.
+func isFunctionConfig(cfg []byte) bool {
+	var c struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations,omitempty"`
+		} `json:"metadata,omitempty"`
+	}
+	if err := yaml.Unmarshal(cfg, &c); err != nil {
+		return false
+	}
+	_, ok := c.Metadata.Annotations[filters.FunctionAnnotationKey]
+	return ok
+}
+
+// invokeFunction runs the plugin with a ResourceList holding
+// the resources of rm, which may be nil, and the plugin's
+// configuration as functionConfig, returning the items of the
+// ResourceList it emits, as YAML.  Results of error severity
+// fail the plugin; others are logged.
+func (p *ExecPlugin) invokeFunction(rm resmap.ResMap) ([]byte, error) {
+	in := resourceList{
+		APIVersion: kio.ResourceListAPIVersion,
+		Kind:       kio.ResourceListKind,
+		Items:      []map[string]interface{}{},
+	}
+	err := yaml.Unmarshal(p.cfg, &in.FunctionConfig)
+	if err != nil {
+		return nil, err
+	}
+	if rm != nil {
+		for _, r := range rm.Resources() {
+			in.Items = append(in.Items, r.Map())
+		}
+	}
+	input, err := yaml.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	output, runErr := p.run(p.args, input)
+	var out resourceList
+	err = yaml.Unmarshal(output, &out)
+	if runErr != nil {
+		if err == nil && out.Results != nil {
+			return nil, errors.Errorf("%v%s", runErr, out.Results.report())
+		}
+		return nil, runErr
+	}
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "parsing the output of function %s", p.name)
+	}
+	if out.Kind != kio.ResourceListKind {
+		return nil, fmt.Errorf(
+			"function %s emitted %q, not a %s",
+			p.name, out.Kind, kio.ResourceListKind)
+	}
+	if out.Results != nil {
+		if out.Results.hasErrors() {
+			return nil, errors.Errorf(
+				"function %s reported errors:%s", p.name, out.Results.report())
+		}
+		for _, item := range out.Results.Items {
+			log.Printf("%s: %s", p.name, item)
+		}
+	}
+	var b bytes.Buffer
+	for _, item := range out.Items {
+		y, err := yaml.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString("---\n")
+		b.Write(y)
+	}
+	return b.Bytes(), nil
+}
+
+func (r *result) hasErrors() bool {
+	for _, item := range r.Items {
+		if item.Severity == severityError {
+			return true
+		}
+	}
+	return false
+}
+
+// report lists the items of the result, one per line.
+func (r *result) report() string {
+	var b strings.Builder
+	for _, item := range r.Items {
+		b.WriteString("\n  " + item.String())
+	}
+	return b.String()
+}
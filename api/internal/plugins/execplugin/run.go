@@ -0,0 +1,148 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package execplugin
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	tmpWorkDirPrefix = "kust-plugin-dir-"
+	tmpCopyDirPrefix = "kust-plugin-copy-"
+
+	// How long a plugin that timed out has to exit,
+	// once asked to, before it's killed.
+	terminationGracePeriod = 2 * time.Second
+)
+
+// run runs the plugin with the given arguments and input,
+// returning its output.  If the plugin fails, outlasts the
+// timeout, or is stopped as kustomize is, the error holds
+// what it wrote to stderr; otherwise that's copied to the
+// stderr of kustomize.
+// The output is returned even if the plugin fails.
+func (p *ExecPlugin) run(args []string, input []byte) ([]byte, error) {
+	dir, cleanup, err := p.workDir()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	path := p.path
+	if p.content != nil {
+		var remove func()
+		path, remove, err = WritePrivateCopy(p.path, p.content)
+		if err != nil {
+			return nil, p.failure(err, nil)
+		}
+		defer remove()
+	}
+	//nolint:gosec
+	cmd := exec.Command(path, args...)
+	cmd.Env = p.getEnv()
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	inNewProcessGroup(cmd)
+	// Being in a group of its own, the plugin no longer gets
+	// the Ctrl-C of a terminal; it's stopped on the signals
+	// that would stop kustomize, to not outlive it.
+	interrupt := make(chan os.Signal, 1)
+	notifyOnInterrupt(interrupt)
+	defer signal.Stop(interrupt)
+	if err = cmd.Start(); err != nil {
+		return nil, p.failure(err, nil)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	var timeout <-chan time.Time
+	if p.opts.Timeout > 0 {
+		t := time.NewTimer(p.opts.Timeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+	select {
+	case err = <-done:
+	case <-timeout:
+		terminate(cmd, done)
+		err = fmt.Errorf("timed out after %v", p.opts.Timeout)
+	case sig := <-interrupt:
+		terminate(cmd, done)
+		err = fmt.Errorf("stopped on signal: %v", sig)
+	}
+	if err != nil {
+		return stdout.Bytes(), p.failure(err, stderr.Bytes())
+	}
+	_, _ = os.Stderr.Write(stderr.Bytes())
+	return stdout.Bytes(), nil
+}
+
+// workDir returns the directory to run the plugin in, if
+// any, and a func removing it once the plugin has exited.
+func (p *ExecPlugin) workDir() (string, func(), error) {
+	if p.opts.TempWorkDir {
+		dir, err := ioutil.TempDir("", tmpWorkDirPrefix)
+		if err != nil {
+			return "", nil, errors.Wrap(
+				err, "creating plugin working directory")
+		}
+		return dir, func() { os.RemoveAll(dir) }, nil
+	}
+	root := p.h.Loader().Root()
+	if _, err := os.Stat(root); err != nil {
+		// E.g. the root of an in-memory file system;
+		// run the plugin in the current directory.
+		root = ""
+	}
+	return root, func() {}, nil
+}
+
+// WritePrivateCopy writes the given content of the plugin at
+// the given path to a file of the same name, in a new directory
+// only the user can write to, returning the file's path and a
+// func removing the directory.
+func WritePrivateCopy(path string, content []byte) (string, func(), error) {
+	dir, err := ioutil.TempDir("", tmpCopyDirPrefix)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "copying plugin")
+	}
+	remove := func() { os.RemoveAll(dir) }
+	copyPath := filepath.Join(dir, filepath.Base(path))
+	// Else a process started meanwhile might inherit the file
+	// open for writing, and running it would fail as busy.
+	syscall.ForkLock.RLock()
+	//nolint:gosec
+	err = ioutil.WriteFile(copyPath, content, 0700)
+	syscall.ForkLock.RUnlock()
+	if err != nil {
+		remove()
+		return "", nil, errors.Wrap(err, "copying plugin")
+	}
+	return copyPath, remove, nil
+}
+
+// failure returns the error of a run of the plugin,
+// naming the plugin by its configuration.
+func (p *ExecPlugin) failure(err error, stderr []byte) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "failure in plugin configured via %s: %v", p.name, err)
+	if s := strings.TrimRight(string(stderr), "\n"); s != "" {
+		b.WriteString("; stderr:\n" + s)
+	}
+	return errors.New(b.String())
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !windows
+
+package execplugin
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// inNewProcessGroup makes the process of cmd the leader
+// of a new process group, holding all it starts, so that
+// they can be terminated together.
+func inNewProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// notifyOnInterrupt relays to c the signals asking
+// kustomize to stop.
+func notifyOnInterrupt(c chan<- os.Signal) {
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+}
+
+// terminate asks the process group of cmd to exit, and
+// kills it if the leader hasn't exited, per done, within
+// the grace period, returning once the leader has exited.
+func terminate(cmd *exec.Cmd, done <-chan error) {
+	pgid := -cmd.Process.Pid
+	_ = syscall.Kill(pgid, syscall.SIGTERM)
+	exited := false
+	select {
+	case <-done:
+		exited = true
+	case <-time.After(terminationGracePeriod):
+	}
+	// Kill any process of the group left behind, too.
+	_ = syscall.Kill(pgid, syscall.SIGKILL)
+	if !exited {
+		<-done
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package execplugin
+
+import (
+	"os"
+	"os/exec"
+)
+
+// inNewProcessGroup does nothing, processes on Windows
+// not being grouped as on Unix.
+func inNewProcessGroup(*exec.Cmd) {}
+
+// notifyOnInterrupt does nothing, the plugin getting
+// the Ctrl-C of the console along with kustomize.
+func notifyOnInterrupt(chan<- os.Signal) {}
+
+// terminate kills the process of cmd, returning once
+// it has exited, per done.
+func terminate(cmd *exec.Cmd, done <-chan error) {
+	_ = cmd.Process.Kill()
+	<-done
+}
@@ -11,6 +11,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/go-openapi/spec"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/internal/plugins/builtinhelpers"
@@ -23,8 +24,9 @@ import (
 )
 
 type Loader struct {
-	pc *types.PluginConfig
-	rf *resmap.Factory
+	pc         *types.PluginConfig
+	rf         *resmap.Factory
+	compiledIn *resmap.PluginRegistry
 }
 
 func NewLoader(
@@ -106,17 +108,49 @@ func isBuiltinPlugin(res *resource.Resource) bool {
 		res.GetGvk().Version == konfig.BuiltinPluginApiVersion
 }
 
+// Config returns the plugin configuration of the loader.
+func (l *Loader) Config() *types.PluginConfig {
+	return l.pc
+}
+
+// WithCrdSchemas returns a copy of the loader whose
+// plugins patch custom resources per the given OpenAPI
+// definitions.
+func (l *Loader) WithCrdSchemas(defs spec.Definitions) *Loader {
+	result := *l
+	result.rf = l.rf.WithCrdSchemas(defs)
+	return &result
+}
+
+// WithRegistry returns a copy of the loader that looks
+// for plugins in the given registry before any others.
+func (l *Loader) WithRegistry(r *resmap.PluginRegistry) *Loader {
+	result := *l
+	result.compiledIn = r
+	return &result
+}
+
 func (l *Loader) loadAndConfigurePlugin(
 	ldr ifc.Loader, v ifc.Validator, res *resource.Resource) (c resmap.Configurable, err error) {
-	if isBuiltinPlugin(res) {
+	c = l.compiledIn.MakePlugin(res.GetGvk())
+	switch {
+	case c != nil:
+		// Compiled into the program embedding kustomize,
+		// so made, and configured, just like a builtin.
+	case isBuiltinPlugin(res):
 		// Instead of looking for and loading a .so file, just
 		// instantiate the plugin from a generated factory
 		// function (see "pluginator").  Being able to do this
 		// is what makes a plugin "builtin".
 		c, err = l.makeBuiltinPlugin(res.GetGvk())
-	} else if l.pc.PluginRestrictions == types.PluginRestrictionsNone {
+	case l.pc.PluginRestrictions == types.PluginRestrictionsNone:
 		c, err = l.loadPlugin(res.OrgId())
-	} else {
+	case l.pc.PluginRestrictions == types.PluginRestrictionsPolicy:
+		err = l.errIfDenied(ldr, res.OrgId())
+		if err == nil {
+			c, err = l.loadPlugin(res.OrgId())
+		}
+	default:
 		err = types.NewErrOnlyBuiltinPluginsAllowed(res.OrgId().Kind)
 	}
 	if err != nil {
@@ -126,7 +160,8 @@ func (l *Loader) loadAndConfigurePlugin(
 	if err != nil {
 		return nil, errors.Wrapf(err, "marshalling yaml from res %s", res.OrgId())
 	}
-	err = c.Config(resmap.NewPluginHelpers(ldr, v, l.rf), yaml)
+	err = c.Config(
+		resmap.NewPluginHelpers(ldr, v, l.rf).WithPluginConfig(l.pc), yaml)
 	if err != nil {
 		return nil, errors.Wrapf(
 			err, "plugin %s fails configuration", res.OrgId())
@@ -147,9 +182,21 @@ func (l *Loader) makeBuiltinPlugin(r resid.Gvk) (resmap.Configurable, error) {
 
 func (l *Loader) loadPlugin(resId resid.ResId) (resmap.Configurable, error) {
 	// First try to load the plugin as an executable.
-	p := execplugin.NewExecPlugin(l.absolutePluginPath(resId))
-	err := p.ErrIfNotExecutable()
+	opts, err := l.execPluginOptions(resId)
+	if err != nil {
+		return nil, err
+	}
+	p := execplugin.NewExecPluginWithOptions(
+		l.absolutePluginPath(resId), opts)
+	err = p.ErrIfNotExecutable()
 	if err == nil {
+		content, err := l.errIfPolicyForbids(resId, p.Path())
+		if err != nil {
+			return nil, err
+		}
+		if content != nil {
+			p.PinContent(content)
+		}
 		return p, nil
 	}
 	if !os.IsNotExist(err) {
@@ -180,11 +227,15 @@ var registry = make(map[string]resmap.Configurable)
 
 func (l *Loader) loadGoPlugin(id resid.ResId) (resmap.Configurable, error) {
 	regId := relativePluginPath(id)
+	absPath := l.absolutePluginPath(id)
+	content, err := l.errIfPolicyForbids(id, absPath+".so")
+	if err != nil {
+		return nil, err
+	}
 	if c, ok := registry[regId]; ok {
 		return copyPlugin(c), nil
 	}
-	absPath := l.absolutePluginPath(id)
-	p, err := plugin.Open(absPath + ".so")
+	p, err := openGoPlugin(absPath+".so", content)
 	if err != nil {
 		return nil, errors.Wrapf(err, "plugin %s fails to load", absPath)
 	}
@@ -202,6 +253,22 @@ func (l *Loader) loadGoPlugin(id resid.ResId) (resmap.Configurable, error) {
 	return copyPlugin(c), nil
 }
 
+// openGoPlugin opens the Go plugin at the given path, or, if
+// content is given, a private copy of it, so that what's
+// loaded is what was checked, even if the file changes.
+func openGoPlugin(path string, content []byte) (*plugin.Plugin, error) {
+	if content == nil {
+		return plugin.Open(path)
+	}
+	copyPath, cleanup, err := execplugin.WritePrivateCopy(path, content)
+	if err != nil {
+		return nil, err
+	}
+	// Once loaded, the copy is no longer needed.
+	defer cleanup()
+	return plugin.Open(copyPath)
+}
+
 func copyPlugin(c resmap.Configurable) resmap.Configurable {
 	indirect := reflect.Indirect(reflect.ValueOf(c))
 	newIndirect := reflect.New(indirect.Type())
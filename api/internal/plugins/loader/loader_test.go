@@ -4,7 +4,14 @@
 package loader_test
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"sigs.k8s.io/kustomize/api/filesys"
 	. "sigs.k8s.io/kustomize/api/internal/plugins/loader"
@@ -15,6 +22,7 @@ import (
 	"sigs.k8s.io/kustomize/api/resource"
 	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
 	valtest_test "sigs.k8s.io/kustomize/api/testutils/valtest"
+	"sigs.k8s.io/kustomize/api/types"
 )
 
 const (
@@ -77,3 +85,210 @@ func TestLoader(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestLoaderPolicy(t *testing.T) {
+	home, err := ioutil.TempDir("", "kustomize-plugin-home-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	dir := filepath.Join(home, "someteam.example.com", "v1", "someservicegenerator")
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	script := []byte("#!/bin/sh\necho\n")
+	//nolint:gosec
+	err = ioutil.WriteFile(
+		filepath.Join(dir, "SomeServiceGenerator"), script, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(script)
+
+	rmF := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	fLdr, err := loader.NewLoader(
+		loader.RestrictionRootOnly,
+		filesys.Separator, filesys.MakeFsInMemory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := rmF.NewResMapFromBytes([]byte(someServiceGenerator))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := map[string]struct {
+		policy *types.PluginPolicy
+		errMsg string
+	}{
+		"noPolicy": {
+			errMsg: "plugin policy forbids plugin " +
+				"'someteam.example.com/v1/SomeServiceGenerator': " +
+				"not in the plugin policy",
+		},
+		"notListed": {
+			policy: &types.PluginPolicy{Plugins: []types.AllowedPlugin{{
+				APIVersion: "someteam.example.com/v1",
+				Kind:       "OtherGenerator",
+				Sha256:     hex.EncodeToString(sum[:]),
+			}}},
+			errMsg: "not in the plugin policy",
+		},
+		"changed": {
+			policy: &types.PluginPolicy{Plugins: []types.AllowedPlugin{{
+				APIVersion: "someteam.example.com/v1",
+				Kind:       "SomeServiceGenerator",
+				Sha256:     strings.Repeat("0", 64),
+			}}},
+			errMsg: "sha256 of " + filepath.Join(dir, "SomeServiceGenerator") +
+				" is " + hex.EncodeToString(sum[:]) + ", not " +
+				strings.Repeat("0", 64) + " as pinned",
+		},
+		"allowed": {
+			policy: &types.PluginPolicy{
+				DenyRemote: true,
+				Plugins: []types.AllowedPlugin{{
+					APIVersion: "someteam.example.com/v1",
+					Kind:       "SomeServiceGenerator",
+					Sha256:     hex.EncodeToString(sum[:]),
+				}},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := konfig.MakePluginConfig(types.PluginRestrictionsPolicy, home)
+			c.Policy = tc.policy
+			_, err := NewLoader(c, rmF).LoadGenerators(
+				fLdr, valtest_test.MakeFakeValidator(), m)
+			if tc.errMsg == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !types.IsErrPluginNotAllowed(err) ||
+				!strings.Contains(err.Error(), tc.errMsg) {
+				t.Fatalf("expected error %q, got %v", tc.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestLoaderPolicyTimeout(t *testing.T) {
+	home, err := ioutil.TempDir("", "kustomize-plugin-home-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	dir := filepath.Join(home, "someteam.example.com", "v1", "someservicegenerator")
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	script := []byte("#!/bin/sh\nsleep 30 &\nwait\n")
+	//nolint:gosec
+	err = ioutil.WriteFile(
+		filepath.Join(dir, "SomeServiceGenerator"), script, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(script)
+
+	rmF := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	fLdr, err := loader.NewLoader(
+		loader.RestrictionRootOnly,
+		filesys.Separator, filesys.MakeFsInMemory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := rmF.NewResMapFromBytes([]byte(someServiceGenerator))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The timeout of the plugin, if any, else the default.
+	for timeout, expected := range map[string]string{
+		"100ms": "timed out after 100ms",
+		"":      "timed out after 200ms",
+	} {
+		c := konfig.MakePluginConfig(types.PluginRestrictionsPolicy, home)
+		c.Exec.Timeout = 200 * time.Millisecond
+		c.Policy = &types.PluginPolicy{Plugins: []types.AllowedPlugin{{
+			APIVersion: "someteam.example.com/v1",
+			Kind:       "SomeServiceGenerator",
+			Sha256:     hex.EncodeToString(sum[:]),
+			Timeout:    timeout,
+		}}}
+		g, err := NewLoader(c, rmF).LoadGenerators(
+			fLdr, valtest_test.MakeFakeValidator(), m)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = g[0].Generate()
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("timeout %q: expected error %q, got %v", timeout, expected, err)
+		}
+	}
+}
+
+func TestLoaderPolicyRunsCheckedContent(t *testing.T) {
+	home, err := ioutil.TempDir("", "kustomize-plugin-home-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	dir := filepath.Join(home, "someteam.example.com", "v1", "someservicegenerator")
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "SomeServiceGenerator")
+	script := []byte(`#!/bin/sh
+cat <<EOF
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: checked
+EOF
+`)
+	//nolint:gosec
+	if err = ioutil.WriteFile(path, script, 0700); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(script)
+
+	rmF := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	fLdr, err := loader.NewLoader(
+		loader.RestrictionRootOnly,
+		filesys.Separator, filesys.MakeFsInMemory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := rmF.NewResMapFromBytes([]byte(someServiceGenerator))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := konfig.MakePluginConfig(types.PluginRestrictionsPolicy, home)
+	c.Policy = &types.PluginPolicy{Plugins: []types.AllowedPlugin{{
+		APIVersion: "someteam.example.com/v1",
+		Kind:       "SomeServiceGenerator",
+		Sha256:     hex.EncodeToString(sum[:]),
+	}}}
+	g, err := NewLoader(c, rmF).LoadGenerators(
+		fLdr, valtest_test.MakeFakeValidator(), m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Replacing the plugin once it's checked changes nothing.
+	//nolint:gosec
+	if err = ioutil.WriteFile(path, []byte("#!/bin/sh\nexit 1\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	rm, err := g[0].Generate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rm.Size() != 1 || rm.Resources()[0].GetName() != "checked" {
+		t.Fatalf("unexpected resources: %v", rm.Resources())
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/ifc"
+	fLdr "sigs.k8s.io/kustomize/api/loader"
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// errIfDenied fails if the plugin policy denies all
+// plugins configured by kustomizations that ldr reads,
+// i.e. those of remote bases, when it says so.
+func (l *Loader) errIfDenied(ldr ifc.Loader, id resid.ResId) error {
+	if l.pc.Policy != nil && l.pc.Policy.DenyRemote && fLdr.IsRemote(ldr) {
+		return types.NewErrPluginNotAllowed(
+			pluginName(id), "configured in remote base "+ldr.Root())
+	}
+	return nil
+}
+
+// errIfPolicyForbids fails closed, if plugins are restricted
+// by a policy, unless it allows the plugin with the given id,
+// and the file at the given path, its executable or .so file,
+// has the checksum the policy pins.  If so, it returns the
+// content checked, which is what must be run: the file may
+// be replaced once checked.  Without a policy, it returns nil.
+func (l *Loader) errIfPolicyForbids(id resid.ResId, path string) ([]byte, error) {
+	if l.pc.PluginRestrictions != types.PluginRestrictionsPolicy {
+		return nil, nil
+	}
+	name := pluginName(id)
+	var allowed *types.AllowedPlugin
+	if l.pc.Policy != nil {
+		allowed = l.pc.Policy.Allowed(apiVersion(id.Gvk), id.Kind)
+	}
+	if allowed == nil {
+		return nil, types.NewErrPluginNotAllowed(name, "not in the plugin policy")
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, types.NewErrPluginNotAllowed(name, err.Error())
+	}
+	sum := sha256.Sum256(content)
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, allowed.Sha256) {
+		return nil, types.NewErrPluginNotAllowed(name, fmt.Sprintf(
+			"sha256 of %s is %s, not %s as pinned", path, actual, allowed.Sha256))
+	}
+	return content, nil
+}
+
+// execPluginOptions returns the options of the exec plugin
+// with the given id: those of the plugin configuration, but
+// with any timeout the plugin policy sets for the plugin.
+func (l *Loader) execPluginOptions(id resid.ResId) (types.ExecPluginOptions, error) {
+	opts := l.pc.Exec
+	if l.pc.PluginRestrictions != types.PluginRestrictionsPolicy || l.pc.Policy == nil {
+		return opts, nil
+	}
+	allowed := l.pc.Policy.Allowed(apiVersion(id.Gvk), id.Kind)
+	if allowed == nil {
+		// Refused by errIfPolicyForbids.
+		return opts, nil
+	}
+	var err error
+	opts.Timeout, err = allowed.ExecTimeout(opts.Timeout)
+	return opts, err
+}
+
+func pluginName(id resid.ResId) string {
+	return apiVersion(id.Gvk) + "/" + id.Kind
+}
+
+func apiVersion(gvk resid.Gvk) string {
+	if gvk.Group == "" {
+		return gvk.Version
+	}
+	return gvk.Group + "/" + gvk.Version
+}
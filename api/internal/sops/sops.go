@@ -0,0 +1,58 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sops decrypts files encrypted by sops
+// (github.com/mozilla/sops), in memory, with the decrypt
+// package of sops itself, and so with the keys, and key
+// services, that sops would use.
+package sops
+
+import (
+	"github.com/pkg/errors"
+	"go.mozilla.org/sops/v3/age"
+	"go.mozilla.org/sops/v3/cmd/sops/formats"
+	"go.mozilla.org/sops/v3/decrypt"
+)
+
+const (
+	// Environment variables that, as for sops, hold
+	// age identities, or name a file holding them.
+	AgeKeyEnv     = age.SopsAgeKeyEnv
+	AgeKeyFileEnv = age.SopsAgeKeyFileEnv
+	// Environment variable naming the gnupg home
+	// directory.  PGP keys are read from its secring.gpg,
+	// else the gpg program, and so gpg-agent, decrypts.
+	GnupgHomeEnv = "GNUPGHOME"
+)
+
+// Format is the format of an encrypted file, which
+// sops infers from the file's extension.
+type Format = formats.Format
+
+const (
+	// Binary content, encrypted as a whole and held
+	// in the "data" field of a JSON document.
+	Binary = formats.Binary
+	Yaml   = formats.Yaml
+	Json   = formats.Json
+	Dotenv = formats.Dotenv
+	Ini    = formats.Ini
+)
+
+// FormatForPath returns the format of the file at path.
+func FormatForPath(path string) Format {
+	return formats.FormatForPath(path)
+}
+
+// Decrypt returns the cleartext of the encrypted content
+// of the file at path, in the file's format and without
+// the sops metadata.  The integrity of the cleartext is
+// checked against the message authentication code
+// recorded by sops.
+func Decrypt(path string, content []byte) ([]byte, error) {
+	result, err := decrypt.DataWithFormat(content, FormatForPath(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "decrypting '%s'", path)
+	}
+	return result, nil
+}
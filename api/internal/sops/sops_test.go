@@ -0,0 +1,254 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package sops_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "sigs.k8s.io/kustomize/api/internal/sops"
+	sopstest_test "sigs.k8s.io/kustomize/api/testutils/sopstest"
+)
+
+func TestDecrypt(t *testing.T) {
+	keys := sopstest_test.MakeKeys(t)
+	testCases := map[string]struct {
+		path      string
+		cleartext string
+		expected  string
+	}{
+		"yaml": {
+			path: "secrets.yaml",
+			cleartext: `db:
+    user: admin
+    port: 5432
+    ratio: 0.5
+    tls: true
+    hosts:
+        - a.example.com
+        - b.example.com
+cert: |
+    line one
+    line two
+`,
+		},
+		"json": {
+			path:      "secrets.json",
+			cleartext: `{"db": {"password": "hunter2", "port": 5432}}`,
+			expected: `{
+	"db": {
+		"password": "hunter2",
+		"port": 5432
+	}
+}`,
+		},
+		"dotenv": {
+			path: "secrets.env",
+			cleartext: `USER=admin
+PASSWORD=hunter2
+MOTD=one\ntwo
+`,
+		},
+		"binary": {
+			path:      "id_rsa",
+			cleartext: "-----BEGIN KEY-----\nabc\n-----END KEY-----\n",
+		},
+	}
+	for _, usePGP := range []bool{false, true} {
+		for n, tc := range testCases {
+			t.Run(n, func(t *testing.T) {
+				encrypted := keys.Encrypt(tc.path, tc.cleartext)
+				if strings.Contains(string(encrypted), "admin") ||
+					strings.Contains(string(encrypted), "hunter2") {
+					t.Fatalf("not encrypted:\n%s", encrypted)
+				}
+				restore := keys.Install(usePGP)
+				actual, err := Decrypt(tc.path, encrypted)
+				restore()
+				if err != nil {
+					t.Fatalf("pgp %v: unexpected error: %v", usePGP, err)
+				}
+				expected := tc.expected
+				if expected == "" {
+					expected = tc.cleartext
+				}
+				if string(actual) != expected {
+					t.Fatalf("pgp %v: expected\n%s\nbut got\n%s",
+						usePGP, expected, actual)
+				}
+			})
+		}
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	keys := sopstest_test.MakeKeys(t)
+	encrypted := string(keys.Encrypt("s.yaml", "password: hunter2\n"))
+
+	restore := sopstest_test.MakeKeys(t).Install(false)
+	_, err := Decrypt("s.yaml", []byte(encrypted))
+	restore()
+	if err == nil || !strings.Contains(err.Error(),
+		"decrypting 's.yaml': Error getting data key") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer keys.Install(false)()
+	_, err = Decrypt("s.yaml", []byte("password: hunter2\n"))
+	if err == nil || !strings.Contains(err.Error(), "sops metadata not found") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A value is bound to its path.
+	tampered := strings.Replace(encrypted, "password:", "passwd:", 1)
+	_, err = Decrypt("s.yaml", []byte(tampered))
+	if err == nil || !strings.Contains(err.Error(), "Could not decrypt value") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A cleartext value is covered by the
+	// message authentication code.
+	tampered = strings.Replace(encrypted, "sops:\n",
+		"user_unencrypted: admin\nsops:\n", 1)
+	_, err = Decrypt("s.yaml", []byte(tampered))
+	if err == nil || !strings.Contains(err.Error(),
+		"Failed to verify data integrity") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// installTestdataKeys points the environment at the keys
+// in testdata/keys: with usePGP, only the PGP key, else
+// only the age identity, returning a func that restores
+// the environment.
+func installTestdataKeys(t *testing.T, usePGP bool) func() {
+	keys, err := filepath.Abs(filepath.Join("testdata", "keys"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		AgeKeyEnv:     "",
+		AgeKeyFileEnv: filepath.Join(keys, "age.txt"),
+		GnupgHomeEnv:  filepath.Join(keys, "none"),
+	}
+	if usePGP {
+		env[AgeKeyFileEnv] = filepath.Join(keys, "none")
+		env[GnupgHomeEnv] = keys
+	}
+	return setEnv(env)
+}
+
+// setEnv sets the given environment variables, unsetting
+// those set to "", returning a func that restores them.
+func setEnv(env map[string]string) func() {
+	var restore []func()
+	for k, v := range env {
+		k := k
+		old, isSet := os.LookupEnv(k)
+		if v == "" {
+			os.Unsetenv(k)
+		} else {
+			os.Setenv(k, v)
+		}
+		if isSet {
+			restore = append(restore, func() { os.Setenv(k, old) })
+		} else {
+			restore = append(restore, func() { os.Unsetenv(k) })
+		}
+	}
+	return func() {
+		for _, f := range restore {
+			f()
+		}
+	}
+}
+
+// The files in testdata/encrypted were encrypted by sops
+// 3.7.3, for the age and PGP keys in testdata/keys, and
+// those in testdata/decrypted are what sops decrypts them
+// to.  For secret-encrypted-regex.yaml, sops was given
+// --encrypted-regex '^(data|stringData)$', for
+// secret-unencrypted-regex.yaml, --unencrypted-regex
+// '^(apiVersion|kind|metadata|type)$', and for
+// suffixes.yaml, --unencrypted-suffix _unencrypted.
+func TestDecryptSopsFiles(t *testing.T) {
+	files, err := ioutil.ReadDir(filepath.Join("testdata", "encrypted"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, usePGP := range []bool{false, true} {
+		restore := installTestdataKeys(t, usePGP)
+		for _, f := range files {
+			encrypted, err := ioutil.ReadFile(
+				filepath.Join("testdata", "encrypted", f.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, err := ioutil.ReadFile(
+				filepath.Join("testdata", "decrypted", f.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := Decrypt(f.Name(), encrypted)
+			if err != nil {
+				t.Errorf("%s, pgp %v: unexpected error: %v", f.Name(), usePGP, err)
+				continue
+			}
+			if string(actual) != string(expected) {
+				t.Errorf("%s, pgp %v: expected\n%s\nbut got\n%s",
+					f.Name(), usePGP, expected, actual)
+			}
+		}
+		restore()
+	}
+}
+
+// With GnuPG 2.1 or later, secret keys are held by
+// gpg-agent rather than in secring.gpg, and gpg decrypts.
+func TestDecryptWithGpgAgent(t *testing.T) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg isn't installed")
+	}
+	home, err := ioutil.TempDir("", "kustomize-gnupg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	restore := setEnv(map[string]string{
+		AgeKeyEnv:     "",
+		AgeKeyFileEnv: filepath.Join(home, "none"),
+		GnupgHomeEnv:  home,
+	})
+	defer restore()
+	out, err := exec.Command("gpg", "--batch", "--import",
+		filepath.Join("testdata", "keys", "secring.gpg")).CombinedOutput()
+	if err != nil {
+		t.Fatalf("importing key: %v\n%s", err, out)
+	}
+	defer exec.Command("gpgconf", "--kill", "gpg-agent").Run()
+	if _, err = os.Stat(filepath.Join(home, "secring.gpg")); !os.IsNotExist(err) {
+		t.Skip("gpg keeps secring.gpg")
+	}
+	encrypted, err := ioutil.ReadFile(
+		filepath.Join("testdata", "encrypted", "secrets.env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := ioutil.ReadFile(
+		filepath.Join("testdata", "decrypted", "secrets.env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := Decrypt("secrets.env", encrypted)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(actual) != string(expected) {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, actual)
+	}
+}
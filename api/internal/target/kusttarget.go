@@ -8,17 +8,23 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"path/filepath"
 	"strings"
+	"sync"
 
+	"github.com/go-openapi/spec"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/api/builtins"
+	"sigs.k8s.io/kustomize/api/filesys"
 	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/internal/accumulator"
 	"sigs.k8s.io/kustomize/api/internal/plugins/builtinconfig"
 	"sigs.k8s.io/kustomize/api/internal/plugins/builtinhelpers"
 	"sigs.k8s.io/kustomize/api/internal/plugins/loader"
 	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/kustvalidate"
 	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
 	"sigs.k8s.io/kustomize/api/transform"
 	"sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/yaml"
@@ -33,6 +39,16 @@ type KustTarget struct {
 	tFactory      resmap.PatchFactory
 	pLdr          *loader.Loader
 	dynamic       *types.Kustomization
+	kustFileName  string
+	// If non-nil, the origin of the kustomization's
+	// directory, and a request to track resource origins.
+	origin *resource.Origin
+	// Tokens for the goroutines reading resources, shared
+	// by all targets in a build.  If nil, reads are serial.
+	workers chan struct{}
+	// OpenAPI definitions from the CRDs and openapi file of
+	// the kustomization and its bases, once accumulated.
+	crdSchemas spec.Definitions
 }
 
 // NewKustTarget returns a new instance of KustTarget.
@@ -51,12 +67,27 @@ func NewKustTarget(
 	}
 }
 
+// TrackOrigins makes the target record, on each resource,
+// where it was loaded or generated from, and which
+// transformers modified it.
+func (kt *KustTarget) TrackOrigins() {
+	kt.origin = kt.subOrigin(
+		kt.ldr, &resource.Origin{Path: filesys.SelfDir})
+}
+
 // Load attempts to load the target's kustomization file.
 func (kt *KustTarget) Load() error {
-	content, err := loadKustFile(kt.ldr)
+	content, fileName, err := loadKustFile(kt.ldr)
 	if err != nil {
 		return err
 	}
+	issues := kustvalidate.Validate(
+		filepath.Join(kt.ldr.Root(), fileName), content).Errors()
+	if len(issues) > 0 {
+		return fmt.Errorf(
+			"invalid kustomization file:\n%s",
+			strings.TrimSuffix(issues.String(), "\n"))
+	}
 	content = types.FixKustomizationPreUnmarshalling(content)
 	var k types.Kustomization
 	err = unmarshal(content, &k)
@@ -72,26 +103,29 @@ func (kt *KustTarget) Load() error {
 	}
 	kt.kustomization = &k
 	kt.dynamic = &types.Kustomization{}
+	kt.kustFileName = fileName
 	return nil
 }
 
-func loadKustFile(ldr ifc.Loader) ([]byte, error) {
+func loadKustFile(ldr ifc.Loader) ([]byte, string, error) {
 	var content []byte
+	var fileName string
 	match := 0
 	for _, kf := range konfig.RecognizedKustomizationFileNames() {
 		c, err := ldr.Load(kf)
 		if err == nil {
 			match += 1
 			content = c
+			fileName = kf
 		}
 	}
 	switch match {
 	case 0:
-		return nil, NewErrMissingKustomization(ldr.Root())
+		return nil, "", NewErrMissingKustomization(ldr.Root())
 	case 1:
-		return content, nil
+		return content, fileName, nil
 	default:
-		return nil, fmt.Errorf(
+		return nil, "", fmt.Errorf(
 			"Found multiple kustomization files under: %s\n", ldr.Root())
 	}
 }
@@ -152,6 +186,14 @@ func (kt *KustTarget) makeCustomizedResMap(
 	return ra.ResMap(), nil
 }
 
+// CrdSchemas returns the OpenAPI definitions, by type name,
+// from the CRDs and openapi files of the kustomization and
+// its bases.  They're known once a customized ResMap has
+// been made.
+func (kt *KustTarget) CrdSchemas() spec.Definitions {
+	return kt.crdSchemas
+}
+
 func (kt *KustTarget) addHashesToNames(
 	ra *accumulator.ResAccumulator) error {
 	p := builtins.NewHashTransformerPlugin()
@@ -159,7 +201,8 @@ func (kt *KustTarget) addHashesToNames(
 	if err != nil {
 		return err
 	}
-	return ra.Transform(p)
+	return ra.Transform(trackTransformer(
+		p, kt.configuredBy(builtinhelpers.HashTransformer.String())))
 }
 
 func (kt *KustTarget) computeInventory(
@@ -188,7 +231,8 @@ func (kt *KustTarget) computeInventory(
 	if err != nil {
 		return err
 	}
-	return ra.Transform(p)
+	return ra.Transform(trackTransformer(
+		p, kt.configuredBy(builtinhelpers.InventoryTransformer.String())))
 }
 
 // AccumulateTarget returns a new ResAccumulator,
@@ -197,7 +241,7 @@ func (kt *KustTarget) computeInventory(
 // not yet fixed.
 func (kt *KustTarget) AccumulateTarget() (
 	ra *accumulator.ResAccumulator, err error) {
-	ra, err = kt.accumulateTarget()
+	ra, err = kt.accumulateTarget(accumulator.MakeEmptyAccumulator())
 	if err != nil {
 		return nil, err
 	}
@@ -214,10 +258,13 @@ func (kt *KustTarget) AccumulateTarget() (
 	return ra, nil
 }
 
-func (kt *KustTarget) accumulateTarget() (
-	ra *accumulator.ResAccumulator, err error) {
-	ra = accumulator.MakeEmptyAccumulator()
-	err = kt.accumulateResources(ra, kt.kustomization.Resources)
+// accumulateTarget adds the target's resources to the given
+// accumulator, then customizes everything the accumulator
+// holds, which for a component includes the resources of
+// the kustomization that includes it.
+func (kt *KustTarget) accumulateTarget(ra *accumulator.ResAccumulator) (
+	*accumulator.ResAccumulator, error) {
+	err := kt.accumulateResources(ra, kt.kustomization.Resources)
 	if err != nil {
 		return nil, errors.Wrap(err, "accumulating resources")
 	}
@@ -241,10 +288,35 @@ func (kt *KustTarget) accumulateTarget() (
 		return nil, errors.Wrapf(
 			err, "merging CRDs %v", crdTc)
 	}
+	crdSchemas, err := accumulator.LoadSchemasFromCRDs(
+		kt.ldr, kt.kustomization.Crds)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "loading CRDs %v", kt.kustomization.Crds)
+	}
+	ra.MergeCrdSchemas(crdSchemas)
+	if kt.kustomization.OpenAPI != nil {
+		defs, err := accumulator.LoadSchemasFromOpenAPI(
+			kt.ldr, kt.kustomization.OpenAPI.Path)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "loading openapi %s", kt.kustomization.OpenAPI.Path)
+		}
+		ra.MergeCrdSchemas(defs)
+	}
+	// Patches of custom resources use the schemas of
+	// these and the bases' CRDs.
+	kt.crdSchemas = ra.CrdSchemas()
 	err = kt.runGenerators(ra)
 	if err != nil {
 		return nil, err
 	}
+	// Components see the generated resources, and their
+	// changes are subject to the transformers.
+	err = kt.accumulateComponents(ra, kt.kustomization.Components)
+	if err != nil {
+		return nil, errors.Wrap(err, "accumulating components")
+	}
 	err = kt.runTransformers(ra)
 	if err != nil {
 		return nil, err
@@ -284,7 +356,16 @@ func (kt *KustTarget) configureExternalGenerators() ([]resmap.Generator, error)
 	if err != nil {
 		return nil, err
 	}
-	return kt.pLdr.LoadGenerators(kt.ldr, kt.validator, ra.ResMap())
+	gs, err := kt.pLdr.LoadGenerators(kt.ldr, kt.validator, ra.ResMap())
+	if err != nil {
+		return nil, err
+	}
+	if kt.origin != nil {
+		for i, res := range ra.ResMap().Resources() {
+			gs[i] = trackGenerator(gs[i], pluginOrigin(res))
+		}
+	}
+	return gs, nil
 }
 
 func (kt *KustTarget) absorbDynamicKustomization(ra *accumulator.ResAccumulator) {
@@ -318,7 +399,17 @@ func (kt *KustTarget) configureExternalTransformers() ([]resmap.Transformer, err
 	if err != nil {
 		return nil, err
 	}
-	return kt.pLdr.LoadTransformers(kt.ldr, kt.validator, ra.ResMap())
+	ts, err := kt.pLdr.WithCrdSchemas(kt.crdSchemas).LoadTransformers(
+		kt.ldr, kt.validator, ra.ResMap())
+	if err != nil {
+		return nil, err
+	}
+	if kt.origin != nil {
+		for i, res := range ra.ResMap().Resources() {
+			ts[i] = trackTransformer(ts[i], pluginOrigin(res))
+		}
+	}
+	return ts, nil
 }
 
 func (kt *KustTarget) LoadRerorderTransformer(transformername string) (resmap.Transformer, error) {
@@ -355,7 +446,8 @@ func (kt *KustTarget) LoadRerorderTransformer(transformername string) (resmap.Tr
 	if err != nil {
 		return nil, err
 	}
-	lts, err := kt.pLdr.LoadTransformers(kt.ldr, kt.validator, ra.ResMap())
+	lts, err := kt.pLdr.WithCrdSchemas(kt.crdSchemas).LoadTransformers(
+		kt.ldr, kt.validator, ra.ResMap())
 	if err != nil {
 		return nil, err
 	}
@@ -366,69 +458,238 @@ func (kt *KustTarget) LoadRerorderTransformer(transformername string) (resmap.Tr
 }
 
 // accumulateResources fills the given resourceAccumulator
-// with resources read from the given list of paths.
+// with resources read from the given list of paths.  Within
+// the target's concurrency limit, paths are read at the same
+// time, but their resources are merged into the accumulator
+// in list order, so the result doesn't depend on the limit.
 func (kt *KustTarget) accumulateResources(
 	ra *accumulator.ResAccumulator, paths []string) error {
-	for _, path := range paths {
-		ldr, err := kt.ldr.New(path)
-		if err == nil {
-			err = kt.accumulateDirectory(ra, ldr)
-			if err != nil {
-				return err
-			}
-		} else {
-			err2 := kt.accumulateFile(ra, path)
-			if err2 != nil {
-				// Log ldr.New() error to highlight git failures.
-				log.Print(err.Error())
-				return err2
-			}
+	pending := make([]*pendingResources, len(paths))
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		i, path := i, path
+		if kt.tryAcquireWorker() {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer kt.releaseWorker()
+				pending[i] = kt.readResources(path)
+			}()
+			continue
+		}
+		pending[i] = kt.readResources(path)
+		if pending[i].err != nil {
+			break
+		}
+	}
+	wg.Wait()
+	for _, p := range pending {
+		if p.err != nil {
+			return p.err
+		}
+		err := p.mergeInto(ra)
+		if err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func (kt *KustTarget) accumulateDirectory(
-	ra *accumulator.ResAccumulator, ldr ifc.Loader) error {
+// SetConcurrency lets the target, and the targets it recurses
+// into, read up to n entries of their resource lists at once,
+// e.g. to clone remote bases in parallel.  The limit applies
+// to the whole tree of targets.  Values below two mean one
+// entry at a time.
+func (kt *KustTarget) SetConcurrency(n int) {
+	kt.workers = nil
+	if n > 1 {
+		// The calling goroutine counts as a worker.
+		kt.workers = make(chan struct{}, n-1)
+	}
+}
+
+// tryAcquireWorker reserves a goroutine within the concurrency
+// limit, if any are free.  It never blocks, since a target
+// waiting on its own resources to be read holds its worker,
+// and blocking could starve the targets it recursed into.
+func (kt *KustTarget) tryAcquireWorker() bool {
+	select {
+	case kt.workers <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (kt *KustTarget) releaseWorker() {
+	<-kt.workers
+}
+
+// pendingResources holds the resources read from one
+// entry of a resource list, waiting to be merged.
+type pendingResources struct {
+	// The directory or file the resources were read from.
+	path string
+	// The resources, and for a directory, its vars, config, etc.
+	subRa *accumulator.ResAccumulator
+	// Whether path is a directory, and the vars declared
+	// by its kustomization.
+	isDir bool
+	vars  []types.Var
+	err   error
+}
+
+// readResources reads the resources at the given path, which
+// is a directory holding a kustomization, or a file.
+func (kt *KustTarget) readResources(path string) *pendingResources {
+	ldr, err := kt.ldr.New(path)
+	if err == nil {
+		return kt.readDirectory(
+			ldr, kt.subOrigin(ldr, kt.origin.Append(path)))
+	}
+	p := kt.readFile(path)
+	if p.err != nil {
+		// Log ldr.New() error to highlight git failures.
+		log.Print(err.Error())
+	}
+	return p
+}
+
+func (kt *KustTarget) readDirectory(
+	ldr ifc.Loader, origin *resource.Origin) *pendingResources {
 	defer ldr.Cleanup()
+	p := &pendingResources{path: ldr.Root(), isDir: true}
 	subKt := NewKustTarget(
 		ldr, kt.validator, kt.rFactory, kt.tFactory, kt.pLdr)
+	subKt.origin = origin
+	subKt.workers = kt.workers
 	err := subKt.Load()
 	if err != nil {
-		return errors.Wrapf(
+		p.err = errors.Wrapf(
 			err, "couldn't make target for path '%s'", ldr.Root())
+		return p
+	}
+	if subKt.kustomization.Kind == types.ComponentKind {
+		p.err = fmt.Errorf(
+			"'%s' is a %s; list it in components, not resources",
+			ldr.Root(), types.ComponentKind)
+		return p
 	}
 
 	// Load the resources in the sub folders. Even if the subdirectory
 	// had already been visited by the kustomize, the subRa accumulator
 	// will contain its own copies of the resources.
-	subRa, err := subKt.accumulateTarget()
+	p.subRa, err = subKt.accumulateTarget(accumulator.MakeEmptyAccumulator())
 	if err != nil {
-		return errors.Wrapf(
+		p.err = errors.Wrapf(
 			err, "recursed accumulation of path '%s'", ldr.Root())
+		return p
 	}
+	p.vars = subKt.kustomization.Vars
+	return p
+}
 
+// accumulateComponents applies the components at the given
+// paths, in order, to the resources in the accumulator.
+func (kt *KustTarget) accumulateComponents(
+	ra *accumulator.ResAccumulator, paths []string) error {
+	for _, path := range paths {
+		ldr, err := kt.ldr.New(path)
+		if err != nil {
+			return errors.Wrapf(
+				err, "couldn't make loader for component '%s'", path)
+		}
+		err = kt.accumulateComponent(
+			ra, ldr, kt.subOrigin(ldr, kt.origin.Append(path)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (kt *KustTarget) accumulateComponent(
+	ra *accumulator.ResAccumulator, ldr ifc.Loader,
+	origin *resource.Origin) error {
+	defer ldr.Cleanup()
+	subKt := NewKustTarget(
+		ldr, kt.validator, kt.rFactory, kt.tFactory, kt.pLdr)
+	subKt.origin = origin
+	subKt.workers = kt.workers
+	err := subKt.Load()
+	if err != nil {
+		return errors.Wrapf(
+			err, "couldn't make target for component '%s'", ldr.Root())
+	}
+	if subKt.kustomization.Kind != types.ComponentKind {
+		return fmt.Errorf(
+			"'%s' is a %s; list it in resources, not components",
+			ldr.Root(), subKt.kustomization.Kind)
+	}
+	// Unlike a base, a component works on the
+	// accumulator of the kustomization including it.
+	_, err = subKt.accumulateTarget(ra)
+	if err != nil {
+		return errors.Wrapf(
+			err, "recursed accumulation of component '%s'", ldr.Root())
+	}
+	err = ra.MergeVars(subKt.kustomization.Vars)
+	if err != nil {
+		return errors.Wrapf(
+			err, "merging vars %v", subKt.kustomization.Vars)
+	}
+	return nil
+}
+
+func (kt *KustTarget) readFile(path string) *pendingResources {
+	p := &pendingResources{path: path}
+	resources, err := kt.rFactory.FromFile(kt.ldr, path)
+	if err != nil {
+		p.err = errors.Wrapf(err, "accumulating resources from '%s'", path)
+		return p
+	}
+	if kt.origin != nil {
+		for _, r := range resources.Resources() {
+			r.SetOrigin(kt.origin.Append(path))
+		}
+	}
+	p.subRa = accumulator.MakeEmptyAccumulator()
+	err = p.subRa.AppendAll(resources)
+	if err != nil {
+		p.err = errors.Wrapf(err, "accumulating resources from '%s'", path)
+	}
+	return p
+}
+
+// mergeInto merges the pending resources into the given
+// accumulator.  Since it depends on what the accumulator
+// already holds, pending resources must be merged in order.
+func (p *pendingResources) mergeInto(ra *accumulator.ResAccumulator) error {
 	// Remove the conflicting resources from the local context (subRa)
 	// and add them to the conflict resources list in the global one (ra)
 	// Conflicting is defined as having same CurId but different value.
 	// The algorithm is basically moving the conflict resources from the
 	// "resources" section of the context into the "patchStrategicMerge" one.
-	err = subRa.HandoverConflictingResources(ra)
+	// A single file may also contain resources conflicting with the
+	// current ones.
+	err := p.subRa.HandoverConflictingResources(ra)
 	if err != nil {
 		return errors.Wrapf(
-			err, "recursed handing over conflicting resources from path '%s'", ldr.Root())
+			err, "recursed handing over conflicting resources from path '%s'", p.path)
 	}
 
-	// Verifies that each variable is targeting at most one resource
-	// in the local context.
-	// MergeVars will not only perform that operations using the variables of
-	// the current context (kustomization.Vars) but will also involve
-	// the "unresolved" variables declared but not resolved during the
-	// walk down the kustomize folder tree (in accumulateTarget).
-	err = subRa.MergeVars(subKt.kustomization.Vars)
-	if err != nil {
-		return errors.Wrapf(
-			err, "merging vars %v", subKt.kustomization.Vars)
+	if p.isDir {
+		// Verifies that each variable is targeting at most one resource
+		// in the local context.
+		// MergeVars will not only perform that operations using the variables of
+		// the current context (kustomization.Vars) but will also involve
+		// the "unresolved" variables declared but not resolved during the
+		// walk down the kustomize folder tree (in accumulateTarget).
+		err = p.subRa.MergeVars(p.vars)
+		if err != nil {
+			return errors.Wrapf(
+				err, "merging vars %v", p.vars)
+		}
 	}
 
 	// MergeAccumulator has three main tasks:
@@ -443,41 +704,11 @@ func (kt *KustTarget) accumulateDirectory(
 	// are declared using the OriginalId, MergeAccumulator needs to check
 	// no variable is now pointing two resources with the same OriginalId
 	// but different CurId.
-	err = ra.MergeAccumulator(subRa)
-	if err != nil {
-		return errors.Wrapf(
-			err, "recursed merging from path '%s'", ldr.Root())
-	}
-	return nil
-}
-
-func (kt *KustTarget) accumulateFile(
-	ra *accumulator.ResAccumulator, path string) error {
-	subRa := accumulator.MakeEmptyAccumulator()
-	resources, err := kt.rFactory.FromFile(kt.ldr, path)
-	if err != nil {
-		return errors.Wrapf(err, "accumulating resources from '%s'", path)
-	}
-	err = subRa.AppendAll(resources)
-	if err != nil {
-		return errors.Wrapf(err, "accumulating resources from '%s'", path)
-	}
-	// Also only one file has been loaded, it may contain resources
-	// which are conflicting with the current ones. As for the directory case,
-	// those resources are moved from the "resources" section into the "patches"
-	// section.
-	err = subRa.HandoverConflictingResources(ra)
-	if err != nil {
-		return errors.Wrapf(
-			err, "recursed handing over conflicting resources from path '%s'", path)
-	}
-	// Since local context only contains one file with potentially multiple
-	// resources, only the resources portion of MergeAccumulator will actually be
-	// used.
-	err = ra.MergeAccumulator(subRa)
+	// For a file, only the resources portion is actually used.
+	err = ra.MergeAccumulator(p.subRa)
 	if err != nil {
 		return errors.Wrapf(
-			err, "recursed merging from path '%s'", path)
+			err, "recursed merging from path '%s'", p.path)
 	}
 	return nil
 }
@@ -492,7 +723,9 @@ func (kt *KustTarget) configureBuiltinPlugin(
 				err, "builtin %s marshal", bpt)
 		}
 	}
-	err = p.Config(resmap.NewPluginHelpers(kt.ldr, kt.validator, kt.rFactory), y)
+	err = p.Config(resmap.NewPluginHelpers(
+		kt.ldr, kt.validator, kt.rFactory.WithCrdSchemas(kt.crdSchemas)).
+		WithPluginConfig(kt.pLdr.Config()), y)
 	if err != nil {
 		return errors.Wrapf(err, "builtin %s config: %v", bpt, y)
 	}
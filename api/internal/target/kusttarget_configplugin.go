@@ -32,13 +32,17 @@ func (kt *KustTarget) configureBuiltinGenerators() (
 	for _, bpt := range []builtinhelpers.BuiltinPluginType{
 		builtinhelpers.ConfigMapGenerator,
 		builtinhelpers.SecretGenerator,
+		builtinhelpers.HelmChartInflationGenerator,
 	} {
 		r, err := generatorConfigurators[bpt](
 			kt, bpt, builtinhelpers.GeneratorFactories[bpt])
 		if err != nil {
 			return nil, err
 		}
-		result = append(result, r...)
+		for _, g := range r {
+			result = append(
+				result, trackGenerator(g, kt.configuredBy(bpt.String())))
+		}
 	}
 	return result, nil
 }
@@ -56,13 +60,17 @@ func (kt *KustTarget) configureBuiltinTransformers(
 		builtinhelpers.PatchJson6902Transformer,
 		builtinhelpers.ReplicaCountTransformer,
 		builtinhelpers.ImageTagTransformer,
+		builtinhelpers.ReplacementTransformer,
 	} {
 		r, err := transformerConfigurators[bpt](
 			kt, bpt, builtinhelpers.TransformerFactories[bpt], tc)
 		if err != nil {
 			return nil, err
 		}
-		result = append(result, r...)
+		for _, t := range r {
+			result = append(
+				result, trackTransformer(t, kt.configuredBy(bpt.String())))
+		}
 	}
 	return result, nil
 }
@@ -114,6 +122,19 @@ var generatorConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 		}
 		return
 	},
+
+	builtinhelpers.HelmChartInflationGenerator: func(kt *KustTarget, bpt builtinhelpers.BuiltinPluginType, f gFactory) (
+		result []resmap.Generator, err error) {
+		for _, args := range kt.kustomization.HelmCharts {
+			p := f()
+			err := kt.configureBuiltinPlugin(p, args, bpt)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, p)
+		}
+		return
+	},
 }
 
 // Until Issue 1292 is implemented, use PathStrategicMerge to address
@@ -307,4 +328,22 @@ var transformerConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 		}
 		return
 	},
+	builtinhelpers.ReplacementTransformer: func(
+		kt *KustTarget, bpt builtinhelpers.BuiltinPluginType, f tFactory, _ *builtinconfig.TransformerConfig) (
+		result []resmap.Transformer, err error) {
+		if len(kt.kustomization.Replacements) == 0 {
+			return
+		}
+		var c struct {
+			Replacements []types.Replacement `json:"replacements,omitempty" yaml:"replacements,omitempty"`
+		}
+		c.Replacements = kt.kustomization.Replacements
+		p := f()
+		err = kt.configureBuiltinPlugin(p, c, bpt)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+		return
+	},
 }
@@ -0,0 +1,192 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/depgraph"
+	"sigs.k8s.io/kustomize/api/internal/git"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// Functions dedicated to walking the tree of kustomizations
+// that accumulateTarget would read, recording what each
+// includes in a depgraph.Graph, without building anything.
+// Remote bases are recorded, but not cloned.
+
+// AddToGraph adds the target's kustomization to the graph,
+// along with everything it includes, directly or not.
+func (kt *KustTarget) AddToGraph(g *depgraph.Graph) error {
+	root := kt.ldr.Root()
+	kind := depgraph.Kustomization
+	if kt.kustomization.Kind == types.ComponentKind {
+		kind = depgraph.Component
+	}
+	if !g.AddNode(root, kind) {
+		return nil
+	}
+	k := kt.kustomization
+	if err := kt.addFileToGraph(g, kt.kustFileName, "kustomization"); err != nil {
+		return err
+	}
+	for _, path := range k.Resources {
+		if err := kt.addEntryToGraph(g, path, "resources"); err != nil {
+			return err
+		}
+	}
+	for _, path := range k.Components {
+		if err := kt.addEntryToGraph(g, path, "components"); err != nil {
+			return err
+		}
+	}
+	var smp, json6902, patches, cm, secret, helm []string
+	for _, p := range k.PatchesStrategicMerge {
+		// Others are patches written inline.
+		if !strings.Contains(string(p), "\n") {
+			smp = append(smp, string(p))
+		}
+	}
+	for _, p := range k.PatchesJson6902 {
+		if p.Path != "" {
+			json6902 = append(json6902, p.Path)
+		}
+	}
+	for _, p := range k.Patches {
+		if p.Path != "" {
+			patches = append(patches, p.Path)
+		}
+	}
+	for _, args := range k.ConfigMapGenerator {
+		cm = append(cm, kvFiles(args.KvPairSources)...)
+	}
+	for _, args := range k.SecretGenerator {
+		secret = append(secret, kvFiles(args.KvPairSources)...)
+	}
+	for _, args := range k.HelmCharts {
+		if err := kt.addChartToGraph(g, args.Chart); err != nil {
+			return err
+		}
+		if args.ValuesFile != "" {
+			helm = append(helm, args.ValuesFile)
+		}
+	}
+	var openAPI []string
+	if k.OpenAPI != nil {
+		openAPI = append(openAPI, k.OpenAPI.Path)
+	}
+	for _, f := range []struct {
+		field string
+		paths []string
+	}{
+		{"crds", k.Crds},
+		{"openapi", openAPI},
+		{"patchesStrategicMerge", smp},
+		{"patchesJson6902", json6902},
+		{"patches", patches},
+		{"configMapGenerator", cm},
+		{"secretGenerator", secret},
+		{"helmCharts", helm},
+		{"configurations", k.Configurations},
+	} {
+		for _, path := range f.paths {
+			if err := kt.addFileToGraph(g, path, f.field); err != nil {
+				return err
+			}
+		}
+	}
+	// Like resources, plugin configurations may be in
+	// kustomizations of their own.
+	for _, path := range k.Generators {
+		if err := kt.addEntryToGraph(g, path, "generators"); err != nil {
+			return err
+		}
+	}
+	for _, path := range k.Transformers {
+		if err := kt.addEntryToGraph(g, path, "transformers"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addEntryToGraph adds an entry of a list of resources,
+// which is a remote base, a directory, or a file.
+func (kt *KustTarget) addEntryToGraph(
+	g *depgraph.Graph, path, field string) error {
+	if _, err := git.NewRepoSpecFromUrl(path); err == nil {
+		g.AddNode(path, depgraph.Remote)
+		g.AddEdge(kt.ldr.Root(), path, field)
+		return nil
+	}
+	ldr, err := kt.ldr.New(path)
+	if err != nil {
+		return kt.addFileToGraph(g, path, field)
+	}
+	defer ldr.Cleanup()
+	subKt := NewKustTarget(
+		ldr, kt.validator, kt.rFactory, kt.tFactory, kt.pLdr)
+	if err = subKt.Load(); err != nil {
+		return errors.Wrapf(
+			err, "couldn't make target for path '%s'", ldr.Root())
+	}
+	g.AddEdge(kt.ldr.Root(), ldr.Root(), field)
+	return subKt.AddToGraph(g)
+}
+
+// addFileToGraph adds a file, which must be readable.
+func (kt *KustTarget) addFileToGraph(
+	g *depgraph.Graph, path, field string) error {
+	if _, err := kt.ldr.Load(path); err != nil {
+		return errors.Wrapf(err, "reading '%s' of %s", path, field)
+	}
+	id := path
+	if !filepath.IsAbs(id) {
+		id = filepath.Join(kt.ldr.Root(), path)
+	}
+	g.AddNode(id, depgraph.File)
+	g.AddEdge(kt.ldr.Root(), id, field)
+	return nil
+}
+
+// addChartToGraph adds a helm chart, which is either
+// packaged, and so a file, or a directory holding a
+// Chart.yaml, all of whose files the chart may use.
+func (kt *KustTarget) addChartToGraph(g *depgraph.Graph, chart string) error {
+	if chart == "" {
+		return nil
+	}
+	if strings.HasSuffix(chart, ".tgz") ||
+		strings.HasSuffix(chart, ".tar.gz") {
+		return kt.addFileToGraph(g, chart, "helmCharts")
+	}
+	if _, err := kt.ldr.Load(filepath.Join(chart, "Chart.yaml")); err != nil {
+		return errors.Wrapf(err, "reading chart '%s' of helmCharts", chart)
+	}
+	id := chart
+	if !filepath.IsAbs(id) {
+		id = filepath.Join(kt.ldr.Root(), chart)
+	}
+	g.AddNode(id, depgraph.Directory)
+	g.AddEdge(kt.ldr.Root(), id, "helmCharts")
+	return nil
+}
+
+// kvFiles returns the files read by a generator.
+func kvFiles(s types.KvPairSources) []string {
+	var result []string
+	for _, list := range [][]string{s.FileSources, s.EncryptedFileSources} {
+		for _, source := range list {
+			// A key may be given, as key=path.
+			if i := strings.Index(source, "="); i >= 0 {
+				source = source[i+1:]
+			}
+			result = append(result, source)
+		}
+	}
+	result = append(result, s.EnvSources...)
+	return append(result, s.EncryptedEnvSources...)
+}
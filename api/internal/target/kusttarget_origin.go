@@ -0,0 +1,113 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"reflect"
+
+	"sigs.k8s.io/kustomize/api/ifc"
+	fLdr "sigs.k8s.io/kustomize/api/loader"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+// Functions dedicated to tracking the origin of
+// resources, i.e. the file or generator each came
+// from and the transformers that modified it.
+// Nothing is tracked unless the target's origin is
+// non-nil (see TrackOrigins).
+
+// subOrigin returns the origin of the kustomization
+// read by the given loader, which is the given local
+// origin unless the loader reads from a remote location.
+func (kt *KustTarget) subOrigin(
+	ldr ifc.Loader, local *resource.Origin) *resource.Origin {
+	if local == nil {
+		return nil
+	}
+	if repo, ref, path, ok := fLdr.RemoteSource(ldr); ok {
+		return &resource.Origin{Repo: repo, Ref: ref, Path: path}
+	}
+	return local
+}
+
+// configuredBy returns the origin of something configured
+// by the named builtin in the target's kustomization file.
+func (kt *KustTarget) configuredBy(by string) *resource.Origin {
+	return kt.origin.Append(kt.kustFileName).Configured(by)
+}
+
+// pluginOrigin returns the origin of something configured
+// by the given plugin configuration resource.
+func pluginOrigin(res *resource.Resource) *resource.Origin {
+	gvk := res.GetGvk()
+	apiVersion := gvk.Version
+	if gvk.Group != "" {
+		apiVersion = gvk.Group + "/" + apiVersion
+	}
+	return res.GetOrigin().Configured(
+		apiVersion + "/" + gvk.Kind + "/" + res.GetName())
+}
+
+// trackGenerator returns a generator setting the given origin
+// on each resource it generates, or the generator itself if
+// the origin is nil.
+func trackGenerator(
+	g resmap.Generator, origin *resource.Origin) resmap.Generator {
+	if origin == nil {
+		return g
+	}
+	return &originGenerator{Generator: g, origin: origin}
+}
+
+type originGenerator struct {
+	resmap.Generator
+	origin *resource.Origin
+}
+
+func (g *originGenerator) Generate() (resmap.ResMap, error) {
+	m, err := g.Generator.Generate()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range m.Resources() {
+		r.SetOrigin(g.origin.Copy())
+	}
+	return m, nil
+}
+
+// trackTransformer returns a transformer appending the given
+// origin to the transformations of each resource it modifies,
+// or the transformer itself if the origin is nil.
+func trackTransformer(
+	t resmap.Transformer, origin *resource.Origin) resmap.Transformer {
+	if origin == nil {
+		return t
+	}
+	return &originTransformer{Transformer: t, origin: origin}
+}
+
+type originTransformer struct {
+	resmap.Transformer
+	origin *resource.Origin
+}
+
+func (t *originTransformer) Transform(m resmap.ResMap) error {
+	before := make(map[*resource.Resource]ifc.Kunstructured)
+	for _, r := range m.Resources() {
+		before[r] = r.Kunstructured.Copy()
+	}
+	err := t.Transformer.Transform(m)
+	if err != nil {
+		return err
+	}
+	for _, r := range m.Resources() {
+		if old, ok := before[r]; ok &&
+			reflect.DeepEqual(old.Map(), r.Map()) {
+			continue
+		}
+		r.AppendTransformation(t.origin.Copy())
+	}
+	return nil
+}
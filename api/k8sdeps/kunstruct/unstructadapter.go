@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/kustomize/api/fieldpath"
 	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/resid"
 )
@@ -74,63 +75,77 @@ func (fs *UnstructAdapter) SetMap(m map[string]interface{}) {
 	fs.Object = m
 }
 
+// selectSubtree follows the list item selectors of the
+// path, returning the map holding the field at the path,
+// and the keys leading from that map to the field.
 func (fs *UnstructAdapter) selectSubtree(path string) (map[string]interface{}, []string, bool, error) {
-	sections, err := parseFields(path)
-	if len(sections) == 0 || (err != nil) {
+	p, err := fieldpath.Parse(path)
+	if len(p) == 0 || err != nil {
 		return nil, nil, false, err
 	}
 
-	content := fs.UnstructuredContent()
-	lastSectionIdx := len(sections)
-
-	// There are multiple sections to walk
-	for sectionIdx := 0; sectionIdx < lastSectionIdx; sectionIdx++ {
-		pathSection := sections[sectionIdx]
-		fields := pathSection.fields
-
-		if pathSection.NotIndexed() {
-			// This section has no index
-			return content, fields, true, nil
+	var current interface{} = fs.UnstructuredContent()
+	var fields []string
+	var idx int
+	for _, step := range p {
+		if !step.IsSelector() {
+			fields = append(fields, step.Key)
+			continue
 		}
-
-		// This section is terminated by an indexed field.
-		// Let's extract the slice first
-		indexedField, found, err := unstructured.NestedFieldNoCopy(content, fields...)
-		if !found || err != nil {
-			return content, fields, found, err
+		indexed := current
+		if len(fields) > 0 {
+			content, ok := current.(map[string]interface{})
+			if !ok {
+				// Only map are supported here
+				return nil, fields, false,
+					fmt.Errorf("%#v is expected to be of type map[string]interface{}", current)
+			}
+			var found bool
+			indexed, found, err = unstructured.NestedFieldNoCopy(content, fields...)
+			if !found || err != nil {
+				return content, fields, found, err
+			}
 		}
-		s, ok := indexedField.([]interface{})
+		s, ok := indexed.([]interface{})
 		if !ok {
-			return content, fields, false,
+			return nil, fields, false,
 				fmt.Errorf("%v is of the type %T, expected []interface{}",
-					indexedField, indexedField)
+					indexed, indexed)
 		}
-
-		idx, idxFound, err := pathSection.ResolveIndex(s)
-		if !idxFound || err != nil {
-			return content, fields, idxFound, err
+		idx, err = resolveIndex(step, s)
+		if idx < 0 || err != nil {
+			return nil, fields, false, err
 		}
+		current = s[idx]
+		fields = nil
+	}
 
-		if sectionIdx == lastSectionIdx-1 {
-			// This is the last section. Let's build a fake map
-			// to let the rest of the field extraction to work.
-			idxstring := fmt.Sprintf("[%v]", idx)
-			newContent := map[string]interface{}{idxstring: s[idx]}
-			newFields := []string{idxstring}
-			return newContent, newFields, true, nil
-		}
+	if len(fields) == 0 {
+		// The path ends with a selector. Let's build a fake map
+		// to let the rest of the field extraction to work.
+		idxstring := fmt.Sprintf("[%v]", idx)
+		return map[string]interface{}{idxstring: current}, []string{idxstring}, true, nil
+	}
+	content, ok := current.(map[string]interface{})
+	if !ok {
+		return nil, fields, false,
+			fmt.Errorf("%#v is expected to be of type map[string]interface{}", current)
+	}
+	return content, fields, true, nil
+}
 
-		newContent, ok := s[idx].(map[string]interface{})
-		if !ok {
-			// Only map are supported here
-			return content, fields, false,
-				fmt.Errorf("%#v is expected to be of type map[string]interface{}", s[idx])
+// resolveIndex returns the index of the first item of s
+// that step selects, or -1.
+func resolveIndex(step fieldpath.Step, s []interface{}) (int, error) {
+	if step.Kind == fieldpath.Index && step.Index >= len(s) {
+		return -1, fmt.Errorf("index %d is out of bounds", step.Index)
+	}
+	for i, item := range s {
+		if step.Selects(i, item) {
+			return i, nil
 		}
-		content = newContent
 	}
-
-	// It seems to be an invalid path
-	return nil, []string{}, false, nil
+	return -1, nil
 }
 
 // GetFieldValue returns the value at the given fieldpath.
@@ -30,4 +30,12 @@ const (
 
 	// A program name, for use in help, finding the XDG_CONFIG_DIR, etc.
 	ProgramName = "kustomize"
+
+	// Annotation holding, in YAML, the file or generator a
+	// resource came from.  Added to build output on request.
+	OriginAnnotation = "config.kubernetes.io/origin"
+
+	// Annotation holding, in YAML, the list of transformers
+	// that modified a resource.  Added to build output on request.
+	TransformationsAnnotation = "alpha.config.kubernetes.io/transformations"
 )
@@ -0,0 +1,41 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package konfig
+
+import (
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const (
+	// An environment variable to consult for the location
+	// of cached data.  See:
+	// https://specifications.freedesktop.org/basedir-spec/basedir-spec-latest.html
+	XdgCacheHomeEnv = "XDG_CACHE_HOME"
+
+	// Use this when XdgCacheHomeEnv not defined.
+	XdgCacheHomeEnvDefault = ".cache"
+
+	// Relative path below XDG_CACHE_HOME/kustomize to cache
+	// clones of remote git bases.
+	RelGitCacheHome = "git"
+)
+
+// DefaultAbsGitCacheDir returns the directory in which to
+// cache clones of remote git bases, whether it exists or not.
+func DefaultAbsGitCacheDir() string {
+	home := os.Getenv(XdgCacheHomeEnv)
+	if home == "" {
+		home = filepath.Join(HomeDir(), XdgCacheHomeEnvDefault)
+	}
+	return filepath.Join(home, ProgramName, RelGitCacheHome)
+}
+
+// DefaultGitCacheConfig returns the configuration of a
+// git cache in the default directory.
+func DefaultGitCacheConfig() *types.GitCacheConfig {
+	return &types.GitCacheConfig{AbsCacheDir: DefaultAbsGitCacheDir()}
+}
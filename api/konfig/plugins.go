@@ -4,12 +4,17 @@
 package konfig
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
 
+	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/api/filesys"
 	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -45,6 +50,53 @@ func EnabledPluginConfig() (*types.PluginConfig, error) {
 	return MakePluginConfig(types.PluginRestrictionsNone, dir), nil
 }
 
+// PolicyPluginConfig returns a configuration running the
+// non-builtin plugins in the default plugin home that the
+// policy in the given file allows.
+func PolicyPluginConfig(
+	fSys filesys.FileSystem, policyPath string) (*types.PluginConfig, error) {
+	policy, err := ReadPluginPolicy(fSys, policyPath)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := DefaultAbsPluginHome(fSys)
+	if err != nil {
+		return nil, err
+	}
+	c := MakePluginConfig(types.PluginRestrictionsPolicy, dir)
+	c.Policy = policy
+	return c, nil
+}
+
+// ReadPluginPolicy reads a plugin policy, failing on
+// unknown fields, and on incomplete or malformed entries.
+func ReadPluginPolicy(
+	fSys filesys.FileSystem, path string) (*types.PluginPolicy, error) {
+	content, err := fSys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var policy types.PluginPolicy
+	if err = yaml.UnmarshalStrict(content, &policy); err != nil {
+		return nil, errors.Wrapf(err, "reading plugin policy %s", path)
+	}
+	for _, p := range policy.Plugins {
+		if p.APIVersion == "" || p.Kind == "" {
+			return nil, fmt.Errorf(
+				"plugin policy %s: each plugin needs an apiVersion and a kind", path)
+		}
+		if b, err := hex.DecodeString(p.Sha256); err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf(
+				"plugin policy %s: %s/%s: sha256 must be %d hex digits",
+				path, p.APIVersion, p.Kind, 2*sha256.Size)
+		}
+		if _, err := p.ExecTimeout(0); err != nil {
+			return nil, errors.Wrapf(err, "plugin policy %s", path)
+		}
+	}
+	return &policy, nil
+}
+
 func DisabledPluginConfig() *types.PluginConfig {
 	return MakePluginConfig(
 		types.PluginRestrictionsBuiltinsOnly, NoPluginHomeSentinal)
@@ -6,6 +6,8 @@ package konfig
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"sigs.k8s.io/kustomize/api/filesys"
@@ -124,3 +126,79 @@ func TestDefaultAbsPluginHomeNoXdgJustHomeDir(t *testing.T) {
 		t.Fatalf("unexpected config dir: %s", s)
 	}
 }
+
+func TestReadPluginPolicy(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	sum := strings.Repeat("ab", 32)
+	testCases := map[string]struct {
+		content  string
+		expected *types.PluginPolicy
+		errMsg   string
+	}{
+		"policy": {
+			content: `
+denyRemote: true
+plugins:
+- apiVersion: someteam.example.com/v1
+  kind: SedTransformer
+  sha256: ` + sum + `
+  timeout: 1m30s`,
+			expected: &types.PluginPolicy{
+				DenyRemote: true,
+				Plugins: []types.AllowedPlugin{{
+					APIVersion: "someteam.example.com/v1",
+					Kind:       "SedTransformer",
+					Sha256:     sum,
+					Timeout:    "1m30s",
+				}},
+			},
+		},
+		"badTimeout": {
+			content: `
+plugins:
+- apiVersion: someteam.example.com/v1
+  kind: SedTransformer
+  sha256: ` + sum + `
+  timeout: 30`,
+			errMsg: `plugin someteam.example.com/v1/SedTransformer: ` +
+				`timeout "30" must be a duration, e.g. 30s`,
+		},
+		"unknownField": {
+			content: "allowRemote: true",
+			errMsg:  `unknown field "allowRemote"`,
+		},
+		"noKind": {
+			content: `
+plugins:
+- apiVersion: someteam.example.com/v1
+  sha256: ` + sum,
+			errMsg: "each plugin needs an apiVersion and a kind",
+		},
+		"shortSum": {
+			content: `
+plugins:
+- apiVersion: someteam.example.com/v1
+  kind: SedTransformer
+  sha256: abab`,
+			errMsg: "someteam.example.com/v1/SedTransformer: sha256 must be 64 hex digits",
+		},
+	}
+	for n, tc := range testCases {
+		if err := fSys.WriteFile("/policy.yaml", []byte(tc.content)); err != nil {
+			t.Fatal(err)
+		}
+		policy, err := ReadPluginPolicy(fSys, "/policy.yaml")
+		if tc.errMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Fatalf("%s: expected error %q, got %v", n, tc.errMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", n, err)
+		}
+		if !reflect.DeepEqual(policy, tc.expected) {
+			t.Fatalf("%s: expected %v, got %v", n, tc.expected, policy)
+		}
+	}
+}
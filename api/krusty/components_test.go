@@ -0,0 +1,178 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeComponentBase(th kusttest_test.Harness) {
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+`)
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: storefront
+spec:
+  template:
+    spec:
+      containers:
+      - name: storefront
+        image: storefront:v1
+`)
+	th.WriteC("/app/components/monitoring", `
+commonLabels:
+  monitored: "true"
+configMapGenerator:
+- name: exporter-config
+  literals:
+  - port=9100
+patchesStrategicMerge:
+- exporter.yaml
+`)
+	th.WriteF("/app/components/monitoring/exporter.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: storefront
+spec:
+  template:
+    spec:
+      containers:
+      - name: exporter
+        image: exporter:v2
+`)
+	th.WriteC("/app/components/tls", `
+patchesJson6902:
+- target:
+    group: apps
+    version: v1
+    kind: Deployment
+    name: storefront
+  path: tls.yaml
+`)
+	th.WriteF("/app/components/tls/tls.yaml", `
+- op: add
+  path: /spec/template/spec/containers/0/args
+  value: [--tls]
+`)
+}
+
+// The same components are mixed into two overlays,
+// whose own transformers then apply to what the
+// components did.
+func TestComponents(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeComponentBase(th)
+	th.WriteK("/app/dev", `
+resources:
+- ../base
+components:
+- ../components/monitoring
+`)
+	th.WriteK("/app/prod", `
+namePrefix: prod-
+resources:
+- ../base
+components:
+- ../components/monitoring
+- ../components/tls
+`)
+
+	m := th.Run("/app/dev", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    monitored: "true"
+  name: storefront
+spec:
+  selector:
+    matchLabels:
+      monitored: "true"
+  template:
+    metadata:
+      labels:
+        monitored: "true"
+    spec:
+      containers:
+      - image: exporter:v2
+        name: exporter
+      - image: storefront:v1
+        name: storefront
+---
+apiVersion: v1
+data:
+  port: "9100"
+kind: ConfigMap
+metadata:
+  labels:
+    monitored: "true"
+  name: exporter-config-c2fctgmbmk
+`)
+
+	m = th.Run("/app/prod", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    monitored: "true"
+  name: prod-storefront
+spec:
+  selector:
+    matchLabels:
+      monitored: "true"
+  template:
+    metadata:
+      labels:
+        monitored: "true"
+    spec:
+      containers:
+      - args:
+        - --tls
+        image: exporter:v2
+        name: exporter
+      - image: storefront:v1
+        name: storefront
+---
+apiVersion: v1
+data:
+  port: "9100"
+kind: ConfigMap
+metadata:
+  labels:
+    monitored: "true"
+  name: prod-exporter-config-bb676m6452
+`)
+}
+
+func TestComponentsMisplaced(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeComponentBase(th)
+	th.WriteK("/app/a", `
+resources:
+- ../components/monitoring
+`)
+	th.WriteK("/app/b", `
+components:
+- ../base
+`)
+	for dir, expected := range map[string]string{
+		"/app/a": "is a Component; list it in components, not resources",
+		"/app/b": "is a Kustomization; list it in resources, not components",
+	} {
+		err := th.RunWithErr(dir, th.MakeDefaultOptions())
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("%s: unexpected error: %v", dir, err)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+// writeManyBases writes an overlay of n bases, each
+// of which has a base of its own, and a local file.
+func writeManyBases(th kusttest_test.Harness, n int) {
+	var b strings.Builder
+	b.WriteString("resources:\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "- ../base%d\n", i)
+		th.WriteK(fmt.Sprintf("/app/base%d", i), fmt.Sprintf(`
+namePrefix: b%d-
+resources:
+- ../common
+- cm.yaml
+`, i))
+		th.WriteF(fmt.Sprintf("/app/base%d/cm.yaml", i), fmt.Sprintf(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  index: "%d"
+`, i))
+	}
+	b.WriteString("- service.yaml\n")
+	th.WriteK("/app/overlay", b.String())
+	th.WriteF("/app/overlay/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`)
+	th.WriteK("/app/common", `
+resources:
+- deployment.yaml
+`)
+	th.WriteF("/app/common/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+`)
+}
+
+func TestConcurrentAccumulation(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeManyBases(th, 3)
+	opts := th.MakeDefaultOptions()
+	opts.RerorderTransformer = "none"
+	opts.Concurrency = 4
+	m := th.Run("/app/overlay", opts)
+	// Depth-first input order, as in a serial build.
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: b0-dep
+---
+apiVersion: v1
+data:
+  index: "0"
+kind: ConfigMap
+metadata:
+  name: b0-cm
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: b1-dep
+---
+apiVersion: v1
+data:
+  index: "1"
+kind: ConfigMap
+metadata:
+  name: b1-cm
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: b2-dep
+---
+apiVersion: v1
+data:
+  index: "2"
+kind: ConfigMap
+metadata:
+  name: b2-cm
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`)
+}
+
+func TestConcurrentAccumulationMatchesSerial(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeManyBases(th, 20)
+	opts := th.MakeDefaultOptions()
+	serial, err := th.Run("/app/overlay", opts).AsYaml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{2, 5, 50} {
+		opts.Concurrency = n
+		actual, err := th.Run("/app/overlay", opts).AsYaml()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != string(serial) {
+			t.Fatalf("concurrency %d: expected\n%s\nbut got\n%s",
+				n, serial, actual)
+		}
+	}
+}
+
+func TestConcurrentAccumulationReportsFirstError(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeManyBases(th, 5)
+	th.WriteF("/app/base1/cm.yaml", "not: [valid")
+	th.WriteF("/app/base3/cm.yaml", "also: [not valid")
+	opts := th.MakeDefaultOptions()
+	opts.Concurrency = 8
+	err := th.RunWithErr("/app/overlay", opts)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(), "/app/base1") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -360,3 +360,184 @@ spec:
             description: Containers allows injecting additional containers
 `)
 }
+
+func TestCrdStrategicMergeListByKey(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+crds:
+- crd.yaml
+resources:
+- app.yaml
+patchesStrategicMerge:
+- patch.yaml
+`)
+	th.WriteF("/app/crd.yaml", `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: apps.example.com
+spec:
+  group: example.com
+  names:
+    kind: App
+    plural: apps
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              components:
+                type: array
+                x-kubernetes-list-type: map
+                x-kubernetes-list-map-keys:
+                - name
+                items:
+                  type: object
+                  properties:
+                    name:
+                      type: string
+                    image:
+                      type: string
+                    ports:
+                      type: array
+                      x-kubernetes-list-type: set
+                      items:
+                        type: integer
+              hosts:
+                type: array
+                items:
+                  type: string
+`)
+	th.WriteF("/app/app.yaml", `
+apiVersion: example.com/v1
+kind: App
+metadata:
+  name: app
+spec:
+  components:
+  - name: web
+    image: web:v1
+    ports:
+    - 80
+  - name: db
+    image: db:v1
+  hosts:
+  - a.example.com
+`)
+	th.WriteF("/app/patch.yaml", `
+apiVersion: example.com/v1
+kind: App
+metadata:
+  name: app
+spec:
+  components:
+  - name: web
+    image: web:v2
+    ports:
+    - 443
+  - name: cache
+    image: cache:v1
+  hosts:
+  - b.example.com
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: example.com/v1
+kind: App
+metadata:
+  name: app
+spec:
+  components:
+  - image: web:v2
+    name: web
+    ports:
+    - 443
+    - 80
+  - image: cache:v1
+    name: cache
+  - image: db:v1
+    name: db
+  hosts:
+  - b.example.com
+`)
+}
+
+func TestOpenAPIStrategicMergeListByKey(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+openapi:
+  path: openapi.json
+resources:
+- app.yaml
+patches:
+- path: patch.yaml
+`)
+	th.WriteF("/app/openapi.json", `{
+  "definitions": {
+    "com.example.v1.App": {
+      "properties": {
+        "apiVersion": {"type": "string"},
+        "kind": {"type": "string"},
+        "metadata": {"type": "object"},
+        "spec": {"$ref": "#/definitions/com.example.v1.AppSpec"}
+      },
+      "x-kubernetes-group-version-kind": [
+        {"group": "example.com", "kind": "App", "version": "v1"}
+      ]
+    },
+    "com.example.v1.AppSpec": {
+      "properties": {
+        "components": {
+          "type": "array",
+          "items": {"type": "object"},
+          "x-kubernetes-patch-merge-key": "name",
+          "x-kubernetes-patch-strategy": "merge"
+        }
+      }
+    }
+  }
+}
+`)
+	th.WriteF("/app/app.yaml", `
+apiVersion: example.com/v1
+kind: App
+metadata:
+  name: app
+spec:
+  components:
+  - name: web
+    image: web:v1
+  - name: db
+    image: db:v1
+`)
+	th.WriteF("/app/patch.yaml", `
+apiVersion: example.com/v1
+kind: App
+metadata:
+  name: app
+spec:
+  components:
+  - name: db
+    image: db:v2
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: example.com/v1
+kind: App
+metadata:
+  name: app
+spec:
+  components:
+  - image: web:v1
+    name: web
+  - image: db:v2
+    name: db
+`)
+}
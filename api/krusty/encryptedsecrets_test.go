@@ -0,0 +1,67 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	sopstest_test "sigs.k8s.io/kustomize/api/testutils/sopstest"
+)
+
+func TestSecretGeneratorEncryptedSources(t *testing.T) {
+	keys := sopstest_test.MakeKeys(t)
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+secretGenerator:
+- name: db
+  encryptedEnvs:
+  - db.env
+  encryptedFiles:
+  - tls.crt=tls.yaml
+`)
+	th.WriteF("/app/db.env", string(keys.Encrypt("db.env", `
+DB_USER=admin
+DB_PASSWORD=hunter2
+`)))
+	th.WriteF("/app/tls.yaml", string(keys.Encrypt("tls.yaml", `
+cert: abc
+`)))
+
+	defer keys.Install(false)()
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  DB_PASSWORD: aHVudGVyMg==
+  DB_USER: YWRtaW4=
+  tls.crt: Y2VydDogYWJjCg==
+kind: Secret
+metadata:
+  name: db-78d2f74b7c
+type: Opaque
+`)
+}
+
+func TestSecretGeneratorEncryptedSourcesWithoutKeys(t *testing.T) {
+	keys := sopstest_test.MakeKeys(t)
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+secretGenerator:
+- name: db
+  encryptedEnvs:
+  - db.env
+`)
+	th.WriteF("/app/db.env", string(keys.Encrypt("db.env", `
+DB_PASSWORD=hunter2
+`)))
+
+	defer sopstest_test.MakeKeys(t).Install(false)()
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if !strings.Contains(err.Error(),
+		"decrypting 'db.env': Error getting data key") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+// Field paths select list items in the fieldSpecs of
+// configurations, and in the fieldPath of vars.
+func TestFieldPathSelectors(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- resources.yaml
+configurations:
+- config.yaml
+vars:
+- name: DB_HOST
+  objref:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: db
+  fieldref:
+    fieldpath: spec.template.spec.containers[name=db].env[name=HOST].value
+- name: OWNER
+  objref:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: db
+  fieldref:
+    fieldpath: metadata.annotations.example\.com/owner
+`)
+	th.WriteF("/app/config.yaml", `
+varReference:
+- kind: App
+  path: spec/template/spec/containers[name=app]/env[name=DB]/value
+- kind: App
+  path: metadata/annotations/example.com\/contact
+`)
+	th.WriteF("/app/resources.yaml", `
+apiVersion: example.com/v1
+kind: App
+metadata:
+  name: app
+  annotations:
+    example.com/contact: $(OWNER)
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        env:
+        - name: DB
+          value: $(DB_HOST)
+        - name: LITERAL
+          value: $(DB_HOST)
+      - name: sidecar
+        env:
+        - name: DB
+          value: $(DB_HOST)
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: db
+  annotations:
+    example.com/owner: team-a
+spec:
+  template:
+    spec:
+      containers:
+      - name: db
+        env:
+        - name: HOST
+          value: db.example.com
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: example.com/v1
+kind: App
+metadata:
+  annotations:
+    example.com/contact: team-a
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - env:
+        - name: DB
+          value: db.example.com
+        - name: LITERAL
+          value: $(DB_HOST)
+        name: app
+      - env:
+        - name: DB
+          value: $(DB_HOST)
+        name: sidecar
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    example.com/owner: team-a
+  name: db
+spec:
+  template:
+    spec:
+      containers:
+      - env:
+        - name: HOST
+          value: db.example.com
+        name: db
+`)
+}
@@ -0,0 +1,131 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// makeBareRepo makes, below dir, a bare git repo holding
+// a kustomization of one ConfigMap, tagged v1.
+func makeBareRepo(t *testing.T, dir string) string {
+	work := filepath.Join(dir, "work")
+	bare := filepath.Join(dir, "repos", "someOrg", "base.git")
+	write := func(name, content string) {
+		err := ioutil.WriteFile(
+			filepath.Join(work, name), []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{
+			"-c", "user.name=test", "-c", "user.email=test@example.com",
+		}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q", work)
+	write("kustomization.yaml", "resources:\n- cm.yaml\n")
+	write("cm.yaml", `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  fruit: apple
+`)
+	run("-C", work, "add", ".")
+	run("-C", work, "commit", "-q", "-m", "initial")
+	run("-C", work, "tag", "v1")
+	run("clone", "-q", "--bare", work, bare)
+	return bare
+}
+
+func TestGitCache(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("no git program on path")
+	}
+	dir, err := ioutil.TempDir("", "kustomize-gitcache-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	bare := makeBareRepo(t, dir)
+	overlay := filepath.Join(dir, "overlay")
+	err = os.Mkdir(overlay, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeOverlay := func(ref string) {
+		err := ioutil.WriteFile(
+			filepath.Join(overlay, "kustomization.yaml"), []byte(`
+namePrefix: pre-
+resources:
+- file://`+bare+`?ref=`+ref+`
+`), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	fSys := filesys.MakeFsOnDisk()
+	opts := krusty.MakeDefaultOptions()
+	opts.GitCache = &types.GitCacheConfig{
+		AbsCacheDir: filepath.Join(dir, "cache")}
+	build := func() (string, error) {
+		m, err := krusty.MakeKustomizer(fSys, opts).Run(overlay)
+		if err != nil {
+			return "", err
+		}
+		y, err := m.AsYaml()
+		return string(y), err
+	}
+	const expected = `apiVersion: v1
+data:
+  fruit: apple
+kind: ConfigMap
+metadata:
+  name: pre-cm
+`
+
+	writeOverlay("v1")
+	actual, err := build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, actual)
+	}
+
+	// The repo is gone, but its clone is cached.
+	err = os.RemoveAll(bare)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.GitCache.Offline = true
+	actual, err = build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, actual)
+	}
+
+	// Another ref isn't cached, and can't be fetched.
+	writeOverlay("master")
+	_, err = build()
+	if err == nil || !strings.Contains(err.Error(), "ref=master") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/go-git/go-git/v5/plumbing/transport/file"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func TestGoGitCloner(t *testing.T) {
+	// Needed to make the repo.
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("no git program on path")
+	}
+	dir, err := ioutil.TempDir("", "kustomize-gitcloner-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	bare := makeBareRepo(t, dir)
+	overlay := filepath.Join(dir, "overlay")
+	err = os.Mkdir(overlay, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Given git::, go-getter would run git to fetch the base.
+	err = ioutil.WriteFile(
+		filepath.Join(overlay, "kustomization.yaml"), []byte(`
+namePrefix: pre-
+resources:
+- git::file://`+bare+`?ref=v1
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Once the repo's made, the git program is replaced by
+	// one that records that it ran, but for git-upload-pack,
+	// which go-git runs to clone from file URLs.
+	uploadPack, err := exec.LookPath(transport.UploadPackServiceName)
+	if err != nil {
+		t.Skip("no git-upload-pack program on path")
+	}
+	client.InstallProtocol("file",
+		file.NewClient(uploadPack, transport.ReceivePackServiceName))
+	defer client.InstallProtocol("file", file.DefaultClient)
+	bin := filepath.Join(dir, "bin")
+	ran := filepath.Join(dir, "git-ran")
+	err = os.Mkdir(bin, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//nolint:gosec
+	err = ioutil.WriteFile(filepath.Join(bin, "git"),
+		[]byte("#!/bin/sh\n: > "+ran+"\nexit 1\n"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", bin)
+	defer os.Setenv("PATH", path)
+	opts := krusty.MakeDefaultOptions()
+	opts.GitCloner = types.GitClonerGo
+	m, err := krusty.MakeKustomizer(filesys.MakeFsOnDisk(), opts).Run(overlay)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actual, err := m.AsYaml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = `apiVersion: v1
+data:
+  fruit: apple
+kind: ConfigMap
+metadata:
+  name: pre-cm
+`
+	if string(actual) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, actual)
+	}
+	if _, err = os.Stat(ran); err == nil {
+		t.Fatalf("the git program was run")
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/depgraph"
+	"sigs.k8s.io/kustomize/api/krusty"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeGraphApp(th kusttest_test.Harness) {
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+- github.com/example/shared//monitoring?ref=v1
+configMapGenerator:
+- name: config
+  files:
+  - app.properties
+  - extra=extra.properties
+`)
+	th.WriteF("/app/base/deployment.yaml", "")
+	th.WriteF("/app/base/app.properties", "a=1\n")
+	th.WriteF("/app/base/extra.properties", "b=2\n")
+	th.WriteC("/app/tls", `
+patchesStrategicMerge:
+- tls.yaml
+`)
+	th.WriteF("/app/tls/tls.yaml", "")
+	th.WriteK("/app/prod", `
+resources:
+- ../base
+components:
+- ../tls
+patchesStrategicMerge:
+- replicas.yaml
+- |-
+  apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: app
+secretGenerator:
+- name: creds
+  envs:
+  - creds.env
+`)
+	th.WriteF("/app/prod/replicas.yaml", "")
+	th.WriteF("/app/prod/creds.env", "USER=admin\n")
+	th.WriteK("/app/staging", `
+resources:
+- ../base
+`)
+}
+
+func TestGraph(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeGraphApp(th)
+	opts := th.MakeDefaultOptions()
+	k := krusty.MakeKustomizer(th.GetFSys(), &opts)
+	g := depgraph.New()
+	for _, path := range []string{"/app/prod", "/app/staging"} {
+		if err := k.AddToGraph(g, path); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	var dot bytes.Buffer
+	if err := g.WriteDot(&dot); err != nil {
+		t.Fatal(err)
+	}
+	expected := `digraph kustomize {
+  "/app/prod" [shape=box, style=bold];
+  "/app/prod/kustomization.yaml" [shape=note];
+  "/app/base" [shape=box];
+  "/app/base/kustomization.yaml" [shape=note];
+  "/app/base/deployment.yaml" [shape=note];
+  "github.com/example/shared//monitoring?ref=v1" [shape=box3d];
+  "/app/base/app.properties" [shape=note];
+  "/app/base/extra.properties" [shape=note];
+  "/app/tls" [shape=component];
+  "/app/tls/kustomization.yaml" [shape=note];
+  "/app/tls/tls.yaml" [shape=note];
+  "/app/prod/replicas.yaml" [shape=note];
+  "/app/prod/creds.env" [shape=note];
+  "/app/staging" [shape=box, style=bold];
+  "/app/staging/kustomization.yaml" [shape=note];
+  "/app/prod" -> "/app/prod/kustomization.yaml" [label="kustomization"];
+  "/app/prod" -> "/app/base" [label="resources"];
+  "/app/base" -> "/app/base/kustomization.yaml" [label="kustomization"];
+  "/app/base" -> "/app/base/deployment.yaml" [label="resources"];
+  "/app/base" -> "github.com/example/shared//monitoring?ref=v1" [label="resources"];
+  "/app/base" -> "/app/base/app.properties" [label="configMapGenerator"];
+  "/app/base" -> "/app/base/extra.properties" [label="configMapGenerator"];
+  "/app/prod" -> "/app/tls" [label="components"];
+  "/app/tls" -> "/app/tls/kustomization.yaml" [label="kustomization"];
+  "/app/tls" -> "/app/tls/tls.yaml" [label="patchesStrategicMerge"];
+  "/app/prod" -> "/app/prod/replicas.yaml" [label="patchesStrategicMerge"];
+  "/app/prod" -> "/app/prod/creds.env" [label="secretGenerator"];
+  "/app/staging" -> "/app/staging/kustomization.yaml" [label="kustomization"];
+  "/app/staging" -> "/app/base" [label="resources"];
+}
+`
+	if dot.String() != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, dot.String())
+	}
+
+	for file, roots := range map[string][]string{
+		"/app/base/extra.properties":  {"/app/prod", "/app/staging"},
+		"/app/tls/kustomization.yaml": {"/app/prod"},
+		"/app/staging":                {"/app/staging"},
+		"/app/base/unused.yaml":       nil,
+	} {
+		if actual := g.RootsIncluding(file); !reflect.DeepEqual(actual, roots) {
+			t.Errorf("%s: expected roots %v, got %v", file, roots, actual)
+		}
+	}
+}
+
+func TestGraphMissingFile(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+patchesJson6902:
+- path: missing.yaml
+  target:
+    kind: Deployment
+    name: app
+    version: v1
+`)
+	opts := th.MakeDefaultOptions()
+	err := krusty.MakeKustomizer(th.GetFSys(), &opts).
+		AddToGraph(depgraph.New(), "/app")
+	if err == nil || !strings.Contains(err.Error(),
+		"reading 'missing.yaml' of patchesJson6902") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGraphHelmCharts(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+helmCharts:
+- releaseName: web
+  chart: charts/web
+  valuesFile: values.yaml
+- releaseName: db
+  chart: charts/db-1.0.0.tgz
+`)
+	th.WriteF("/app/charts/web/Chart.yaml", "name: web\n")
+	th.WriteF("/app/charts/web/templates/deployment.yaml", "")
+	th.WriteF("/app/charts/db-1.0.0.tgz", "")
+	th.WriteF("/app/values.yaml", "")
+	opts := th.MakeDefaultOptions()
+	g := depgraph.New()
+	if err := krusty.MakeKustomizer(th.GetFSys(), &opts).
+		AddToGraph(g, "/app"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []depgraph.Node{
+		{ID: "/app", Kind: depgraph.Kustomization},
+		{ID: "/app/kustomization.yaml", Kind: depgraph.File},
+		{ID: "/app/charts/web", Kind: depgraph.Directory},
+		{ID: "/app/charts/db-1.0.0.tgz", Kind: depgraph.File},
+		{ID: "/app/values.yaml", Kind: depgraph.File},
+	}
+	if !reflect.DeepEqual(g.Nodes, expected) {
+		t.Fatalf("expected nodes %v, got %v", expected, g.Nodes)
+	}
+	for file, roots := range map[string][]string{
+		"/app/charts/web/templates/deployment.yaml": {"/app"},
+		"/app/charts/db-1.0.0.tgz":                  {"/app"},
+		"/app/charts/website/Chart.yaml":            nil,
+	} {
+		if actual := g.RootsIncluding(file); !reflect.DeepEqual(actual, roots) {
+			t.Errorf("%s: expected roots %v, got %v", file, roots, actual)
+		}
+	}
+}
+
+func TestGraphChartWithoutChartFile(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+helmCharts:
+- releaseName: web
+  chart: charts/web
+`)
+	opts := th.MakeDefaultOptions()
+	err := krusty.MakeKustomizer(th.GetFSys(), &opts).
+		AddToGraph(depgraph.New(), "/app")
+	if err == nil || !strings.Contains(err.Error(),
+		"reading chart 'charts/web' of helmCharts") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+// writeFakeHelm writes a program that stands in for
+// "helm template <release> <chart> --namespace <ns>",
+// rendering a Service named after the release.
+func writeFakeHelm(t *testing.T, dir string) string {
+	helm := filepath.Join(dir, "helm")
+	err := ioutil.WriteFile(helm, []byte(`#!/bin/sh
+cat <<END
+apiVersion: v1
+kind: Service
+metadata:
+  name: $2-$(basename $3)
+  namespace: $5
+spec:
+  ports:
+  - port: 25565
+END
+`), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return helm
+}
+
+// Charts are inflated by a builtin plugin, so they don't
+// need plugins to be enabled, and their resources are
+// customized like any other.
+func TestHelmChartInflationGenerator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-helm-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	helm := writeFakeHelm(t, dir)
+
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+namePrefix: p-
+commonLabels:
+  app: minecraft
+helmCharts:
+- chart: charts/minecraft
+  releaseName: moria
+  releaseNamespace: games
+generators:
+- gen.yaml
+`)
+	th.WriteF("/app/gen.yaml", `
+apiVersion: builtin
+kind: HelmChartInflationGenerator
+metadata:
+  name: other
+  namespace: games
+chart: charts/other.tgz
+`)
+	th.WriteF("/app/charts/minecraft/Chart.yaml", "name: minecraft")
+	th.WriteF("/app/charts/other.tgz", "")
+	o := th.MakeDefaultOptions()
+	o.PluginConfig.HelmCommand = helm
+	m := th.Run("/app", o)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    app: minecraft
+  name: p-moria-minecraft
+  namespace: games
+spec:
+  ports:
+  - port: 25565
+  selector:
+    app: minecraft
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    app: minecraft
+  name: p-release-name-other.tgz
+  namespace: games
+spec:
+  ports:
+  - port: 25565
+  selector:
+    app: minecraft
+`)
+}
+
+// The helm program is chosen by whoever runs the build,
+// not by kustomizations, which may be remote.
+func TestHelmChartInflationGeneratorNoHelmBin(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+helmCharts:
+- chart: charts/minecraft
+  helmBin: /tmp/evil
+`)
+	th.WriteF("/app/charts/minecraft/Chart.yaml", "name: minecraft")
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if err == nil || !strings.Contains(err.Error(), "helmBin") {
+		t.Fatalf("expected helmBin to be rejected, got %v", err)
+	}
+}
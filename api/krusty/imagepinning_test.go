@@ -0,0 +1,133 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/imagedigest"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	registrytest_test "sigs.k8s.io/kustomize/api/testutils/registrytest"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func writeImagePinningApp(th kusttest_test.Harness, host string) {
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+images:
+- name: app
+  newName: `+host+`/team/app
+  newTag: v1
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox@sha256:0123
+      containers:
+      - name: app
+        image: app
+`)
+}
+
+func TestImagePinningResolve(t *testing.T) {
+	reg := registrytest_test.NewRegistry()
+	defer reg.Close()
+	digest := reg.Push("team/app", "v1")
+	th := kusttest_test.MakeHarness(t)
+	writeImagePinningApp(th, reg.Host)
+	opts := th.MakeDefaultOptions()
+	opts.ImagePinning = types.ImagePinningResolve
+	expected := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - image: ` + reg.Host + `/team/app@` + digest + `
+        name: app
+      initContainers:
+      - image: busybox@sha256:0123
+        name: init
+`
+	m := th.Run("/app", opts)
+	th.AssertActualEqualsExpected(m, expected)
+	lock, err := th.GetFSys().ReadFile("/app/" + imagedigest.LockFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(lock) != `images:
+- digest: `+digest+`
+  image: `+reg.Host+`/team/app:v1
+` {
+		t.Fatalf("unexpected lock file:\n%s", lock)
+	}
+
+	// With the lock file, builds need no registry.
+	reg.Close()
+	m = th.Run("/app", opts)
+	th.AssertActualEqualsExpected(m, expected)
+}
+
+func TestImagePinningResolveFailure(t *testing.T) {
+	reg := registrytest_test.NewRegistry()
+	defer reg.Close()
+	th := kusttest_test.MakeHarness(t)
+	writeImagePinningApp(th, reg.Host)
+	opts := th.MakeDefaultOptions()
+	opts.ImagePinning = types.ImagePinningResolve
+	err := th.RunWithErr("/app", opts)
+	if err == nil || !strings.Contains(err.Error(),
+		"resolving digest of image "+reg.Host+"/team/app:v1") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestImagePinningCheck(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeImagePinningApp(th, "registry.example.com")
+	opts := th.MakeDefaultOptions()
+	opts.ImagePinning = types.ImagePinningCheck
+	err := th.RunWithErr("/app", opts)
+	if err == nil || err.Error() != `images not pinned by digest:
+  apps_v1_Deployment|~X|app: spec.template.spec.containers[0].image: registry.example.com/team/app:v1` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+images:
+- name: app
+  newName: registry.example.com/team/app
+  digest: sha256:4567
+`)
+	m := th.Run("/app", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - image: registry.example.com/team/app@sha256:4567
+        name: app
+      initContainers:
+      - image: busybox@sha256:0123
+        name: init
+`)
+}
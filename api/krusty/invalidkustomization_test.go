@@ -0,0 +1,60 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestInvalidKustomizationReportsEveryProblem(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/kustomization.yaml", `
+resources:
+- cm.yaml
+patchesStrategicMerg:
+- patch.yaml
+configMapGenerator:
+- name: cm
+  literals: a=b
+`)
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	for _, expected := range []string{
+		`/app/kustomization.yaml:4:1: error: unknown field "patchesStrategicMerg"; ` +
+			`did you mean "patchesStrategicMerge"?`,
+		`/app/kustomization.yaml:8:13: error: ` +
+			`configMapGenerator[0].literals must be a list, not "a=b"`,
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected %q in error: %v", expected, err)
+		}
+	}
+}
+
+// Kustomization files are YAML 1.1, in which e.g. yes is true.
+func TestKustomizationYaml11Booleans(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+configMapGenerator:
+- name: cm
+  literals:
+  - a=b
+generatorOptions:
+  disableNameSuffixHash: yes
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  a: b
+kind: ConfigMap
+metadata:
+  name: cm
+`)
+}
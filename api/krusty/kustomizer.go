@@ -4,16 +4,26 @@
 package krusty
 
 import (
+	"errors"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"sigs.k8s.io/kustomize/api/depgraph"
 	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/api/imagedigest"
 	"sigs.k8s.io/kustomize/api/internal/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/api/internal/openapi"
 	pLdr "sigs.k8s.io/kustomize/api/internal/plugins/loader"
 	"sigs.k8s.io/kustomize/api/internal/target"
 	"sigs.k8s.io/kustomize/api/k8sdeps/kunstruct"
 	"sigs.k8s.io/kustomize/api/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/api/konfig"
 	fLdr "sigs.k8s.io/kustomize/api/loader"
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/resource"
 	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
 )
 
 // Kustomizer performs kustomizations.  It's meant to behave
@@ -44,27 +54,15 @@ func MakeKustomizer(fSys filesys.FileSystem, o *Options) *Kustomizer {
 // on any number of internal paths (e.g. the filesystem may contain
 // multiple overlays, and Run can be called on each of them).
 func (b *Kustomizer) Run(path string) (resmap.ResMap, error) {
-	pf := transformer.NewFactoryImpl()
-	rf := resmap.NewFactory(
-		resource.NewFactory(
-			kunstruct.NewKunstructuredFactoryImpl()),
-		pf)
-	lr := fLdr.RestrictionNone
-	if b.options.LoadRestrictions == types.LoadRestrictionsRootOnly {
-		lr = fLdr.RestrictionRootOnly
-	}
-	ldr, err := fLdr.NewLoader(lr, path, b.fSys)
+	kt, ldr, err := b.makeTarget(path)
 	if err != nil {
 		return nil, err
 	}
 	defer ldr.Cleanup()
-	kt := target.NewKustTarget(
-		ldr,
-		validator.NewKustValidator(),
-		rf,
-		pf,
-		pLdr.NewLoader(b.options.PluginConfig, rf),
-	)
+	if b.options.AddOriginAnnotations {
+		kt.TrackOrigins()
+	}
+	kt.SetConcurrency(b.options.Concurrency)
 	err = kt.Load()
 	if err != nil {
 		return nil, err
@@ -84,5 +82,182 @@ func (b *Kustomizer) Run(path string) (resmap.ResMap, error) {
 			t.Transform(m)
 		}
 	}
+	err = selectResources(m, b.options.Select, b.options.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	switch b.options.ImagePinning {
+	case types.ImagePinningResolve:
+		err = b.pinImages(m, path)
+	case types.ImagePinningCheck:
+		err = imagedigest.Check(m)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if b.options.ValidateOutput {
+		err = validateOutput(m, kt.CrdSchemas())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if b.options.AddOriginAnnotations {
+		err = addOriginAnnotations(m)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return m, nil
 }
+
+// AddToGraph adds to g, as a root, the kustomization at the
+// given path, and all that it includes, directly or not: bases,
+// components, and the files of resources, patches, generator
+// inputs and the like.  Nothing is built, and remote bases are
+// added as nodes, but not cloned.
+func (b *Kustomizer) AddToGraph(g *depgraph.Graph, path string) error {
+	kt, ldr, err := b.makeTarget(path)
+	if err != nil {
+		return err
+	}
+	defer ldr.Cleanup()
+	if err = kt.Load(); err != nil {
+		return err
+	}
+	g.AddRoot(ldr.Root())
+	return kt.AddToGraph(g)
+}
+
+// makeTarget returns the target for the kustomization at
+// the given path, and its loader, to be cleaned up.
+func (b *Kustomizer) makeTarget(path string) (*target.KustTarget, ifc.Loader, error) {
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(
+		resource.NewFactory(
+			kunstruct.NewKunstructuredFactoryImpl()),
+		pf)
+	lr := fLdr.RestrictionNone
+	if b.options.LoadRestrictions == types.LoadRestrictionsRootOnly {
+		lr = fLdr.RestrictionRootOnly
+	}
+	ldr, err := fLdr.NewLoaderWithGit(
+		lr, path, b.fSys, b.options.GitCloner, b.options.GitCache)
+	if err != nil {
+		return nil, nil, err
+	}
+	kt := target.NewKustTarget(
+		ldr,
+		validator.NewKustValidator(),
+		rf,
+		pf,
+		pLdr.NewLoader(b.options.PluginConfig, rf).
+			WithRegistry(b.options.PluginRegistry),
+	)
+	return kt, ldr, nil
+}
+
+// selectResources removes from m the resources matching none
+// of the selectors, if there are any, and those matching any
+// of the exclusions.
+func selectResources(
+	m resmap.ResMap, selectors, exclusions []types.Selector) error {
+	if len(selectors) > 0 {
+		selected := make(map[*resource.Resource]bool)
+		for _, s := range selectors {
+			resources, err := m.Select(s)
+			if err != nil {
+				return err
+			}
+			for _, r := range resources {
+				selected[r] = true
+			}
+		}
+		for _, r := range m.Resources() {
+			if !selected[r] {
+				if err := m.Remove(r.CurId()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, s := range exclusions {
+		resources, err := m.Select(s)
+		if err != nil {
+			return err
+		}
+		for _, r := range resources {
+			if err = m.Remove(r.CurId()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pinImages pins the container images in m by digest,
+// per the lock file in the kustomization root, which
+// records any digests that had to be resolved.
+// Remote roots have no lock file.
+func (b *Kustomizer) pinImages(m resmap.ResMap, path string) error {
+	lock := &imagedigest.LockFile{}
+	isDir := b.fSys.IsDir(path)
+	if isDir {
+		var err error
+		lock, err = imagedigest.ReadLockFile(b.fSys, path)
+		if err != nil {
+			return err
+		}
+	}
+	r := imagedigest.NewResolver()
+	err := imagedigest.Pin(m, func(image string) (string, error) {
+		return lock.Resolve(r, image)
+	})
+	if err != nil {
+		return err
+	}
+	if isDir {
+		return lock.Write(b.fSys, path)
+	}
+	return nil
+}
+
+// validateOutput checks the resources against their
+// OpenAPI schemas, reporting every violation at once.
+func validateOutput(m resmap.ResMap, crdSchemas spec.Definitions) error {
+	violations := openapi.NewValidator(crdSchemas).Validate(m)
+	if len(violations) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString("resources violate their OpenAPI schema:")
+	for _, v := range violations {
+		b.WriteString("\n  ")
+		b.WriteString(v.String())
+	}
+	return errors.New(b.String())
+}
+
+// addOriginAnnotations annotates each resource with its
+// tracked origin and transformations, if any.
+func addOriginAnnotations(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		annotations := r.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		if o := r.GetOrigin(); o != nil {
+			annotations[konfig.OriginAnnotation] = o.String()
+		}
+		if ts := r.GetTransformations(); len(ts) > 0 {
+			y, err := yaml.Marshal(ts)
+			if err != nil {
+				return err
+			}
+			annotations[konfig.TransformationsAnnotation] = string(y)
+		}
+		if len(annotations) > 0 {
+			r.SetAnnotations(annotations)
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ package krusty
 
 import (
 	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/types"
 )
 
@@ -27,14 +28,66 @@ type Options struct {
 
 	// Options related to kustomize plugins.
 	PluginConfig *types.PluginConfig
+
+	// If non-nil, plugins compiled into the program using
+	// the Kustomizer, found by the apiVersion and kind of
+	// their configuration before any Go or exec plugin.
+	// See type definition.
+	PluginRegistry *resmap.PluginRegistry
+
+	// When true, annotate each resource with the file or
+	// generator it came from, and the transformers that
+	// modified it.
+	AddOriginAnnotations bool
+
+	// The maximum number of entries of resource lists, e.g.
+	// remote bases, read at once across the whole build.
+	// Output order doesn't depend on it.  Values below two
+	// mean one at a time.  When above one, the FileSystem
+	// given to the Kustomizer must be safe for concurrent
+	// reads.
+	Concurrency int
+
+	// If non-nil, clones of remote git bases are kept in,
+	// and reused from, the cache it configures.  If nil,
+	// remote bases are fetched anew on every build.
+	GitCache *types.GitCacheConfig
+
+	// How to clone remote git bases; see type definition.
+	GitCloner types.GitCloner
+
+	// When true, check every resource built against the
+	// OpenAPI schema of its type, be it a Kubernetes type,
+	// or a type defined by a CRD named in a kustomization,
+	// failing the build on any violation.  Resources of
+	// types with no known schema aren't checked.
+	ValidateOutput bool
+
+	// Whether, and how, to pin container images by
+	// digest; see type definition.
+	ImagePinning types.ImagePinning
+
+	// If not empty, only the resources matching one of these
+	// selectors are output.  The selection is made after all
+	// transformations, so that the resources output are the
+	// same as in the whole build.
+	Select []types.Selector
+
+	// The resources matching any of these selectors aren't
+	// output, as for Select.
+	Exclude []types.Selector
 }
 
 // MakeDefaultOptions returns a default instance of Options.
 func MakeDefaultOptions() *Options {
 	return &Options{
-		RerorderTransformer: "legacy",
-		LoadRestrictions:    types.LoadRestrictionsRootOnly,
-		DoPrune:             false,
-		PluginConfig:        konfig.DisabledPluginConfig(),
+		RerorderTransformer:  "legacy",
+		LoadRestrictions:     types.LoadRestrictionsRootOnly,
+		DoPrune:              false,
+		PluginConfig:         konfig.DisabledPluginConfig(),
+		AddOriginAnnotations: false,
+		Concurrency:          1,
+		GitCloner:            types.GitClonerExec,
+		ImagePinning:         types.ImagePinningNone,
 	}
 }
@@ -0,0 +1,118 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestOriginAnnotations(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+- service.yaml
+configMapGenerator:
+- name: config
+  literals:
+  - fruit=apple
+`)
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+`)
+	th.WriteF("/app/base/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+namePrefix: pre-
+patchesStrategicMerge:
+- patch.yaml
+`)
+	th.WriteF("/app/overlay/patch.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+spec:
+  replicas: 3
+`)
+	opts := th.MakeDefaultOptions()
+	opts.AddOriginAnnotations = true
+	m := th.Run("/app/overlay", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    alpha.config.kubernetes.io/transformations: |
+      - configuredBy: PatchStrategicMergeTransformer
+        configuredIn: kustomization.yaml
+      - configuredBy: PrefixSuffixTransformer
+        configuredIn: kustomization.yaml
+    config.kubernetes.io/origin: |
+      path: ../base/deployment.yaml
+  name: pre-dep
+spec:
+  replicas: 3
+---
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    alpha.config.kubernetes.io/transformations: |
+      - configuredBy: PrefixSuffixTransformer
+        configuredIn: kustomization.yaml
+    config.kubernetes.io/origin: |
+      path: ../base/service.yaml
+  name: pre-svc
+---
+apiVersion: v1
+data:
+  fruit: apple
+kind: ConfigMap
+metadata:
+  annotations:
+    alpha.config.kubernetes.io/transformations: |
+      - configuredBy: PrefixSuffixTransformer
+        configuredIn: kustomization.yaml
+      - configuredBy: HashTransformer
+        configuredIn: kustomization.yaml
+    config.kubernetes.io/origin: |
+      configuredBy: ConfigMapGenerator
+      configuredIn: ../base/kustomization.yaml
+  name: pre-config-489tc4g9ft
+`)
+}
+
+func TestNoOriginAnnotationsByDefault(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- service.yaml
+namePrefix: pre-
+`)
+	th.WriteF("/app/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: pre-svc
+`)
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// greetingGenerator is a generator compiled into the test,
+// as into a program embedding kustomize.
+type greetingGenerator struct {
+	h        *resmap.PluginHelpers
+	Greeting string `json:"greeting,omitempty" yaml:"greeting,omitempty"`
+}
+
+func (p *greetingGenerator) Config(h *resmap.PluginHelpers, c []byte) error {
+	p.h = h
+	return yaml.Unmarshal(c, p)
+}
+
+func (p *greetingGenerator) Generate() (resmap.ResMap, error) {
+	return p.h.ResmapFactory().NewResMapFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: greeting
+data:
+  greeting: ` + p.Greeting))
+}
+
+// upperCaseTransformer upper cases the values of
+// the data of ConfigMaps.
+type upperCaseTransformer struct{}
+
+func (p *upperCaseTransformer) Config(*resmap.PluginHelpers, []byte) error {
+	return nil
+}
+
+func (p *upperCaseTransformer) Transform(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		if r.GetKind() != "ConfigMap" {
+			continue
+		}
+		obj := r.Map()
+		data, _ := obj["data"].(map[string]interface{})
+		for k, v := range data {
+			data[k] = strings.ToUpper(v.(string))
+		}
+		r.SetMap(obj)
+	}
+	return nil
+}
+
+func makePluginRegistry() *resmap.PluginRegistry {
+	r := resmap.NewPluginRegistry()
+	r.RegisterGenerator("someteam.example.com/v1", "GreetingGenerator",
+		func() resmap.GeneratorPlugin { return &greetingGenerator{} })
+	r.RegisterTransformer("someteam.example.com/v1", "UpperCaseTransformer",
+		func() resmap.TransformerPlugin { return &upperCaseTransformer{} })
+	return r
+}
+
+func TestPluginRegistry(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+generators:
+- greeting.yaml
+transformers:
+- upperCase.yaml
+`)
+	th.WriteF("/app/greeting.yaml", `
+apiVersion: someteam.example.com/v1
+kind: GreetingGenerator
+metadata:
+  name: greeting
+greeting: hello
+`)
+	th.WriteF("/app/upperCase.yaml", `
+apiVersion: someteam.example.com/v1
+kind: UpperCaseTransformer
+metadata:
+  name: upperCase
+`)
+	// Only builtins are allowed, so unregistered
+	// plugins fail, but registered ones are loaded.
+	o := th.MakeDefaultOptions()
+	err := th.RunWithErr("/app", o)
+	if err == nil || !strings.Contains(
+		err.Error(), types.NewErrOnlyBuiltinPluginsAllowed("GreetingGenerator").Error()) {
+		t.Fatalf("expected only builtin plugins to be allowed, got %v", err)
+	}
+	o.PluginRegistry = makePluginRegistry()
+	m := th.Run("/app", o)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  greeting: HELLO
+kind: ConfigMap
+metadata:
+  name: greeting
+`)
+}
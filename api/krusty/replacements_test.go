@@ -0,0 +1,134 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeReplacementsBase(th kusttest_test.Harness) {
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+- service.yaml
+`)
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1.0
+        env:
+        - name: SERVICE
+          value: placeholder
+        - name: URL
+          value: http://placeholder:80/api
+`)
+	th.WriteF("/app/base/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  ports:
+  - port: 8080
+`)
+}
+
+func TestReplacements(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeReplacementsBase(th)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+namePrefix: pre-
+replacements:
+- source:
+    objref:
+      kind: Service
+      name: svc
+  targets:
+  - objref:
+      kind: Deployment
+    fieldrefs:
+    - spec.template.spec.containers[name=app].env[name=SERVICE].value
+  - objref:
+      kind: Deployment
+    fieldrefs:
+    - spec.template.spec.containers[name=app].env[name=URL].value
+    options:
+      delimiter: /
+      index: 2
+- source:
+    objref:
+      kind: Service
+      name: svc
+    fieldref: spec.ports[0].port
+  target:
+    objref:
+      kind: Deployment
+    fieldrefs:
+    - spec.replicas
+`)
+	m := th.Run("/app/overlay", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: pre-dep
+spec:
+  replicas: 8080
+  template:
+    spec:
+      containers:
+      - env:
+        - name: SERVICE
+          value: pre-svc
+        - name: URL
+          value: http://pre-svc/api
+        image: app:1.0
+        name: app
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: pre-svc
+spec:
+  ports:
+  - port: 8080
+`)
+}
+
+func TestReplacementsUnmatchedTarget(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeReplacementsBase(th)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+replacements:
+- source:
+    value: x
+  target:
+    objref:
+      kind: StatefulSet
+    fieldrefs:
+    - metadata.name
+`)
+	err := th.RunWithErr("/app/overlay", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(), "no resources match replacement target") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
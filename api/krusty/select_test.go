@@ -0,0 +1,126 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/resid"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func writeSelectApp(th kusttest_test.Harness) {
+	th.WriteK("/app", `
+namePrefix: p-
+resources:
+- resources.yaml
+configMapGenerator:
+- name: config
+  literals:
+  - color=red
+`)
+	th.WriteF("/app/resources.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    tier: front
+spec:
+  template:
+    spec:
+      volumes:
+      - name: config
+        configMap:
+          name: config
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: db
+  labels:
+    tier: back
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  labels:
+    tier: front
+`)
+}
+
+func TestSelect(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeSelectApp(th)
+	opts := th.MakeDefaultOptions()
+	opts.Select = []types.Selector{
+		{Gvk: resid.Gvk{Kind: "Deployment"}, LabelSelector: "tier=front"},
+		{Gvk: resid.Gvk{Kind: "ConfigMap"}},
+	}
+	m := th.Run("/app", opts)
+	// The reference to the config map has its hash,
+	// whether or not the config map is output.
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    tier: front
+  name: p-web
+spec:
+  template:
+    spec:
+      volumes:
+      - configMap:
+          name: p-config-m2968fgcc6
+        name: config
+---
+apiVersion: v1
+data:
+  color: red
+kind: ConfigMap
+metadata:
+  name: p-config-m2968fgcc6
+`)
+
+	opts.Exclude = []types.Selector{{Gvk: resid.Gvk{Kind: "ConfigMap"}}}
+	m = th.Run("/app", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    tier: front
+  name: p-web
+spec:
+  template:
+    spec:
+      volumes:
+      - configMap:
+          name: p-config-m2968fgcc6
+        name: config
+`)
+}
+
+func TestExclude(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeSelectApp(th)
+	opts := th.MakeDefaultOptions()
+	// Names match as before and after prefixing.
+	opts.Exclude = []types.Selector{
+		{Name: "web"},
+		{Name: "p-config.*"},
+	}
+	m := th.Run("/app", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    tier: back
+  name: p-db
+`)
+}
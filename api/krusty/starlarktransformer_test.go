@@ -0,0 +1,66 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+// A Starlark program, in a file beside the kustomization,
+// runs with plugins restricted to builtins.
+func TestStarlarkTransformer(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+namePrefix: p-
+resources:
+- deployment.yaml
+transformers:
+- starlark.yaml
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v1
+`)
+	th.WriteF("/app/starlark.yaml", `
+apiVersion: builtin
+kind: StarlarkTransformer
+metadata:
+  name: pull-always
+path: pullalways.star
+`)
+	th.WriteF("/app/pullalways.star", `
+def run(items):
+  for r in items:
+    if r["kind"] != "Deployment":
+      continue
+    for c in r["spec"]["template"]["spec"]["containers"]:
+      c["imagePullPolicy"] = "Always"
+
+run(resourceList["items"])
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: p-app
+spec:
+  template:
+    spec:
+      containers:
+      - image: app:v1
+        imagePullPolicy: Always
+        name: app
+`)
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeValidatedDeployment(th kusttest_test.Harness, path, replicas string) {
+	th.WriteF(path, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+  labels:
+    app: dep
+spec:
+  replicas: `+replicas+`
+  selector:
+    matchLabels:
+      app: dep
+  template:
+    metadata:
+      labels:
+        app: dep
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.7.9
+        ports:
+        - containerPort: 80
+`)
+}
+
+func TestValidateOutputValid(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeBaseWithCrd(th)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+- deployment.yaml
+`)
+	writeValidatedDeployment(th, "/app/overlay/deployment.yaml", "3")
+	opts := th.MakeDefaultOptions()
+	opts.ValidateOutput = true
+	th.Run("/app/overlay", opts)
+}
+
+func TestValidateOutputViolations(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeBaseWithCrd(th)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+- deployment.yaml
+patchesStrategicMerge:
+- patch.yaml
+- mykind.yaml
+`)
+	writeValidatedDeployment(th, "/app/overlay/deployment.yaml", "three")
+	th.WriteF("/app/overlay/patch.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+spec:
+  template:
+    spec:
+      containres:
+      - name: sidecar
+`)
+	th.WriteF("/app/overlay/mykind.yaml", `
+apiVersion: jingfang.example.com/v1beta1
+kind: MyKind
+metadata:
+  name: mykind
+spec:
+  beeRef:
+    nmae: bee
+  secretRef:
+    name: crdsecret
+    key: password
+`)
+	opts := th.MakeDefaultOptions()
+	// Builds fine without validation.
+	th.Run("/app/overlay", opts)
+	opts.ValidateOutput = true
+	err := th.RunWithErr("/app/overlay", opts)
+	for _, expected := range []string{
+		"apps_v1_Deployment|~X|dep: spec.replicas: " +
+			`expected integer, got string "three"`,
+		"apps_v1_Deployment|~X|dep: spec.template.spec.containres: unknown field",
+		"jingfang.example.com_v1beta1_MyKind|~X|x-mykind: spec.beeRef.nmae: unknown field",
+		"jingfang.example.com_v1beta1_MyKind|~X|x-mykind: spec.secretRef.key: unknown field",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected %q in error: %v", expected, err)
+		}
+	}
+}
@@ -0,0 +1,485 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package kustvalidate checks a kustomization file against
+// the schema of types.Kustomization, reporting every problem
+// found along with its line and column.
+package kustvalidate
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	yaml11 "gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// Severity distinguishes problems that fail a build
+// from those that merely deserve attention.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a problem found at a place in a kustomization file.
+type Issue struct {
+	// Path of the kustomization file.
+	Path string
+	// Line and Column, both starting at one, of the problem;
+	// zero if the file couldn't be parsed far enough to know.
+	Line   int
+	Column int
+	// Field is the path of the field holding the problem,
+	// e.g. configMapGenerator[0].literals, or empty for
+	// the file as a whole.
+	Field    string
+	Severity Severity
+	Message  string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf(
+		"%s:%d:%d: %s: %s", i.Path, i.Line, i.Column, i.Severity, i.Message)
+}
+
+// Issues is a list of Issue, in file order.
+type Issues []Issue
+
+// Errors returns the issues of SeverityError.
+func (is Issues) Errors() Issues {
+	var result Issues
+	for _, i := range is {
+		if i.Severity == SeverityError {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func (is Issues) String() string {
+	var b strings.Builder
+	for _, i := range is {
+		b.WriteString(i.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Fields that still work, but that have better replacements.
+var deprecatedFields = map[string]string{
+	"bases":           "resources",
+	"imageTags":       "images",
+	"patchesJson6902": "patches",
+}
+
+// ValidateDir validates the kustomization file in the given
+// directory.
+func ValidateDir(fSys filesys.FileSystem, dir string) (Issues, error) {
+	var found []string
+	for _, n := range konfig.RecognizedKustomizationFileNames() {
+		if fSys.Exists(filepath.Join(dir, n)) {
+			found = append(found, n)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf(
+			"unable to find one of %v in directory '%s'",
+			konfig.RecognizedKustomizationFileNames(), dir)
+	case 1:
+	default:
+		return nil, fmt.Errorf(
+			"found multiple kustomization files %v in directory '%s'",
+			found, dir)
+	}
+	path := filepath.Join(dir, found[0])
+	data, err := fSys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Validate(path, data), nil
+}
+
+// Validate validates the given content of the kustomization
+// file at the given path.  The path is only used in issues.
+func Validate(path string, data []byte) Issues {
+	v := &validator{path: path}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		v.addParseError(err)
+		return v.issues
+	}
+	if len(doc.Content) == 0 {
+		// An empty file is an empty kustomization.
+		return nil
+	}
+	root := resolve(doc.Content[0])
+	if root.Kind != yaml.MappingNode {
+		v.addError(root, "", "expected a mapping of kustomization fields")
+		return v.issues
+	}
+	v.checkTypeMeta(root)
+	v.checkFields(root, "", reflect.TypeOf(types.Kustomization{}))
+	return v.issues
+}
+
+type validator struct {
+	path   string
+	issues Issues
+}
+
+func (v *validator) add(
+	n *yaml.Node, field string, s Severity, format string, args ...interface{}) {
+	i := Issue{
+		Path:     v.path,
+		Field:    field,
+		Severity: s,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	if n != nil {
+		i.Line, i.Column = n.Line, n.Column
+	}
+	v.issues = append(v.issues, i)
+}
+
+// The yaml parser reports only the line of a syntax error.
+var syntaxErrorLine = regexp.MustCompile(`^yaml: line (\d+): `)
+
+func (v *validator) addParseError(err error) {
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	var n *yaml.Node
+	if m := syntaxErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		n = &yaml.Node{Line: line, Column: 1}
+		msg = strings.TrimPrefix(err.Error(), m[0])
+	}
+	v.addError(n, "", "%s", msg)
+}
+
+func (v *validator) addError(
+	n *yaml.Node, field string, format string, args ...interface{}) {
+	v.add(n, field, SeverityError, format, args...)
+}
+
+func (v *validator) addWarning(
+	n *yaml.Node, field string, format string, args ...interface{}) {
+	v.add(n, field, SeverityWarning, format, args...)
+}
+
+// checkTypeMeta does what Kustomization.EnforceFields does,
+// but with the location of the offending values.
+func (v *validator) checkTypeMeta(root *yaml.Node) {
+	var k types.Kustomization
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "kind" {
+			k.Kind = resolve(root.Content[i+1]).Value
+		}
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, val := root.Content[i], resolve(root.Content[i+1])
+		if val.Kind != yaml.ScalarNode || decodeScalar(val) == nil {
+			continue
+		}
+		switch {
+		case key.Value == "apiVersion" && val.Value != k.ExpectedVersion():
+			v.addError(val, key.Value,
+				"apiVersion should be %s", k.ExpectedVersion())
+		case key.Value == "kind" && val.Value != types.KustomizationKind &&
+			val.Value != types.ComponentKind:
+			v.addError(val, key.Value, "kind should be %s or %s",
+				types.KustomizationKind, types.ComponentKind)
+		}
+	}
+}
+
+// checkFields checks the mapping n against the struct type t.
+func (v *validator) checkFields(n *yaml.Node, field string, t reflect.Type) {
+	fields := jsonFields(t)
+	seen := make(map[string]*yaml.Node)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, val := n.Content[i], n.Content[i+1]
+		name := k.Value
+		child := join(field, name)
+		if first, ok := seen[name]; ok {
+			// The last value wins.
+			v.addWarning(k, child,
+				"field %q is repeated; its value at line %d is ignored",
+				name, first.Line)
+		}
+		seen[name] = k
+		ft, ok := fields[name]
+		if !ok && field == "" {
+			// E.g. imageTags, read as images.
+			if newName, isDeprecated := deprecatedFields[name]; isDeprecated {
+				ft, ok = fields[newName]
+			}
+		}
+		if !ok {
+			if match := caseInsensitiveMatch(name, fields); match != "" {
+				// The json decoder accepts this, so it works, but
+				// nothing else, e.g. kustomize edit, will see it.
+				v.addWarning(k, child,
+					"field %q should be spelled %q", name, match)
+				v.checkValue(val, join(field, match), fields[match])
+				continue
+			}
+			msg := fmt.Sprintf("unknown field %q", name)
+			if field != "" {
+				msg += " in " + field
+			}
+			if s := suggest(name, fields); s != "" {
+				msg += fmt.Sprintf("; did you mean %q?", s)
+			}
+			v.addError(k, child, "%s", msg)
+			continue
+		}
+		if newName, isDeprecated := deprecatedFields[name]; isDeprecated && field == "" {
+			v.addWarning(k, child,
+				"field %q is deprecated; use %q instead", name, newName)
+		}
+		if name == "patches" && field == "" {
+			v.checkLegacyPatches(val, child, ft)
+			continue
+		}
+		v.checkValue(val, child, ft)
+	}
+}
+
+// checkLegacyPatches allows, with a warning, patches given as
+// file paths, which are then read as patchesStrategicMerge.
+func (v *validator) checkLegacyPatches(n *yaml.Node, field string, t reflect.Type) {
+	n = resolve(n)
+	if n.Kind == yaml.SequenceNode {
+		for i, e := range n.Content {
+			if e := resolve(e); e.Kind == yaml.ScalarNode && isString(e) {
+				v.addWarning(e, fmt.Sprintf("%s[%d]", field, i),
+					"a file path in patches is deprecated; "+
+						"list it in patchesStrategicMerge instead")
+				return
+			}
+		}
+	}
+	v.checkValue(n, field, t)
+}
+
+// checkValue checks the node n against the type t.
+func (v *validator) checkValue(n *yaml.Node, field string, t reflect.Type) {
+	n = resolve(n)
+	if n.Kind == yaml.ScalarNode && decodeScalar(n) == nil {
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if n.Kind != yaml.MappingNode {
+			v.addError(n, field, "%s must be a mapping, not %s", field, describe(n))
+			return
+		}
+		v.checkFields(n, field, t)
+	case reflect.Map:
+		if n.Kind != yaml.MappingNode {
+			v.addError(n, field, "%s must be a mapping, not %s", field, describe(n))
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			v.checkValue(n.Content[i+1],
+				join(field, n.Content[i].Value), t.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		if n.Kind != yaml.SequenceNode {
+			v.addError(n, field, "%s must be a list, not %s", field, describe(n))
+			return
+		}
+		for i, e := range n.Content {
+			v.checkValue(e, fmt.Sprintf("%s[%d]", field, i), t.Elem())
+		}
+	case reflect.String:
+		if n.Kind != yaml.ScalarNode || !isString(n) {
+			v.addError(n, field, "%s must be a string, not %s", field, describe(n))
+		}
+	case reflect.Bool:
+		if _, ok := decodeScalar(n).(bool); n.Kind != yaml.ScalarNode || !ok {
+			v.addError(n, field, "%s must be true or false, not %s", field, describe(n))
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n.Kind != yaml.ScalarNode || !isInteger(n) {
+			v.addError(n, field, "%s must be an integer, not %s", field, describe(n))
+		}
+	case reflect.Float32, reflect.Float64:
+		if n.Kind != yaml.ScalarNode || !isNumber(n) {
+			v.addError(n, field, "%s must be a number, not %s", field, describe(n))
+		}
+	}
+}
+
+// decodeScalar returns the value that the kustomization
+// decoder, sigs.k8s.io/yaml, reads from the scalar n.  That
+// follows YAML 1.1, as gopkg.in/yaml.v2 does, so e.g. yes is
+// true, while the YAML 1.2 parser used here for its positions
+// would read a string.
+func decodeScalar(n *yaml.Node) interface{} {
+	if n.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|
+		yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return n.Value
+	}
+	s := n.Value
+	if n.Style&yaml.TaggedStyle != 0 {
+		s = n.Tag + " " + s
+	}
+	var result interface{}
+	if err := yaml11.Unmarshal([]byte(s), &result); err != nil {
+		return n.Value
+	}
+	return result
+}
+
+// isString is true for the scalars that the json decoder
+// will put in a string.
+func isString(n *yaml.Node) bool {
+	_, ok := decodeScalar(n).(string)
+	return ok
+}
+
+func isNumber(n *yaml.Node) bool {
+	switch decodeScalar(n).(type) {
+	case int, int64, uint64, float64:
+		return true
+	}
+	return false
+}
+
+// isInteger is true for the numbers that the json
+// decoder will put in an integer, which include those
+// written as floats with no fractional part, e.g. 3.0.
+func isInteger(n *yaml.Node) bool {
+	switch x := decodeScalar(n).(type) {
+	case int, int64, uint64:
+		return true
+	case float64:
+		return x == math.Trunc(x) && !math.IsInf(x, 0)
+	}
+	return false
+}
+
+func describe(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	}
+	return fmt.Sprintf("%q", n.Value)
+}
+
+func resolve(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	return n
+}
+
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+// jsonFields maps the names that encoding/json decodes
+// into the struct type t to the types of their fields.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	result := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			for k, v := range jsonFields(ft) {
+				result[k] = v
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			// Unexported.
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		result[name] = f.Type
+	}
+	return result
+}
+
+func caseInsensitiveMatch(name string, fields map[string]reflect.Type) string {
+	for f := range fields {
+		if strings.EqualFold(f, name) {
+			return f
+		}
+	}
+	return ""
+}
+
+// suggest returns the field nearest to the given name,
+// if it's near enough to be a likely misspelling.
+func suggest(name string, fields map[string]reflect.Type) string {
+	best, bestDist := "", len(name)/3+2
+	for f := range fields {
+		d := distance(strings.ToLower(name), strings.ToLower(f))
+		if d < bestDist || (d == bestDist && best != "" && f < best) {
+			best, bestDist = f, d
+		}
+	}
+	return best
+}
+
+// distance is the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func min(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
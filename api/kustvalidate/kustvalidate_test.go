@@ -0,0 +1,226 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kustvalidate_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	. "sigs.k8s.io/kustomize/api/kustvalidate"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := map[string]struct {
+		content  string
+		expected string
+	}{
+		"valid": {
+			content: `
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+namePrefix: p-
+commonLabels:
+  app: foo
+resources:
+- deployment.yaml
+configMapGenerator:
+- name: cm
+  literals:
+  - a=b
+  generatorOptions:
+    disableNameSuffixHash: true
+replicas:
+- name: dep
+  count: 3
+patches:
+- path: patch.yaml
+  target:
+    kind: Deployment
+    name: dep
+`,
+		},
+		"empty": {},
+		"misspelled": {
+			content: `
+resources:
+- deployment.yaml
+patchesStrategicMerg:
+- patch.yaml
+configMapGenerator:
+- name: cm
+  literal:
+  - a=b
+`,
+			expected: `
+k.yaml:4:1: error: unknown field "patchesStrategicMerg"; did you mean "patchesStrategicMerge"?
+k.yaml:8:3: error: unknown field "literal" in configMapGenerator[0]; did you mean "literals"?
+`,
+		},
+		"nothingNear": {
+			content: `
+resources:
+- deployment.yaml
+frobnicate: true
+`,
+			expected: `
+k.yaml:4:1: error: unknown field "frobnicate"
+`,
+		},
+		"wrongTypes": {
+			content: `
+namePrefix: [a]
+resources: deployment.yaml
+commonLabels:
+  version: 1.0
+replicas:
+- name: dep
+  count: three
+generatorOptions:
+  disableNameSuffixHash: maybe
+`,
+			expected: `
+k.yaml:2:13: error: namePrefix must be a string, not a list
+k.yaml:3:12: error: resources must be a list, not "deployment.yaml"
+k.yaml:5:12: error: commonLabels.version must be a string, not "1.0"
+k.yaml:8:10: error: replicas[0].count must be an integer, not "three"
+k.yaml:10:26: error: generatorOptions.disableNameSuffixHash must be true or false, not "maybe"
+`,
+		},
+		"yaml11": {
+			// Read as the kustomization decoder reads them,
+			// following YAML 1.1 rather than YAML 1.2.
+			content: `
+namePrefix: no
+nameSuffix: "yes"
+commonLabels:
+  enabled: !!str on
+replicas:
+- name: dep
+  count: 3.0
+generatorOptions:
+  disableNameSuffixHash: yes
+`,
+			expected: `
+k.yaml:2:13: error: namePrefix must be a string, not "no"
+`,
+		},
+		"deprecated": {
+			content: `
+bases:
+- ../base
+imageTags:
+- name: nginx
+  newTag: v2
+patchesJson6902:
+- target:
+    kind: Deployment
+    name: dep
+  path: patch.yaml
+`,
+			expected: `
+k.yaml:2:1: warning: field "bases" is deprecated; use "resources" instead
+k.yaml:4:1: warning: field "imageTags" is deprecated; use "images" instead
+k.yaml:7:1: warning: field "patchesJson6902" is deprecated; use "patches" instead
+`,
+		},
+		"legacyPatches": {
+			content: `
+patches:
+- patch.yaml
+`,
+			expected: `
+k.yaml:3:3: warning: a file path in patches is deprecated; list it in patchesStrategicMerge instead
+`,
+		},
+		"typeMetaAndCase": {
+			content: `
+apiVersion: v1
+kind: Kustomization
+NamePrefix: p-
+namespace: a
+namespace: b
+`,
+			expected: `
+k.yaml:2:13: error: apiVersion should be kustomize.config.k8s.io/v1beta1
+k.yaml:4:1: warning: field "NamePrefix" should be spelled "namePrefix"
+k.yaml:6:1: warning: field "namespace" is repeated; its value at line 5 is ignored
+`,
+		},
+		"component": {
+			content: `
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+components:
+- ../tls
+`,
+		},
+		"componentVersion": {
+			content: `
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Component
+`,
+			expected: `
+k.yaml:2:13: error: apiVersion should be kustomize.config.k8s.io/v1alpha1
+`,
+		},
+		"unknownKind": {
+			content: `
+kind: Overlay
+`,
+			expected: `
+k.yaml:2:7: error: kind should be Kustomization or Component
+`,
+		},
+		"notAMapping": {
+			content: `
+- resources
+`,
+			expected: `
+k.yaml:2:1: error: expected a mapping of kustomization fields
+`,
+		},
+		"syntax": {
+			content: `
+resources:
+- a
+  b: c
+`,
+			expected: `
+k.yaml:4:1: error: mapping values are not allowed in this context
+`,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			actual := Validate("k.yaml", []byte(tc.content)).String()
+			expected := strings.TrimPrefix(tc.expected, "\n")
+			if actual != expected {
+				t.Fatalf("expected\n%s\nbut got\n%s", expected, actual)
+			}
+		})
+	}
+}
+
+func TestValidateDir(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	_, err := ValidateDir(fSys, "/app")
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	err = fSys.WriteFile("/app/kustomization.yml", []byte(`
+nameSufix: -s
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues, err := ValidateDir(fSys, "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues.Errors()) != 1 || issues[0].Field != "nameSufix" ||
+		issues[0].Path != "/app/kustomization.yml" {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+}
@@ -10,12 +10,15 @@ import (
 	"os"
 	"path"
 	"regexp"
+	"sort"
 	"strings"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
 	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/api/internal/sops"
 	"sigs.k8s.io/kustomize/api/types"
 )
 
@@ -60,9 +63,113 @@ func (kvl *loader) Load(
 		return nil, errors.Wrap(err, fmt.Sprintf(
 			"file sources: %v", args.FileSources))
 	}
+	all = append(all, pairs...)
+
+	pairs, err = kvl.keyValuesFromEncryptedEnvFiles(args.EncryptedEnvSources)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf(
+			"encrypted env source files: %v", args.EncryptedEnvSources))
+	}
+	all = append(all, pairs...)
+
+	pairs, err = kvl.keyValuesFromEncryptedFileSources(args.EncryptedFileSources)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf(
+			"encrypted file sources: %v", args.EncryptedFileSources))
+	}
 	return append(all, pairs...), nil
 }
 
+// decrypt returns the cleartext of the sops-encrypted
+// file at path, which is never written anywhere.
+func (kvl *loader) decrypt(path string) ([]byte, error) {
+	content, err := kvl.ldr.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return sops.Decrypt(path, content)
+}
+
+func (kvl *loader) keyValuesFromEncryptedFileSources(
+	sources []string) ([]types.Pair, error) {
+	var kvs []types.Pair
+	for _, s := range sources {
+		k, fPath, err := parseFileSource(s)
+		if err != nil {
+			return nil, err
+		}
+		content, err := kvl.decrypt(fPath)
+		if err != nil {
+			return nil, err
+		}
+		kvs = append(kvs, types.Pair{Key: k, Value: string(content)})
+	}
+	return kvs, nil
+}
+
+func (kvl *loader) keyValuesFromEncryptedEnvFiles(
+	paths []string) ([]types.Pair, error) {
+	var kvs []types.Pair
+	for _, p := range paths {
+		content, err := kvl.decrypt(p)
+		if err != nil {
+			return nil, err
+		}
+		var more []types.Pair
+		switch sops.FormatForPath(p) {
+		case sops.Dotenv:
+			more, err = kvl.keyValuesFromLines(content)
+		case sops.Yaml, sops.Json:
+			more, err = kvl.keyValuesFromMapping(content)
+		default:
+			err = fmt.Errorf(
+				"encrypted env file '%s' should be dotenv (.env), YAML or JSON", p)
+		}
+		if err != nil {
+			return nil, err
+		}
+		kvs = append(kvs, more...)
+	}
+	return kvs, nil
+}
+
+// keyValuesFromMapping parses a YAML or JSON mapping
+// of keys to scalar values into key-value pairs.  The
+// values are as written, e.g. a number keeps its digits.
+func (kvl *loader) keyValuesFromMapping(content []byte) ([]types.Pair, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	m := doc.Content[0]
+	if m.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("content isn't a mapping of keys to values")
+	}
+	var kvs []types.Pair
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		k, v := m.Content[i].Value, m.Content[i+1]
+		if err := kvl.validator.IsEnvVarName(k); err != nil {
+			return nil, err
+		}
+		if v.Kind == yaml.AliasNode {
+			v = v.Alias
+		}
+		if v.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("value of key %s isn't a scalar", k)
+		}
+		value := v.Value
+		if v.ShortTag() == "!!null" {
+			value = ""
+		}
+		kvs = append(kvs, types.Pair{Key: k, Value: value})
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+	return kvs, nil
+}
+
 func keyValuesFromLiteralSources(sources []string) ([]types.Pair, error) {
 	var kvs []types.Pair
 	for _, s := range sources {
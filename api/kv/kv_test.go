@@ -5,10 +5,12 @@ package kv
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"sigs.k8s.io/kustomize/api/filesys"
 	ldr "sigs.k8s.io/kustomize/api/loader"
+	sopstest_test "sigs.k8s.io/kustomize/api/testutils/sopstest"
 	valtest_test "sigs.k8s.io/kustomize/api/testutils/valtest"
 	"sigs.k8s.io/kustomize/api/types"
 )
@@ -96,6 +98,89 @@ func TestKeyValuesFromFileSources(t *testing.T) {
 	}
 }
 
+func TestKeyValuesFromEncryptedSources(t *testing.T) {
+	keys := sopstest_test.MakeKeys(t)
+	fSys := filesys.MakeFsInMemory()
+	fSys.WriteFile("/files/db.env", keys.Encrypt("db.env", `
+DB_USER=admin
+DB_PASSWORD=hunter2
+`))
+	fSys.WriteFile("/files/api.yaml", keys.Encrypt("api.yaml", `
+API_TOKEN: t0k3n
+API_PORT: 8443
+`))
+	fSys.WriteFile("/files/tls.key", keys.Encrypt("tls.key", "KEY"))
+
+	args := types.KvPairSources{
+		EncryptedEnvSources:  []string{"files/db.env", "files/api.yaml"},
+		EncryptedFileSources: []string{"key=files/tls.key"},
+	}
+	kvl := makeKvLoader(fSys)
+	restore := keys.Install(false)
+	kvs, err := kvl.Load(args)
+	restore()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []types.Pair{
+		{Key: "DB_USER", Value: "admin"},
+		{Key: "DB_PASSWORD", Value: "hunter2"},
+		{Key: "API_PORT", Value: "8443"},
+		{Key: "API_TOKEN", Value: "t0k3n"},
+		{Key: "key", Value: "KEY"},
+	}
+	if !reflect.DeepEqual(kvs, expected) {
+		t.Fatalf("expected %v, got %v", expected, kvs)
+	}
+
+	// Without keys.
+	kvl = makeKvLoader(fSys)
+	restore = sopstest_test.MakeKeys(t).Install(false)
+	_, err = kvl.Load(args)
+	restore()
+	if err == nil || !strings.Contains(err.Error(),
+		"decrypting 'files/db.env': Error getting data key") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestKeyValuesFromMapping(t *testing.T) {
+	expected := []types.Pair{
+		{Key: "ACCOUNT", Value: "123456789012"},
+		{Key: "EMPTY", Value: ""},
+		{Key: "ENABLED", Value: "true"},
+		{Key: "MAX", Value: "9007199254740993"},
+		{Key: "RATE", Value: "0.000001"},
+		{Key: "SCALE", Value: "1.5e+20"},
+	}
+	for name, content := range map[string]string{
+		"yaml": `
+ACCOUNT: 123456789012
+EMPTY:
+ENABLED: true
+MAX: 9007199254740993
+RATE: 0.000001
+SCALE: 1.5e+20
+`,
+		"json": `{"ACCOUNT": 123456789012, "EMPTY": null, "ENABLED": true,
+"MAX": 9007199254740993, "RATE": 0.000001, "SCALE": 1.5e+20}`,
+	} {
+		kvs, err := makeKvLoader(filesys.MakeFsInMemory()).
+			keyValuesFromMapping([]byte(content))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !reflect.DeepEqual(kvs, expected) {
+			t.Fatalf("%s: expected %v, got %v", name, expected, kvs)
+		}
+	}
+	_, err := makeKvLoader(filesys.MakeFsInMemory()).
+		keyValuesFromMapping([]byte("LIST: [a, b]\n"))
+	if err == nil || err.Error() != "value of key LIST isn't a scalar" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestTrimTrailingSpacesInLines(t *testing.T) {
 	input := "\"fooKey\": \"fooValue\"   \t\n \t\t  \n\t\"barKey\": \"barValue\""
 	expected := "\"fooKey\": \"fooValue\"\n\n\t\"barKey\": \"barValue\""
@@ -482,6 +482,9 @@ func TestLoaderDisallowsLocalBaseFromRemoteOverlay(t *testing.T) {
 	if l2.Root() != cloneRoot+"/foo/base" {
 		t.Fatalf("unexpected root %s", l2.Root())
 	}
+	if !IsRemote(l2) {
+		t.Fatalf("expected a base within a clone to be remote")
+	}
 	// This is not okay.
 	_, err = l2.New("../../../highBase")
 	if err == nil {
@@ -517,6 +520,9 @@ func TestLocalLoaderReferencingGitBase(t *testing.T) {
 	if l2.Root() != cloneRoot+"/foo/base" {
 		t.Fatalf("unexpected root %s", l2.Root())
 	}
+	if IsRemote(l1) || !IsRemote(l2) {
+		t.Fatalf("expected only the git base to be remote")
+	}
 }
 
 func TestRepoDirectCycleDetection(t *testing.T) {
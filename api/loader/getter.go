@@ -5,6 +5,7 @@ package loader
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 
@@ -12,6 +13,7 @@ import (
 	"sigs.k8s.io/kustomize/api/filesys"
 	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/internal/git"
+	"sigs.k8s.io/kustomize/api/types"
 )
 
 type remoteTargetSpec struct {
@@ -78,11 +80,50 @@ func getRemoteTarget(rs *remoteTargetSpec) error {
 			new(getter.GitDetector),
 			new(getter.BitBucketDetector),
 		},
+		Getters: newGetters(),
 		Options: opts,
 	}
 	return client.Get()
 }
 
+// newGetters returns getters for the client's use only,
+// since a client configures its getters to point back to
+// it, and remote targets may be fetched concurrently.
+func newGetters() map[string]getter.Getter {
+	httpGetter := &getter.HttpGetter{Netrc: true}
+	return map[string]getter.Getter{
+		"file":  new(getter.FileGetter),
+		"git":   new(getter.GitGetter),
+		"hg":    new(getter.HgGetter),
+		"http":  httpGetter,
+		"https": httpGetter,
+	}
+}
+
+// clonerGetter returns a getter that leaves git URLs to
+// the loader's cloner.
+func clonerGetter(getter remoteTargetGetter) remoteTargetGetter {
+	return func(rs *remoteTargetSpec) error {
+		if _, err := git.NewRepoSpecFromUrl(rs.Raw); err == nil {
+			return fmt.Errorf("'%s' is left to the git cloner", rs.Raw)
+		}
+		return getter(rs)
+	}
+}
+
+// gitCacheGetter returns a getter that leaves git URLs to
+// the caching cloner, and that, offline, gets nothing.
+func gitCacheGetter(
+	c *types.GitCacheConfig, getter remoteTargetGetter) remoteTargetGetter {
+	getter = clonerGetter(getter)
+	return func(rs *remoteTargetSpec) error {
+		if c.Offline {
+			return fmt.Errorf("offline; not getting '%s'", rs.Raw)
+		}
+		return getter(rs)
+	}
+}
+
 func getNothing(rs *remoteTargetSpec) error {
 	var err error
 	rs.Dir, err = filesys.NewTmpConfirmedDir()
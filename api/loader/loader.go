@@ -6,10 +6,12 @@ package loader
 
 import (
 	"fmt"
+	"time"
 
 	"sigs.k8s.io/kustomize/api/filesys"
 	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/internal/git"
+	"sigs.k8s.io/kustomize/api/types"
 )
 
 // NewLoader returns a Loader pointed at the given target.
@@ -21,8 +23,43 @@ import (
 func NewLoader(
 	lr LoadRestrictorFunc,
 	target string, fSys filesys.FileSystem) (ifc.Loader, error) {
+	return newLoader(
+		lr, target, fSys, git.ClonerUsingGitExec, getRemoteTarget)
+}
+
+// NewLoaderWithGit returns a Loader like NewLoader does,
+// except that it, and the loaders it spawns, clone remote
+// git bases with the given cloner, and, if c is non-nil,
+// keep the clones in the cache c configures.  When go-git
+// or a cache is used, git URLs are cloned rather than fetched
+// by go-getter, which would run the git program, so that
+// they're cloned by go-git, or cached, and when offline,
+// nothing is fetched at all.
+func NewLoaderWithGit(
+	lr LoadRestrictorFunc,
+	target string, fSys filesys.FileSystem,
+	gc types.GitCloner, c *types.GitCacheConfig) (ifc.Loader, error) {
+	cloner := git.ClonerUsingGitExec
+	var getter remoteTargetGetter = getRemoteTarget
+	if gc == types.GitClonerGo {
+		cloner = git.ClonerUsingGoGit
+		getter = clonerGetter(getRemoteTarget)
+	}
+	if c == nil {
+		return newLoader(lr, target, fSys, cloner, getter)
+	}
+	return newLoader(
+		lr, target, fSys,
+		git.CachingCloner(c, cloner),
+		gitCacheGetter(c, getRemoteTarget))
+}
+
+func newLoader(
+	lr LoadRestrictorFunc,
+	target string, fSys filesys.FileSystem,
+	cloner git.Cloner, getter remoteTargetGetter) (ifc.Loader, error) {
 
-	ldr, errGet := newLoaderAtGetter(target, fSys, nil, git.ClonerUsingGitExec, getRemoteTarget)
+	ldr, errGet := newLoaderAtGetter(target, fSys, nil, cloner, getter)
 	if errGet == nil {
 		return ldr, nil
 	}
@@ -31,13 +68,59 @@ func NewLoader(
 	if errGit == nil {
 		// The target qualifies as a remote git target.
 		return newLoaderAtGitClone(
-			repoSpec, fSys, nil, git.ClonerUsingGitExec, getRemoteTarget)
+			repoSpec, fSys, nil, cloner, getter)
 	}
 
 	root, errDir := demandDirectoryRoot(fSys, target)
 	if errDir == nil {
-		return newLoaderAtConfirmedDir(lr, root, fSys, nil, git.ClonerUsingGitExec, getRemoteTarget), nil
+		return newLoaderAtConfirmedDir(lr, root, fSys, nil, cloner, getter), nil
 	}
 
 	return nil, fmt.Errorf("Error creating new loader with git: %v, dir: %v, get: %v", errGit, errDir, errGet)
 }
+
+// PruneGitCache removes from the given cache the clones of
+// remote git bases that no build has used for at least the
+// given duration, returning the directories removed.
+func PruneGitCache(
+	c *types.GitCacheConfig, maxAge time.Duration) ([]string, error) {
+	return git.PruneCache(c.AbsCacheDir, maxAge)
+}
+
+// RemoteSource reports the remote location, the ref and the
+// path within that location from which the given loader reads
+// files.  The result is false if the loader itself reads local
+// files, even if its referrer cloned them.
+func RemoteSource(ldr ifc.Loader) (repo, ref, path string, ok bool) {
+	fl, isFl := ldr.(*fileLoader)
+	if !isFl {
+		return "", "", "", false
+	}
+	if fl.repoSpec != nil {
+		path = fl.repoSpec.Path
+		if path == "" {
+			path = filesys.SelfDir
+		}
+		return fl.repoSpec.CloneSpec(), fl.repoSpec.Ref, path, true
+	}
+	if fl.rscSpec != nil {
+		return fl.rscSpec.Raw, "", filesys.SelfDir, true
+	}
+	return "", "", "", false
+}
+
+// IsRemote is true if the given loader reads files fetched
+// from a remote location, be it the root of a remote base,
+// or any directory within one.
+func IsRemote(ldr ifc.Loader) bool {
+	fl, isFl := ldr.(*fileLoader)
+	if !isFl {
+		return false
+	}
+	for ; fl != nil; fl = fl.referrer {
+		if fl.repoSpec != nil || fl.rscSpec != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resmap
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+// Diff holds the differences between two ResMaps,
+// an old one and a new one.
+type Diff struct {
+	// Added holds the CurIds of resources found
+	// only in the new ResMap.
+	Added []resid.ResId `json:"added,omitempty" yaml:"added,omitempty"`
+
+	// Removed holds the CurIds of resources found
+	// only in the old ResMap.
+	Removed []resid.ResId `json:"removed,omitempty" yaml:"removed,omitempty"`
+
+	// Changed holds the field level differences of
+	// resources found in both ResMaps.
+	Changed []ResourceDiff `json:"changed,omitempty" yaml:"changed,omitempty"`
+}
+
+// IsEmpty is true if the ResMaps don't differ.
+func (d *Diff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// ResourceDiff holds the differences between the old
+// and new versions of a resource.
+type ResourceDiff struct {
+	// OldId is the CurId of the old version.
+	OldId resid.ResId `json:"oldId" yaml:"oldId"`
+
+	// NewId is the CurId of the new version, which
+	// differs from OldId if, say, a name prefix or a
+	// name hash changed.
+	NewId resid.ResId `json:"newId" yaml:"newId"`
+
+	// Fields holds the changed fields, ordered by path.
+	Fields []FieldDiff `json:"fields" yaml:"fields"`
+}
+
+// FieldDiff holds the old and new values of a field
+// at a path like spec.template.spec.containers[0].image.
+// A nil value means the field is absent.
+type FieldDiff struct {
+	Path string      `json:"path" yaml:"path"`
+	Old  interface{} `json:"old,omitempty" yaml:"old,omitempty"`
+	New  interface{} `json:"new,omitempty" yaml:"new,omitempty"`
+}
+
+// matchById returns the only resource in the list whose id,
+// per the given id getter, equals the argument.
+func matchById(
+	list []*resource.Resource,
+	id resid.ResId, idGetter IdFromResource) (*resource.Resource, error) {
+	var result []*resource.Resource
+	for _, r := range list {
+		if id.Equals(idGetter(r)) {
+			result = append(result, r)
+		}
+	}
+	switch len(result) {
+	case 0:
+		return nil, fmt.Errorf("id in self missing from other; id: %s", id)
+	case 1:
+		return result[0], nil
+	default:
+		return nil, fmt.Errorf(
+			"id in self matches %d in other; id: %s", len(result), id)
+	}
+}
+
+// Diff implements ResMap.
+func (m *resWrangler) Diff(other ResMap) *Diff {
+	d := &Diff{}
+	pairs := make(map[*resource.Resource]*resource.Resource)
+	unmatched := make(map[*resource.Resource]bool)
+	for _, r := range other.Resources() {
+		unmatched[r] = true
+	}
+	// Match by current id first, so that resources whose
+	// original ids collide can't steal each other's match.
+	for _, r1 := range m.rList {
+		r2, err := matchById(other.Resources(), r1.CurId(), GetCurrentId)
+		if err == nil {
+			pairs[r1] = r2
+			delete(unmatched, r2)
+		}
+	}
+	// Failing that, match by original id, so that a change
+	// of name prefix, suffix or hash isn't a removal and
+	// an addition.
+	for _, r1 := range m.rList {
+		if _, ok := pairs[r1]; ok {
+			continue
+		}
+		var candidates []*resource.Resource
+		for _, r := range other.Resources() {
+			if unmatched[r] {
+				candidates = append(candidates, r)
+			}
+		}
+		r2, err := matchById(candidates, r1.OrgId(), GetOriginalId)
+		if err != nil {
+			d.Removed = append(d.Removed, r1.CurId())
+			continue
+		}
+		pairs[r1] = r2
+		delete(unmatched, r2)
+	}
+	for _, r1 := range m.rList {
+		r2, ok := pairs[r1]
+		if !ok {
+			continue
+		}
+		fields := diffValues("", r1.Map(), r2.Map(), nil)
+		if len(fields) > 0 {
+			d.Changed = append(d.Changed, ResourceDiff{
+				OldId: r1.CurId(), NewId: r2.CurId(), Fields: fields})
+		}
+	}
+	for _, r := range other.Resources() {
+		if unmatched[r] {
+			d.Added = append(d.Added, r.CurId())
+		}
+	}
+	return d
+}
+
+// diffValues appends to result the differences between the
+// old and new values found at the given path.
+func diffValues(
+	path string, old, new interface{}, result []FieldDiff) []FieldDiff {
+	oldMap, oldIsMap := old.(map[string]interface{})
+	newMap, newIsMap := new.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		return diffMaps(path, oldMap, newMap, result)
+	}
+	oldList, oldIsList := old.([]interface{})
+	newList, newIsList := new.([]interface{})
+	if oldIsList && newIsList {
+		return diffLists(path, oldList, newList, result)
+	}
+	if reflect.DeepEqual(old, new) {
+		return result
+	}
+	return append(result, FieldDiff{Path: path, Old: old, New: new})
+}
+
+func diffMaps(
+	path string, old, new map[string]interface{},
+	result []FieldDiff) []FieldDiff {
+	keys := make(map[string]bool)
+	for k := range old {
+		keys[k] = true
+	}
+	for k := range new {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+	for _, k := range sorted {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		result = diffValues(p, old[k], new[k], result)
+	}
+	return result
+}
+
+func diffLists(
+	path string, old, new []interface{}, result []FieldDiff) []FieldDiff {
+	n := len(old)
+	if len(new) > n {
+		n = len(new)
+	}
+	for i := 0; i < n; i++ {
+		var o, v interface{}
+		if i < len(old) {
+			o = old[i]
+		}
+		if i < len(new) {
+			v = new[i]
+		}
+		result = diffValues(fmt.Sprintf("%s[%d]", path, i), o, v, result)
+	}
+	return result
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resmap_test
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/resid"
+	. "sigs.k8s.io/kustomize/api/resmap"
+	resmaptest_test "sigs.k8s.io/kustomize/api/testutils/resmaptest"
+)
+
+func TestDiff(t *testing.T) {
+	cmGvk := resid.Gvk{Version: "v1", Kind: "ConfigMap"}
+	depGvk := resid.Gvk{Group: "apps", Version: "v1", Kind: "Deployment"}
+	m1 := resmaptest_test.NewRmBuilder(t, rf).
+		AddWithName("cm", map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name": "cm-abc",
+			},
+			"data": map[string]interface{}{
+				"fruit": "apple",
+			}}).
+		Add(map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name": "dep",
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(1),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "app",
+								"image": "app:1",
+							},
+						},
+					},
+				},
+			}}).
+		Add(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata": map[string]interface{}{
+				"name": "gone",
+			}}).ResMap()
+	m2 := resmaptest_test.NewRmBuilder(t, rf).
+		AddWithName("cm", map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name": "cm-def",
+			},
+			"data": map[string]interface{}{
+				"fruit": "banana",
+			}}).
+		Add(map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name": "dep",
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(1),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "app",
+								"image": "app:2",
+							},
+							map[string]interface{}{
+								"name": "sidecar",
+							},
+						},
+					},
+				},
+			}}).
+		Add(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata": map[string]interface{}{
+				"name": "new",
+			}}).ResMap()
+
+	expected := &Diff{
+		Added: []resid.ResId{
+			resid.NewResId(resid.Gvk{Version: "v1", Kind: "Service"}, "new")},
+		Removed: []resid.ResId{
+			resid.NewResId(resid.Gvk{Version: "v1", Kind: "Service"}, "gone")},
+		Changed: []ResourceDiff{
+			{
+				OldId: resid.NewResId(cmGvk, "cm-abc"),
+				NewId: resid.NewResId(cmGvk, "cm-def"),
+				Fields: []FieldDiff{
+					{Path: "data.fruit", Old: "apple", New: "banana"},
+					{Path: "metadata.name", Old: "cm-abc", New: "cm-def"},
+				},
+			},
+			{
+				OldId: resid.NewResId(depGvk, "dep"),
+				NewId: resid.NewResId(depGvk, "dep"),
+				Fields: []FieldDiff{
+					{
+						Path: "spec.template.spec.containers[0].image",
+						Old:  "app:1", New: "app:2",
+					},
+					{
+						Path: "spec.template.spec.containers[1]",
+						New:  map[string]interface{}{"name": "sidecar"},
+					},
+				},
+			},
+		},
+	}
+	actual := m1.Diff(m2)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected\n%#v\ngot\n%#v", expected, actual)
+	}
+	if !m1.Diff(m1.DeepCopy()).IsEmpty() {
+		t.Fatalf("expected no difference with a copy")
+	}
+}
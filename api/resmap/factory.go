@@ -4,6 +4,7 @@
 package resmap
 
 import (
+	"github.com/go-openapi/spec"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/internal/kusterr"
@@ -15,6 +16,8 @@ import (
 type Factory struct {
 	resF *resource.Factory
 	tf   PatchFactory
+	// OpenAPI definitions of custom resources.
+	crdSchemas spec.Definitions
 }
 
 // NewFactory returns a new resmap.Factory.
@@ -22,6 +25,15 @@ func NewFactory(rf *resource.Factory, tf PatchFactory) *Factory {
 	return &Factory{resF: rf, tf: tf}
 }
 
+// WithCrdSchemas returns a copy of the factory whose
+// patches use the given OpenAPI definitions of custom
+// resources.
+func (rmF *Factory) WithCrdSchemas(defs spec.Definitions) *Factory {
+	result := *rmF
+	result.crdSchemas = defs
+	return &result
+}
+
 // RF returns a resource.Factory.
 func (rmF *Factory) RF() *resource.Factory {
 	return rmF.resF
@@ -121,9 +133,21 @@ func (rmF *Factory) FromSecretArgs(
 
 func (rmF *Factory) MergePatches(patches []*resource.Resource) (
 	ResMap, error) {
+	if cf, ok := rmF.tf.(CrdPatchFactory); ok {
+		return cf.MergeCrdPatches(patches, rmF.resF, rmF.crdSchemas)
+	}
 	return rmF.tf.MergePatches(patches, rmF.resF)
 }
 
+// ApplySmPatch applies the strategic merge patch to target,
+// or a JSON merge patch to a resource of a type with no schema.
+func (rmF *Factory) ApplySmPatch(target, patch *resource.Resource) error {
+	if cf, ok := rmF.tf.(CrdPatchFactory); ok {
+		return cf.ApplySmPatch(target, patch, rmF.crdSchemas)
+	}
+	return target.Patch(patch.Kunstructured)
+}
+
 func newResMapFromResourceSlice(resources []*resource.Resource) (ResMap, error) {
 	result := New()
 	for _, res := range resources {
@@ -5,6 +5,7 @@
 package resmap
 
 import (
+	"github.com/go-openapi/spec"
 	"sigs.k8s.io/kustomize/api/resource"
 )
 
@@ -13,3 +14,15 @@ type PatchFactory interface {
 	MergePatches(patches []*resource.Resource,
 		rf *resource.Factory) (ResMap, error)
 }
+
+// CrdPatchFactory is a PatchFactory that can also merge
+// the lists of custom resources by key, as those of built
+// in types are, per the given CRD definitions.  A Factory
+// uses it if its PatchFactory implements it.
+type CrdPatchFactory interface {
+	PatchFactory
+	MergeCrdPatches(patches []*resource.Resource,
+		rf *resource.Factory, crdSchemas spec.Definitions) (ResMap, error)
+	ApplySmPatch(target, patch *resource.Resource,
+		crdSchemas spec.Definitions) error
+}
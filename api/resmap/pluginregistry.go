@@ -0,0 +1,81 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resmap
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resid"
+)
+
+// PluginRegistry holds plugins compiled into a program
+// embedding kustomize, by the apiVersion and kind of
+// their configuration.
+//
+// A plugin configuration naming a registered plugin, in
+// the generators or transformers field of a kustomization,
+// is given to a new instance of it, made by its factory,
+// just as for builtin plugins, rather than looking for a Go
+// or exec plugin in the plugin home.  Registered plugins are
+// part of the program, so are loaded whatever the plugin
+// restrictions.
+//
+// Register all plugins before building; the registry
+// may be read by concurrent builds.
+type PluginRegistry struct {
+	generators   map[resid.Gvk]func() GeneratorPlugin
+	transformers map[resid.Gvk]func() TransformerPlugin
+}
+
+// NewPluginRegistry returns an empty registry.
+func NewPluginRegistry() *PluginRegistry {
+	return &PluginRegistry{
+		generators:   make(map[resid.Gvk]func() GeneratorPlugin),
+		transformers: make(map[resid.Gvk]func() TransformerPlugin),
+	}
+}
+
+// RegisterGenerator registers a generator, configured by
+// objects of the given apiVersion, e.g. someteam.example.com/v1,
+// and kind, replacing any plugin registered for them.
+func (r *PluginRegistry) RegisterGenerator(
+	apiVersion, kind string, f func() GeneratorPlugin) {
+	gvk := gvkOf(apiVersion, kind)
+	delete(r.transformers, gvk)
+	r.generators[gvk] = f
+}
+
+// RegisterTransformer registers a transformer, as for
+// RegisterGenerator.
+func (r *PluginRegistry) RegisterTransformer(
+	apiVersion, kind string, f func() TransformerPlugin) {
+	gvk := gvkOf(apiVersion, kind)
+	delete(r.generators, gvk)
+	r.transformers[gvk] = f
+}
+
+// MakePlugin returns a new instance of the plugin
+// configured by objects of the given type, if one
+// is registered, or else nil.
+func (r *PluginRegistry) MakePlugin(gvk resid.Gvk) Configurable {
+	if r == nil {
+		return nil
+	}
+	if f, ok := r.generators[gvk]; ok {
+		return f()
+	}
+	if f, ok := r.transformers[gvk]; ok {
+		return f()
+	}
+	return nil
+}
+
+func gvkOf(apiVersion, kind string) resid.Gvk {
+	gvk := resid.Gvk{Version: apiVersion, Kind: kind}
+	if i := strings.LastIndex(apiVersion, "/"); i >= 0 {
+		gvk.Group = apiVersion[:i]
+		gvk.Version = apiVersion[i+1:]
+	}
+	return gvk
+}
@@ -49,6 +49,21 @@ type PluginHelpers struct {
 	ldr ifc.Loader
 	v   ifc.Validator
 	rf  *Factory
+	pc  *types.PluginConfig
+}
+
+// WithPluginConfig returns a copy of the helpers
+// that gives plugins the given plugin configuration.
+func (c *PluginHelpers) WithPluginConfig(pc *types.PluginConfig) *PluginHelpers {
+	result := *c
+	result.pc = pc
+	return &result
+}
+
+// PluginConfig returns the plugin configuration of the
+// build, or nil, if unknown.
+func (c *PluginHelpers) PluginConfig() *types.PluginConfig {
+	return c.pc
 }
 
 func (c *PluginHelpers) Loader() ifc.Loader {
@@ -235,6 +250,14 @@ type ResMap interface {
 	// for more informed errors on not equals.
 	ErrorIfNotEqualLists(ResMap) error
 
+	// Diff returns the differences between self,
+	// taken as old, and the argument, taken as new.
+	// Resources are matched as in ErrorIfNotEqualSets,
+	// falling back to matching unique original ids
+	// so that a changed name prefix, suffix or hash
+	// doesn't look like a removal and an addition.
+	Diff(ResMap) *Diff
+
 	// Debug prints the ResMap.
 	Debug(title string)
 
@@ -530,17 +553,10 @@ func (m *resWrangler) ErrorIfNotEqualSets(other ResMap) error {
 	}
 	seen := make(map[int]bool)
 	for _, r1 := range m.rList {
-		id := r1.CurId()
-		others := m2.GetMatchingResourcesByCurrentId(id.Equals)
-		if len(others) < 0 {
-			return fmt.Errorf(
-				"id in self missing from other; id: %s", id)
-		}
-		if len(others) > 1 {
-			return fmt.Errorf(
-				"id in self matches %d in other; id: %s", len(others), id)
+		r2, err := matchById(m2.rList, r1.CurId(), GetCurrentId)
+		if err != nil {
+			return err
 		}
-		r2 := others[0]
 		if !r1.KunstructEqual(r2) {
 			return fmt.Errorf(
 				"kunstruct not equal: \n -- %s,\n -- %s\n\n--\n%#v\n------\n%#v\n",
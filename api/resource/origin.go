@@ -0,0 +1,84 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Origin retains information about where a resource was
+// loaded from, or which generator or transformer, configured
+// in which file, produced or modified it.
+type Origin struct {
+	// Path is the path to the file the resource was read from,
+	// relative to the root of the build, or to the root of Repo.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// Repo is the remote location holding Path, if any.
+	Repo string `json:"repo,omitempty" yaml:"repo,omitempty"`
+
+	// Ref is the branch, tag or commit of Repo, if any.
+	Ref string `json:"ref,omitempty" yaml:"ref,omitempty"`
+
+	// ConfiguredIn is the file holding the configuration of the
+	// generator or transformer named in ConfiguredBy.
+	ConfiguredIn string `json:"configuredIn,omitempty" yaml:"configuredIn,omitempty"`
+
+	// ConfiguredBy names the generator or transformer,
+	// e.g. ConfigMapGenerator, or apiVersion/kind/name
+	// for a plugin.
+	ConfiguredBy string `json:"configuredBy,omitempty" yaml:"configuredBy,omitempty"`
+}
+
+// Copy returns a copy of the origin.
+func (origin *Origin) Copy() *Origin {
+	if origin == nil {
+		return nil
+	}
+	o := *origin
+	return &o
+}
+
+// Append returns a copy of the origin with the given
+// path joined to its path.  A URL replaces the path,
+// since it isn't relative to anything.  A nil origin
+// stays nil.
+func (origin *Origin) Append(path string) *Origin {
+	if origin == nil {
+		return nil
+	}
+	o := origin.Copy()
+	if strings.Contains(path, "://") {
+		return &Origin{Path: path}
+	}
+	o.Path = filepath.Join(o.Path, path)
+	return o
+}
+
+// Configured returns a copy of the origin describing something
+// produced by the named generator or transformer, configured
+// in the file at the origin's path.
+func (origin *Origin) Configured(by string) *Origin {
+	if origin == nil {
+		return nil
+	}
+	return &Origin{
+		Repo:         origin.Repo,
+		Ref:          origin.Ref,
+		ConfiguredIn: origin.Path,
+		ConfiguredBy: by,
+	}
+}
+
+// String returns the origin as YAML.
+func (origin *Origin) String() string {
+	b, err := yaml.Marshal(origin)
+	if err != nil {
+		return "<" + err.Error() + ">"
+	}
+	return string(b)
+}
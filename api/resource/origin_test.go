@@ -0,0 +1,61 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "sigs.k8s.io/kustomize/api/resource"
+)
+
+func TestOriginAppend(t *testing.T) {
+	var nilOrigin *Origin
+	if nilOrigin.Append("foo") != nil {
+		t.Fatalf("expected nil origin to stay nil")
+	}
+	o := &Origin{Repo: "github.com/org/repo", Ref: "v1", Path: "base"}
+	tests := map[string]*Origin{
+		"deployment.yaml": {
+			Repo: "github.com/org/repo", Ref: "v1",
+			Path: "base/deployment.yaml"},
+		"../other/svc.yaml": {
+			Repo: "github.com/org/repo", Ref: "v1",
+			Path: "other/svc.yaml"},
+		"https://example.com/svc.yaml": {
+			Path: "https://example.com/svc.yaml"},
+	}
+	for path, expected := range tests {
+		if actual := o.Append(path); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: expected %v, got %v", path, expected, actual)
+		}
+	}
+	if o.Path != "base" {
+		t.Fatalf("append modified the original origin")
+	}
+}
+
+func TestOriginConfigured(t *testing.T) {
+	o := &Origin{Path: "overlay/kustomization.yaml"}
+	expected := `configuredBy: ConfigMapGenerator
+configuredIn: overlay/kustomization.yaml
+`
+	if actual := o.Configured("ConfigMapGenerator").String(); actual != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestDeepCopyKeepsOrigin(t *testing.T) {
+	r := testConfigMap.DeepCopy()
+	r.SetOrigin(&Origin{Path: "cm.yaml"})
+	r.AppendTransformation(&Origin{ConfiguredBy: "LabelTransformer"})
+	c := r.DeepCopy()
+	if !reflect.DeepEqual(c.GetOrigin(), r.GetOrigin()) {
+		t.Fatalf("expected origin %v, got %v", r.GetOrigin(), c.GetOrigin())
+	}
+	if !reflect.DeepEqual(c.GetTransformations(), r.GetTransformations()) {
+		t.Fatalf("expected transformations %v, got %v",
+			r.GetTransformations(), c.GetTransformations())
+	}
+}
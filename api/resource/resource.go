@@ -25,6 +25,8 @@ type Resource struct {
 	refVarNames  []string
 	namePrefixes []string
 	nameSuffixes []string
+	origin       *Origin
+	transforms   []*Origin
 }
 
 // ResCtx is an interface describing the contextual added
@@ -70,6 +72,8 @@ func (r *Resource) copyOtherFields(other *Resource) {
 	r.refVarNames = copyStringSlice(other.refVarNames)
 	r.namePrefixes = copyStringSlice(other.namePrefixes)
 	r.nameSuffixes = copyStringSlice(other.nameSuffixes)
+	r.origin = other.origin.Copy()
+	r.transforms = copyOrigins(other.transforms)
 }
 
 func (r *Resource) Equals(o *Resource) bool {
@@ -120,6 +124,17 @@ func copyStringSlice(s []string) []string {
 	return c
 }
 
+func copyOrigins(s []*Origin) []*Origin {
+	if s == nil {
+		return nil
+	}
+	c := make([]*Origin, len(s))
+	for i := range s {
+		c[i] = s[i].Copy()
+	}
+	return c
+}
+
 // Implements ResCtx AddNamePrefix
 func (r *Resource) AddNamePrefix(p string) {
 	r.namePrefixes = append(r.namePrefixes, p)
@@ -223,6 +238,31 @@ func (r *Resource) setOriginalNs(n string) *Resource {
 	return r
 }
 
+// GetOrigin returns where the resource was loaded or
+// generated from, or nil if that wasn't tracked.
+func (r *Resource) GetOrigin() *Origin {
+	return r.origin
+}
+
+// SetOrigin records where the resource was loaded or
+// generated from.
+func (r *Resource) SetOrigin(o *Origin) {
+	r.origin = o
+}
+
+// GetTransformations returns, in order of application, the
+// transformers that modified the resource, or nil if that
+// wasn't tracked.
+func (r *Resource) GetTransformations() []*Origin {
+	return r.transforms
+}
+
+// AppendTransformation records that the resource was
+// modified by the given transformer.
+func (r *Resource) AppendTransformation(o *Origin) {
+	r.transforms = append(r.transforms, o)
+}
+
 // String returns resource as JSON.
 func (r *Resource) String() string {
 	bs, err := r.MarshalJSON()
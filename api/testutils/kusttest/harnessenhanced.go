@@ -16,6 +16,7 @@ import (
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/resource"
 	valtest_test "sigs.k8s.io/kustomize/api/testutils/valtest"
+	"sigs.k8s.io/kustomize/api/types"
 )
 
 // HarnessEnhanced manages a full plugin environment for tests.
@@ -24,6 +25,7 @@ type HarnessEnhanced struct {
 	pte *pluginTestEnv
 	rf  *resmap.Factory
 	ldr ifc.Loader
+	pc  *types.PluginConfig
 	pl  *pLdr.Loader
 }
 
@@ -45,12 +47,19 @@ func MakeEnhancedHarness(t *testing.T) *HarnessEnhanced {
 		Harness: Harness{t: t, fSys: fSys},
 		pte:     pte,
 		rf:      rf,
+		pc:      pc,
 		pl:      pLdr.NewLoader(pc, rf)}
 	result.ResetLoaderRoot(filesys.Separator)
 
 	return result
 }
 
+// PluginConfig returns the plugin configuration of the
+// harness, which tests may change before loading plugins.
+func (th *HarnessEnhanced) PluginConfig() *types.PluginConfig {
+	return th.pc
+}
+
 func (th *HarnessEnhanced) Reset() {
 	th.pte.reset()
 }
@@ -84,20 +93,31 @@ func (th *HarnessEnhanced) ResetLoaderRoot(root string) {
 
 func (th *HarnessEnhanced) LoadAndRunGenerator(
 	config string) resmap.ResMap {
-	res, err := th.rf.RF().FromBytes([]byte(config))
+	rm, err := th.RunGenerator(config)
 	if err != nil {
 		th.t.Fatalf("Err: %v", err)
 	}
-	g, err := th.pl.LoadGenerator(
-		th.ldr, valtest_test.MakeFakeValidator(), res)
+	return rm
+}
+
+func (th *HarnessEnhanced) ErrorFromLoadAndRunGenerator(
+	config string) error {
+	_, err := th.RunGenerator(config)
+	return err
+}
+
+func (th *HarnessEnhanced) RunGenerator(
+	config string) (resmap.ResMap, error) {
+	res, err := th.rf.RF().FromBytes([]byte(config))
 	if err != nil {
 		th.t.Fatalf("Err: %v", err)
 	}
-	rm, err := g.Generate()
+	g, err := th.pl.LoadGenerator(
+		th.ldr, valtest_test.MakeFakeValidator(), res)
 	if err != nil {
-		th.t.Fatalf("Err: %v", err)
+		return nil, err
 	}
-	return rm
+	return g.Generate()
 }
 
 func (th *HarnessEnhanced) LoadAndRunTransformer(
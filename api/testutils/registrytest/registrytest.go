@@ -0,0 +1,113 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package registrytest_test runs a stand-in for an
+// OCI registry, serving manifests by tag, for tests.
+package registrytest_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+const (
+	manifestType = "application/vnd.oci.image.manifest.v1+json"
+	token        = "anonymous-pull-token"
+)
+
+// Registry serves the manifests pushed to it on the
+// loopback interface, so that images named for its
+// Host are resolved by plain HTTP.
+type Registry struct {
+	server *httptest.Server
+
+	// Host is the address of the registry, e.g. 127.0.0.1:39425.
+	Host string
+
+	// When true, manifests are served only with the
+	// anonymous token the registry hands out.
+	RequireToken bool
+
+	// When true, the digest of a manifest isn't
+	// reported in a header, and HEAD isn't answered.
+	OmitDigest bool
+
+	mu        sync.Mutex
+	manifests map[string][]byte
+	requests  int
+}
+
+// NewRegistry starts a registry; Close stops it.
+func NewRegistry() *Registry {
+	r := &Registry{manifests: make(map[string][]byte)}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serve))
+	r.Host = strings.TrimPrefix(r.server.URL, "http://")
+	return r
+}
+
+// Close stops the registry.
+func (r *Registry) Close() {
+	r.server.Close()
+}
+
+// Push stores a manifest for repo:tag, returning its digest.
+func (r *Registry) Push(repo, tag string) string {
+	m := []byte(fmt.Sprintf(
+		`{"schemaVersion":2,"mediaType":%q,"annotations":{"tag":"%s:%s"}}`,
+		manifestType, repo, tag))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.manifests[repo+":"+tag] = m
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(m))
+}
+
+// Requests returns the number of manifest requests served.
+func (r *Registry) Requests() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.requests
+}
+
+func (r *Registry) serve(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		fmt.Fprintf(w, `{"token":%q}`, token)
+		return
+	}
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	i := strings.LastIndex(path, "/manifests/")
+	if i < 0 || path == req.URL.Path {
+		http.NotFound(w, req)
+		return
+	}
+	if r.RequireToken && req.Header.Get("Authorization") != "Bearer "+token {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+			`Bearer realm="%s/token",service="registrytest",scope="repository:%s:pull"`,
+			r.server.URL, path[:i]))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.OmitDigest && req.Method == http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	r.mu.Lock()
+	r.requests++
+	m, ok := r.manifests[path[:i]+":"+path[i+len("/manifests/"):]]
+	r.mu.Unlock()
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	w.Header().Set("Content-Type", manifestType)
+	if !r.OmitDigest {
+		w.Header().Set("Docker-Content-Digest",
+			fmt.Sprintf("sha256:%x", sha256.Sum256(m)))
+	}
+	if req.Method == http.MethodGet {
+		w.Write(m)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sopstest_test makes keys, and files encrypted
+// by sops for them, for tests.
+package sopstest_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	_ "crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+	sopsapi "go.mozilla.org/sops/v3"
+	"go.mozilla.org/sops/v3/aes"
+	sopsage "go.mozilla.org/sops/v3/age"
+	"go.mozilla.org/sops/v3/cmd/sops/common"
+	"go.mozilla.org/sops/v3/pgp"
+	"go.mozilla.org/sops/v3/version"
+	"golang.org/x/crypto/openpgp"
+	pgpArmor "golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/packet"
+	"sigs.k8s.io/kustomize/api/internal/sops"
+)
+
+// Keys are locally generated keys to encrypt files for.
+type Keys struct {
+	t   *testing.T
+	Age *age.X25519Identity
+	PGP *openpgp.Entity
+}
+
+// MakeKeys generates an age identity and a PGP key.
+func MakeKeys(t *testing.T) *Keys {
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := openpgp.NewEntity(
+		"test", "", "test@example.com", &packet.Config{DefaultHash: crypto.SHA256})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Keys{t: t, Age: id, PGP: e}
+}
+
+// Install points the environment at a new directory
+// holding the keys, as sops would find them, returning
+// a func that restores the environment and removes the
+// directory.  With usePGP, only the PGP key is installed,
+// else only the age identity.
+func (k *Keys) Install(usePGP bool) func() {
+	dir, err := ioutil.TempDir("", "kustomize-sops-keys")
+	if err != nil {
+		k.t.Fatal(err)
+	}
+	old := make(map[string]*string)
+	for _, v := range []string{
+		sops.AgeKeyEnv, sops.AgeKeyFileEnv, sops.GnupgHomeEnv} {
+		if s, ok := os.LookupEnv(v); ok {
+			old[v] = &s
+		} else {
+			old[v] = nil
+		}
+	}
+	os.Unsetenv(sops.AgeKeyEnv)
+	ageFile := filepath.Join(dir, "keys.txt")
+	os.Setenv(sops.AgeKeyFileEnv, ageFile)
+	os.Setenv(sops.GnupgHomeEnv, dir)
+	if usePGP {
+		var b bytes.Buffer
+		if err = k.PGP.SerializePrivate(&b, nil); err != nil {
+			k.t.Fatal(err)
+		}
+		err = ioutil.WriteFile(
+			filepath.Join(dir, "secring.gpg"), b.Bytes(), 0600)
+	} else {
+		err = ioutil.WriteFile(ageFile, []byte(
+			"# created: "+time.Now().Format(time.RFC3339)+"\n"+
+				k.Age.String()+"\n"), 0600)
+	}
+	if err != nil {
+		k.t.Fatal(err)
+	}
+	return func() {
+		for v, s := range old {
+			if s == nil {
+				os.Unsetenv(v)
+			} else {
+				os.Setenv(v, *s)
+			}
+		}
+		os.RemoveAll(dir)
+	}
+}
+
+// Encrypt encrypts cleartext with sops, in the format sops
+// infers from path, for both keys, as sops would with
+// its default unencrypted suffix, _unencrypted.
+func (k *Keys) Encrypt(path string, cleartext string) []byte {
+	store := common.StoreForFormat(sops.FormatForPath(path))
+	branches, err := store.LoadPlainFile([]byte(cleartext))
+	if err != nil {
+		k.t.Fatal(err)
+	}
+	dataKey := make([]byte, 32)
+	if _, err = rand.Read(dataKey); err != nil {
+		k.t.Fatal(err)
+	}
+	ageKeys, err := sopsage.MasterKeysFromRecipients(k.Age.Recipient().String())
+	if err != nil {
+		k.t.Fatal(err)
+	}
+	if err = ageKeys[0].Encrypt(dataKey); err != nil {
+		k.t.Fatal(err)
+	}
+	// Encrypted here, as sops would look for the
+	// public key in the user's keyring.
+	pgpKey := pgp.NewMasterKeyFromFingerprint(
+		fmt.Sprintf("%X", k.PGP.PrimaryKey.Fingerprint))
+	pgpKey.SetEncryptedDataKey(k.encryptPGP(dataKey))
+	tree := sopsapi.Tree{
+		Branches: branches,
+		Metadata: sopsapi.Metadata{
+			KeyGroups:         []sopsapi.KeyGroup{{ageKeys[0], pgpKey}},
+			UnencryptedSuffix: "_unencrypted",
+			Version:           version.Version,
+		},
+	}
+	err = common.EncryptTree(common.EncryptTreeOpts{
+		Tree: &tree, Cipher: aes.NewCipher(), DataKey: dataKey})
+	if err != nil {
+		k.t.Fatal(err)
+	}
+	b, err := store.EmitEncryptedFile(tree)
+	if err != nil {
+		k.t.Fatal(err)
+	}
+	return b
+}
+
+func (k *Keys) encryptPGP(dataKey []byte) []byte {
+	var b bytes.Buffer
+	aw, err := pgpArmor.Encode(&b, "PGP MESSAGE", nil)
+	if err != nil {
+		k.t.Fatal(err)
+	}
+	w, err := openpgp.Encrypt(aw, []*openpgp.Entity{k.PGP}, nil, nil, nil)
+	if err != nil {
+		k.t.Fatal(err)
+	}
+	w.Write(dataKey)
+	w.Close()
+	aw.Close()
+	return b.Bytes()
+}
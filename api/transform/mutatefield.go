@@ -4,13 +4,15 @@
 package transform
 
 import (
-	"fmt"
-	"log"
-	"strings"
+	"sigs.k8s.io/kustomize/api/fieldpath"
 )
 
 type mutateFunc func(interface{}) (interface{}, error)
 
+// MutateField applies the given functions, in order, to the
+// value of each field at the path, as split by PathSlice.
+// A segment of the path may select list items, e.g.
+// containers[name=app]; see package fieldpath.
 func MutateField(
 	m map[string]interface{},
 	pathToField []string,
@@ -19,64 +21,19 @@ func MutateField(
 	if len(pathToField) == 0 {
 		return nil
 	}
-
-	firstPathSegment, isArray := getFirstPathSegment(pathToField)
-
-	_, found := m[firstPathSegment]
-	if !found {
-		if !createIfNotPresent || isArray {
-			return nil
-		}
-		m[firstPathSegment] = map[string]interface{}{}
-	}
-
-	if len(pathToField) == 1 {
-		var err error
-		for _, fn := range fns {
-			m[firstPathSegment], err = fn(m[firstPathSegment])
-			if err != nil {
-				return err
-			}
-		}
-		return nil
+	path, err := fieldpath.FromSegments(pathToField)
+	if err != nil {
+		return err
 	}
-
-	v := m[firstPathSegment]
-	newPathToField := pathToField[1:]
-	switch typedV := v.(type) {
-	case nil:
-		log.Printf(
-			"nil value at `%s` ignored in mutation attempt",
-			strings.Join(pathToField, "."))
-		return nil
-	case map[string]interface{}:
-		return MutateField(typedV, newPathToField, createIfNotPresent, fns...)
-	case []interface{}:
-		for i := range typedV {
-			item := typedV[i]
-			typedItem, ok := item.(map[string]interface{})
-			if !ok {
-				return fmt.Errorf("%#v is expected to be %T", item, typedItem)
+	return path.Mutate(m, createIfNotPresent,
+		func(v interface{}) (interface{}, error) {
+			var err error
+			for _, fn := range fns {
+				v, err = fn(v)
+				if err != nil {
+					return nil, err
+				}
 			}
-			err := MutateField(typedItem, newPathToField, createIfNotPresent, fns...)
-			if err != nil {
-				return err
-			}
-		}
-		return nil
-	default:
-		// If an inline could not be resolved, a field that was supposed to map or slice
-		// is still in the tree as a string, a.k.a "field: $(INLINE)"
-		// Hence we shoould not return an error and not attempt to mutate the field.
-
-		// return fmt.Errorf("%#v is not expected to be a primitive type", typedV)
-		return nil
-	}
-}
-
-func getFirstPathSegment(pathToField []string) (string, bool) {
-	if strings.HasSuffix(pathToField[0], "[]") {
-		return pathToField[0][:len(pathToField[0])-2], true
-	}
-	return pathToField[0], false
+			return v, nil
+		})
 }
@@ -155,3 +155,19 @@ func TestWithNil(t *testing.T) {
 		t.Fatalf("Unexpected error: %v", err)
 	}
 }
+
+func TestWithSelectors(t *testing.T) {
+	obj := makeTestDeployment()
+	m := &noopMutator{}
+	err := MutateField(
+		obj.Map(), []string{"spec", "template", "env[name=GOODBYE]", "value"}, false, m.mutate)
+	if err != nil {
+		t.Fatalf("unexpected mutate error: %v", err)
+	}
+	if v := getFieldValue(t, obj, "spec.template.env[name=GOODBYE].value"); v != newValue {
+		t.Fatalf("unexpected new value: %v", v)
+	}
+	if v := getFieldValue(t, obj, "spec.template.env[name=HELLO].value"); v != "hi there" {
+		t.Fatalf("unexpected value: %v", v)
+	}
+}
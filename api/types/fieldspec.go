@@ -6,8 +6,8 @@ package types
 import (
 	"fmt"
 	"sort"
-	"strings"
 
+	"sigs.k8s.io/kustomize/api/fieldpath"
 	"sigs.k8s.io/kustomize/api/resid"
 )
 
@@ -91,11 +91,6 @@ type FieldSpecConfig struct {
 	Behavior  string `json:"behavior,omitempty" yaml:"behavior,omitempty"`
 }
 
-const (
-	escapedForwardSlash  = "\\/"
-	tempSlashReplacement = "???"
-)
-
 func (fs FieldSpec) String() string {
 	return fmt.Sprintf(
 		"%s:%v:%v:%s", fs.Gvk.String(), fs.CreateIfNotPresent, fs.SkipTransformation, fs.Path)
@@ -135,19 +130,14 @@ func (fs FieldSpec) effectivelyEquals(other FieldSpecConfig) bool {
 //   []string{
 //      "metadata",
 //      "annotations",
-//      "ingress.auth-secretkubernetes.io/auth-secret"
+//      "ingress.kubernetes.io/auth-secret"
 //   }
+//
+// A slash in the brackets of a list item selector, such as
+// containers[name=a/b], doesn't separate fields; see
+// package fieldpath for the syntax of selectors.
 func (fs FieldSpec) PathSlice() []string {
-	if !strings.Contains(fs.Path, escapedForwardSlash) {
-		return strings.Split(fs.Path, "/")
-	}
-	s := strings.Replace(fs.Path, escapedForwardSlash, tempSlashReplacement, -1)
-	paths := strings.Split(s, "/")
-	var result []string
-	for _, path := range paths {
-		result = append(result, strings.Replace(path, tempSlashReplacement, "/", -1))
-	}
-	return result
+	return fieldpath.Split(fs.Path, '/')
 }
 
 type FsSlice []FieldSpecConfig
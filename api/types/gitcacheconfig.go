@@ -0,0 +1,24 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// GitCacheConfig holds the configuration of the on-disk
+// cache of clones of remote git bases.
+type GitCacheConfig struct {
+	// AbsCacheDir is the directory holding the cache, e.g.
+	//   $XDG_CACHE_HOME/kustomize/git
+	// Clones are kept below it, per host, repository
+	// and ref.
+	AbsCacheDir string
+
+	// Refresh, when true, fetches again repositories whose
+	// ref isn't a commit hash, e.g. a branch, since the
+	// commit the ref names may have changed since it was
+	// cached.
+	Refresh bool
+
+	// Offline, when true, fails rather than fetching a
+	// repository that isn't cached.
+	Offline bool
+}
@@ -0,0 +1,20 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// How to clone the remote git repos holding bases.
+//
+//go:generate stringer -type=GitCloner
+type GitCloner int
+
+const (
+	GitClonerUnknown GitCloner = iota
+
+	// Run the git program found on the path.
+	GitClonerExec
+
+	// Use a git implementation in Go, so that no
+	// git program need be installed.
+	GitClonerGo
+)
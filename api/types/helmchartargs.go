@@ -0,0 +1,30 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// HelmChartArgs contains the metadata of how to inflate a helm chart.
+type HelmChartArgs struct {
+	// Chart is the path, relative to the kustomization root,
+	// of a chart directory or of a packaged chart (.tgz).
+	Chart string `json:"chart,omitempty" yaml:"chart,omitempty"`
+
+	// ReleaseName is the name given to the release when
+	// rendering; defaults to "release-name", as in helm.
+	ReleaseName string `json:"releaseName,omitempty" yaml:"releaseName,omitempty"`
+
+	// ReleaseNamespace is the namespace of the release.
+	ReleaseNamespace string `json:"releaseNamespace,omitempty" yaml:"releaseNamespace,omitempty"`
+
+	// ValuesFile is the path, relative to the kustomization
+	// root, of a file of values overriding those of the chart.
+	ValuesFile string `json:"valuesFile,omitempty" yaml:"valuesFile,omitempty"`
+
+	// ValuesInline holds values overriding those of the
+	// chart and those of the values file.
+	ValuesInline map[string]interface{} `json:"valuesInline,omitempty" yaml:"valuesInline,omitempty"`
+
+	// IncludeCRDs asks that the CRDs of the chart be
+	// rendered along with its templates.
+	IncludeCRDs bool `json:"includeCRDs,omitempty" yaml:"includeCRDs,omitempty"`
+}
@@ -0,0 +1,27 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// Whether, and how, a build pins the container
+// images of its output by digest.
+//
+//go:generate stringer -type=ImagePinning
+type ImagePinning int
+
+const (
+	ImagePinningUnknown ImagePinning = iota
+
+	// Images are left as they are.
+	ImagePinningNone
+
+	// The tag of each image is replaced by its digest,
+	// as recorded in the lock file next to the
+	// kustomization file, else as resolved with the
+	// registry of the image, and then recorded.
+	ImagePinningResolve
+
+	// The build fails if any image isn't pinned
+	// by digest.
+	ImagePinningCheck
+)
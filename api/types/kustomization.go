@@ -6,6 +6,8 @@ package types
 const (
 	KustomizationVersion = "kustomize.config.k8s.io/v1beta1"
 	KustomizationKind    = "Kustomization"
+	ComponentVersion     = "kustomize.config.k8s.io/v1alpha1"
+	ComponentKind        = "Component"
 )
 
 // Kustomization holds the information needed to generate customized k8s api resources.
@@ -67,6 +69,12 @@ type Kustomization struct {
 	// value of the specified field has been determined.
 	Vars []Var `json:"vars,omitempty" yaml:"vars,omitempty"`
 
+	// Replacements copy a value, taken from a field of one
+	// resource or given literally, into fields of other
+	// resources.  Unlike vars, any field of any type can be
+	// a source or a target, and targets need no placeholder.
+	Replacements []Replacement `json:"replacements,omitempty" yaml:"replacements,omitempty"`
+
 	//
 	// Operands - what kustomize operates on.
 	//
@@ -76,12 +84,27 @@ type Kustomization struct {
 	// via relative paths, absolute paths, or URLs.
 	Resources []string `json:"resources,omitempty" yaml:"resources,omitempty"`
 
+	// Components specifies relative paths to, or URLs of,
+	// kustomizations of kind Component.  A component has no
+	// resources of its own to offer; instead, its generators,
+	// patches and transformers are applied, in list order,
+	// to the resources accumulated by the kustomization
+	// that includes it.
+	Components []string `json:"components,omitempty" yaml:"components,omitempty"`
+
 	// Crds specifies relative paths to Custom Resource Definition files.
 	// This allows custom resources to be recognized as operands, making
 	// it possible to add them to the Resources list.
+	// Their schemas let strategic merge patches merge the items of
+	// lists in custom resources by key, per x-kubernetes-patch-merge-key,
+	// or x-kubernetes-list-type: map and x-kubernetes-list-map-keys.
 	// CRDs themselves are not modified.
 	Crds []string `json:"crds,omitempty" yaml:"crds,omitempty"`
 
+	// OpenAPI specifies a file of OpenAPI definitions, whose
+	// schemas of custom resources are used as those of Crds are.
+	OpenAPI *OpenAPI `json:"openapi,omitempty" yaml:"openapi,omitempty"`
+
 	// Deprecated.
 	// Anything that would have been specified here should
 	// be specified in the Resources field instead.
@@ -105,6 +128,12 @@ type Kustomization struct {
 	// the map will have a suffix hash generated from its contents.
 	SecretGenerator []SecretArgs `json:"secretGenerator,omitempty" yaml:"secretGenerator,omitempty"`
 
+	// HelmCharts is a list of helm charts to inflate (one
+	// chart per list item) using the helm program.
+	// The resulting resources are normal operands, subject
+	// to name prefixing, patching, etc.
+	HelmCharts []HelmChartArgs `json:"helmCharts,omitempty" yaml:"helmCharts,omitempty"`
+
 	// GeneratorOptions modify behavior of all ConfigMap and Secret generators.
 	GeneratorOptions *GeneratorOptions `json:"generatorOptions,omitempty" yaml:"generatorOptions,omitempty"`
 
@@ -128,7 +157,11 @@ type Kustomization struct {
 // fields.
 func (k *Kustomization) FixKustomizationPostUnmarshalling() {
 	if k.APIVersion == "" {
-		k.APIVersion = KustomizationVersion
+		if k.Kind == ComponentKind {
+			k.APIVersion = ComponentVersion
+		} else {
+			k.APIVersion = KustomizationVersion
+		}
 	}
 	if k.Kind == "" {
 		k.Kind = KustomizationKind
@@ -139,11 +172,20 @@ func (k *Kustomization) FixKustomizationPostUnmarshalling() {
 
 func (k *Kustomization) EnforceFields() []string {
 	var errs []string
-	if k.APIVersion != "" && k.APIVersion != KustomizationVersion {
-		errs = append(errs, "apiVersion should be "+KustomizationVersion)
+	if k.APIVersion != "" && k.APIVersion != k.ExpectedVersion() {
+		errs = append(errs, "apiVersion should be "+k.ExpectedVersion())
 	}
-	if k.Kind != "" && k.Kind != KustomizationKind {
-		errs = append(errs, "kind should be "+KustomizationKind)
+	if k.Kind != "" && k.Kind != KustomizationKind && k.Kind != ComponentKind {
+		errs = append(errs, "kind should be "+KustomizationKind+" or "+ComponentKind)
 	}
 	return errs
 }
+
+// ExpectedVersion returns the apiVersion that goes with
+// the kind of the kustomization.
+func (k *Kustomization) ExpectedVersion() string {
+	if k.Kind == ComponentKind {
+		return ComponentVersion
+	}
+	return KustomizationVersion
+}
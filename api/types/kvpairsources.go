@@ -28,4 +28,20 @@ type KvPairSources struct {
 	// or npm ".env" file or a ".ini" file
 	// (wikipedia.org/wiki/INI_file)
 	EnvSources []string `json:"envs,omitempty" yaml:"envs,omitempty"`
+
+	// EncryptedFileSources is like FileSources, but each
+	// file is encrypted by sops (github.com/mozilla/sops)
+	// with age or PGP keys, and the value is the file's
+	// cleartext.  The format of the file (YAML, JSON,
+	// dotenv or binary) follows its extension, as in sops.
+	// Files are decrypted in memory, with the keys sops
+	// would use, e.g. those in $SOPS_AGE_KEY_FILE.
+	// Only secret generators allow encrypted sources.
+	EncryptedFileSources []string `json:"encryptedFiles,omitempty" yaml:"encryptedFiles,omitempty"`
+
+	// EncryptedEnvSources is like EnvSources, but each
+	// file is encrypted by sops, as for EncryptedFileSources.
+	// A dotenv (.env) file holds one key=value pair per
+	// line; a YAML or JSON file, a mapping of keys to values.
+	EncryptedEnvSources []string `json:"encryptedEnvs,omitempty" yaml:"encryptedEnvs,omitempty"`
 }
@@ -0,0 +1,10 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// OpenAPI names a file of OpenAPI definitions, such as a
+// cluster serves at /openapi/v2, in JSON or YAML.
+type OpenAPI struct {
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
@@ -3,6 +3,8 @@
 
 package types
 
+import "time"
+
 // PluginConfig holds plugin configuration.
 type PluginConfig struct {
 	// AbsPluginHome is the home of kustomize plugins.
@@ -28,4 +30,39 @@ type PluginConfig struct {
 	// PluginRestrictions defines the plugin restriction state.
 	// See type for more information.
 	PluginRestrictions PluginRestrictions
+
+	// Policy lists the plugins allowed to run when the
+	// restrictions are PluginRestrictionsPolicy.
+	Policy *PluginPolicy
+
+	// HelmCommand is the helm program that the builtin
+	// HelmChartInflationGenerator runs; defaults to "helm",
+	// found on the PATH.  It's chosen by whoever runs the
+	// build, never by a kustomization.
+	HelmCommand string
+
+	// How exec plugins run.
+	// See type for more information.
+	Exec ExecPluginOptions
+}
+
+// ExecPluginOptions controls the processes running exec
+// plugins.  The zero value runs them as ever, with no
+// timeout, and kustomize's whole environment.
+type ExecPluginOptions struct {
+	// Timeout, if not zero, bounds each run of an exec
+	// plugin, after which it, and all processes it started,
+	// are terminated, failing the build.
+	Timeout time.Duration
+
+	// Env, if not nil, names the only variables of the
+	// environment of kustomize passed on to exec plugins,
+	// e.g. PATH and HOME.  They get the variables holding
+	// their configuration regardless.
+	Env []string
+
+	// TempWorkDir, when true, runs each exec plugin in a
+	// new, empty, directory, removed after it exits, rather
+	// than in the root of the kustomization configuring it.
+	TempWorkDir bool
 }
@@ -0,0 +1,96 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// PluginPolicy lists the non-builtin plugins that may run,
+// when the plugin restrictions are PluginRestrictionsPolicy.
+// It's read from a file like
+//   denyRemote: true
+//   plugins:
+//   - apiVersion: someteam.example.com/v1
+//     kind: SedTransformer
+//     sha256: 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae
+//     timeout: 30s
+type PluginPolicy struct {
+	// Plugins that may run; any other fails the build.
+	Plugins []AllowedPlugin `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+
+	// DenyRemote, when true, fails the build if a remote
+	// base, or a kustomization within a remote base,
+	// configures a non-builtin plugin, even if allowed.
+	DenyRemote bool `json:"denyRemote,omitempty" yaml:"denyRemote,omitempty"`
+}
+
+// AllowedPlugin is a non-builtin plugin that may run.
+type AllowedPlugin struct {
+	// The apiVersion and kind of the plugin's configuration.
+	APIVersion string `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string `json:"kind" yaml:"kind"`
+
+	// Sha256 is the hex encoded SHA-256 checksum of the
+	// plugin's executable, or of its .so file for a Go
+	// plugin.  A plugin whose file has any other checksum
+	// doesn't run.
+	Sha256 string `json:"sha256" yaml:"sha256"`
+
+	// Timeout, if set, e.g. 30s, is how long the plugin, if an
+	// exec plugin, may run, in place of ExecPluginOptions.Timeout.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// ExecTimeout returns the timeout of the plugin, or
+// the given default if it has none.
+func (a *AllowedPlugin) ExecTimeout(d time.Duration) (time.Duration, error) {
+	if a.Timeout == "" {
+		return d, nil
+	}
+	t, err := time.ParseDuration(a.Timeout)
+	if err != nil || t < 0 {
+		return 0, fmt.Errorf(
+			"plugin %s/%s: timeout %q must be a duration, e.g. 30s",
+			a.APIVersion, a.Kind, a.Timeout)
+	}
+	return t, nil
+}
+
+// Allowed returns the allowed plugin configured by objects
+// of the given apiVersion and kind, or nil.
+func (p *PluginPolicy) Allowed(apiVersion, kind string) *AllowedPlugin {
+	for i := range p.Plugins {
+		if p.Plugins[i].APIVersion == apiVersion && p.Plugins[i].Kind == kind {
+			return &p.Plugins[i]
+		}
+	}
+	return nil
+}
+
+type errPluginNotAllowed struct {
+	name   string
+	reason string
+}
+
+func (e *errPluginNotAllowed) Error() string {
+	return fmt.Sprintf(
+		"plugin policy forbids plugin '%s': %s", e.name, e.reason)
+}
+
+func NewErrPluginNotAllowed(n, reason string) *errPluginNotAllowed {
+	return &errPluginNotAllowed{name: n, reason: reason}
+}
+
+func IsErrPluginNotAllowed(err error) bool {
+	_, ok := err.(*errPluginNotAllowed)
+	if ok {
+		return true
+	}
+	_, ok = errors.Cause(err).(*errPluginNotAllowed)
+	return ok
+}
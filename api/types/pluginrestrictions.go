@@ -25,4 +25,8 @@ const (
 
 	// No restrictions, do whatever you want.
 	PluginRestrictionsNone
+
+	// Only the non-builtin plugins allowed by the
+	// PluginPolicy of the PluginConfig may run.
+	PluginRestrictionsPolicy
 )
@@ -11,11 +11,12 @@ func _() {
 	_ = x[PluginRestrictionsUnknown-0]
 	_ = x[PluginRestrictionsBuiltinsOnly-1]
 	_ = x[PluginRestrictionsNone-2]
+	_ = x[PluginRestrictionsPolicy-3]
 }
 
-const _PluginRestrictionsName = "PluginRestrictionsUnknownPluginRestrictionsBuiltinsOnlyPluginRestrictionsNone"
+const _PluginRestrictionsName = "PluginRestrictionsUnknownPluginRestrictionsBuiltinsOnlyPluginRestrictionsNonePluginRestrictionsPolicy"
 
-var _PluginRestrictionsIndex = [...]uint8{0, 25, 55, 77}
+var _PluginRestrictionsIndex = [...]uint8{0, 25, 55, 77, 101}
 
 func (i PluginRestrictions) String() string {
 	if i < 0 || i >= PluginRestrictions(len(_PluginRestrictionsIndex)-1) {
@@ -7,7 +7,20 @@ package types
 // where it is from and where it is to.
 type Replacement struct {
 	Source *ReplSource `json:"source" yaml:"source"`
-	Target *ReplTarget `json:"target" yaml:"target"`
+
+	// Target is a single target, kept for compatibility;
+	// the value is copied to it and to all Targets.
+	Target *ReplTarget `json:"target,omitempty" yaml:"target,omitempty"`
+
+	Targets []*ReplTarget `json:"targets,omitempty" yaml:"targets,omitempty"`
+}
+
+// AllTargets returns Target, if any, followed by Targets.
+func (r *Replacement) AllTargets() []*ReplTarget {
+	if r.Target == nil {
+		return r.Targets
+	}
+	return append([]*ReplTarget{r.Target}, r.Targets...)
 }
 
 // ReplSource defines where a substitution is from
@@ -15,13 +28,26 @@ type Replacement struct {
 //  - from a field of one resource
 //  - from a string
 type ReplSource struct {
-	ObjRef   *Target `json:"objref,omitempty" yaml:"objref,omitempty"`
-	FieldRef string  `json:"fieldref,omitempty" yaml:"fiedldref,omitempty"`
-	Value    string  `json:"value,omitempty" yaml:"value,omitempty"`
+	ObjRef   *Target       `json:"objref,omitempty" yaml:"objref,omitempty"`
+	FieldRef string        `json:"fieldref,omitempty" yaml:"fieldref,omitempty"`
+	Value    string        `json:"value,omitempty" yaml:"value,omitempty"`
+	Options  *FieldOptions `json:"options,omitempty" yaml:"options,omitempty"`
 }
 
 // ReplTarget defines where a substitution is to.
 type ReplTarget struct {
-	ObjRef    *Selector `json:"objref,omitempty" yaml:"objref,omitempty"`
-	FieldRefs []string  `json:"fieldrefs,omitempty" yaml:"fieldrefs,omitempty"`
+	ObjRef    *Selector     `json:"objref,omitempty" yaml:"objref,omitempty"`
+	FieldRefs []string      `json:"fieldrefs,omitempty" yaml:"fieldrefs,omitempty"`
+	Options   *FieldOptions `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// FieldOptions refine how a replacement reads or writes
+// a field.  If Delimiter is set, the field is a string
+// split by Delimiter, and only the part at Index is
+// read or written.  If Create is set, a missing target
+// field is created rather than reported as an error.
+type FieldOptions struct {
+	Delimiter string `json:"delimiter,omitempty" yaml:"delimiter,omitempty"`
+	Index     int    `json:"index,omitempty" yaml:"index,omitempty"`
+	Create    bool   `json:"create,omitempty" yaml:"create,omitempty"`
 }
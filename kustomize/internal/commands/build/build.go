@@ -8,6 +8,8 @@ import (
 	"log"
 	"path/filepath"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -16,7 +18,7 @@ import (
 	"sigs.k8s.io/kustomize/api/krusty"
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/resource"
-	"sigs.k8s.io/yaml"
+	"sigs.k8s.io/kustomize/api/types"
 )
 
 // Options contain the options for running a build
@@ -24,6 +26,25 @@ type Options struct {
 	kustomizationPath string
 	outputPath        string
 	outOrder          reorderOutput
+	outFormat         outputFormat
+	outputLayout      string
+	layout            *template.Template
+	selectors         []types.Selector
+	exclusions        []types.Selector
+	addOrigins        bool
+	concurrency       int
+	gitCache          bool
+	refreshGitCache   bool
+	offline           bool
+	validateOutput    bool
+	watch             bool
+	watchDiff         bool
+	watchDebounce     time.Duration
+
+	// For tests of watching.
+	watchPollInterval time.Duration
+	watchErrOut       io.Writer
+	stopWatch         <-chan struct{}
 }
 
 // NewOptions creates a Options object
@@ -49,6 +70,17 @@ with a kustomization.yaml file, e.g.
 
 The URL should be formulated as described at
 https://github.com/hashicorp/go-getter#url-format
+
+To build again whenever a file read by the build changes,
+or a file of a local helm chart, printing what changed in
+the output, run
+
+  kustomize build someDir --watch --watch_diff
+
+To output only some of the resources built, e.g. the
+Deployments labelled app=web, run
+
+  kustomize build someDir --select kind=Deployment,labelSelector=app=web
 `
 
 // NewCmdBuild creates a new build command.
@@ -73,9 +105,65 @@ func NewCmdBuild(out io.Writer) *cobra.Command {
 		&o.outputPath,
 		"output", "o", "",
 		"If specified, write the build output to this path.")
+	cmd.Flags().StringVar(
+		&o.outputLayout,
+		"output_layout", "",
+		"If specified, a template of the paths of the files, in the "+
+			"directory given by --output, to write each resource to, "+
+			"e.g. '{{.Namespace}}/{{.Kind | lower}}/{{.Name}}.yaml'. "+
+			"The fields are Group, Version, Kind, Namespace and Name.")
+	cmd.Flags().BoolVar(
+		&o.addOrigins,
+		"add_origin_annotations", false,
+		"If true, annotate each resource with the file or generator "+
+			"it came from, and the transformers that modified it.")
+	cmd.Flags().IntVar(
+		&o.concurrency,
+		"concurrency", 1,
+		"The maximum number of resources entries, e.g. remote bases, "+
+			"to fetch and build at once. Output order is unaffected.")
+	cmd.Flags().BoolVar(
+		&o.gitCache,
+		"git_cache", false,
+		"If true, keep clones of remote git bases in "+
+			konfig.DefaultAbsGitCacheDir()+", and reuse them.")
+	cmd.Flags().BoolVar(
+		&o.refreshGitCache,
+		"refresh_git_cache", false,
+		"If true, use the git cache, but fetch again remote bases "+
+			"whose ref isn't a commit hash, e.g. a branch.")
+	cmd.Flags().BoolVar(
+		&o.offline,
+		"offline", false,
+		"If true, use the git cache, and fail rather than fetch "+
+			"remote bases that aren't in it.")
+	cmd.Flags().BoolVar(
+		&o.validateOutput,
+		"validate_output", false,
+		"If true, check the output against the OpenAPI schema of "+
+			"Kubernetes, and of the CRDs named in kustomizations.")
+	cmd.Flags().BoolVar(
+		&o.watch,
+		"watch", false,
+		"If true, keep running, and build again whenever "+
+			"a file read by the last build changes.")
+	cmd.Flags().BoolVar(
+		&o.watchDiff,
+		"watch_diff", false,
+		"If true, with --watch, print the difference between "+
+			"the output of each build and that of the last.")
+	cmd.Flags().DurationVar(
+		&o.watchDebounce,
+		"watch_debounce", 300*time.Millisecond,
+		"With --watch, how long files must stay unchanged "+
+			"before building again.")
 	addFlagLoadRestrictor(cmd.Flags())
+	addFlagGitCloner(cmd.Flags())
+	addFlagImagePinning(cmd.Flags())
 	addFlagEnablePlugins(cmd.Flags())
 	addFlagReorderOutput(cmd.Flags())
+	addFlagOutputFormat(cmd.Flags())
+	addFlagSelect(cmd.Flags())
 	cmd.AddCommand(NewCmdBuildPrune(out))
 	return cmd
 }
@@ -96,17 +184,70 @@ func (o *Options) Validate(args []string) (err error) {
 	if err != nil {
 		return err
 	}
+	err = validateFlagGitCloner()
+	if err != nil {
+		return err
+	}
+	err = validateFlagImagePinning()
+	if err != nil {
+		return err
+	}
+	if o.offline && o.refreshGitCache {
+		return errors.New(
+			"--offline and --refresh_git_cache can't both be set")
+	}
+	if o.outputLayout != "" {
+		if o.outputPath == "" {
+			return errors.New("--output_layout requires --output")
+		}
+		o.layout, err = parseLayout(o.outputLayout)
+		if err != nil {
+			return errors.Wrap(err, "--output_layout")
+		}
+	}
+	if o.watchDiff && !o.watch {
+		return errors.New("--watch_diff requires --watch")
+	}
+	if o.watchDiff && o.outputPath != "" {
+		return errors.New("--watch_diff and --output can't both be set")
+	}
+	o.selectors, o.exclusions, err = validateFlagSelect()
+	if err != nil {
+		return err
+	}
+	o.outFormat, err = validateFlagOutputFormat()
+	if err != nil {
+		return err
+	}
 	o.outOrder, err = validateFlagReorderOutput()
 	return
 }
 
 func (o *Options) makeOptions() *krusty.Options {
 	opts := &krusty.Options{
-		RerorderTransformer: o.outOrder.String(),
-		LoadRestrictions:    getFlagLoadRestrictorValue(),
-		DoPrune:             false,
+		RerorderTransformer:  o.outOrder.String(),
+		LoadRestrictions:     getFlagLoadRestrictorValue(),
+		DoPrune:              false,
+		AddOriginAnnotations: o.addOrigins,
+		Concurrency:          o.concurrency,
+		GitCloner:            getFlagGitClonerValue(),
+		ValidateOutput:       o.validateOutput,
+		ImagePinning:         getFlagImagePinningValue(),
+		Select:               o.selectors,
+		Exclude:              o.exclusions,
+	}
+	if o.gitCache || o.refreshGitCache || o.offline {
+		opts.GitCache = konfig.DefaultGitCacheConfig()
+		opts.GitCache.Refresh = o.refreshGitCache
+		opts.GitCache.Offline = o.offline
 	}
-	if isFlagEnablePluginsSet() {
+	if policy := getFlagPluginPolicyValue(); policy != "" {
+		c, err := konfig.PolicyPluginConfig(filesys.MakeFsOnDisk(), policy)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts.PluginConfig = c
+	} else if isFlagEnablePluginsSet() {
 		c, err := konfig.EnabledPluginConfig()
 		if err != nil {
 			log.Fatal(err)
@@ -115,10 +256,15 @@ func (o *Options) makeOptions() *krusty.Options {
 	} else {
 		opts.PluginConfig = konfig.DisabledPluginConfig()
 	}
+	opts.PluginConfig.Exec = getFlagExecPluginOptions()
+	opts.PluginConfig.HelmCommand = getFlagHelmCommandValue()
 	return opts
 }
 
 func (o *Options) RunBuild(out io.Writer) error {
+	if o.watch {
+		return o.runWatch(out)
+	}
 	fSys := filesys.MakeFsOnDisk()
 	k := krusty.MakeKustomizer(fSys, o.makeOptions())
 	m, err := k.Run(o.kustomizationPath)
@@ -142,10 +288,13 @@ func (o *Options) RunBuildPrune(out io.Writer) error {
 
 func (o *Options) emitResources(
 	out io.Writer, fSys filesys.FileSystem, m resmap.ResMap) error {
+	if o.layout != nil {
+		return writeLayout(fSys, o.outputPath, o.layout, o.outFormat, m)
+	}
 	if o.outputPath != "" && fSys.IsDir(o.outputPath) {
-		return writeIndividualFiles(fSys, o.outputPath, m)
+		return writeIndividualFiles(fSys, o.outputPath, o.outFormat, m)
 	}
-	res, err := m.AsYaml()
+	res, err := o.outFormat.encode(m.Resources())
 	if err != nil {
 		return err
 	}
@@ -175,22 +324,24 @@ func NewCmdBuildPrune(out io.Writer) *cobra.Command {
 }
 
 func writeIndividualFiles(
-	fSys filesys.FileSystem, folderPath string, m resmap.ResMap) error {
+	fSys filesys.FileSystem, folderPath string,
+	format outputFormat, m resmap.ResMap) error {
 	byNamespace := m.GroupedByCurrentNamespace()
 	for namespace, resList := range byNamespace {
 		for _, res := range resList {
-			fName := fileName(res)
+			fName := fileName(res, format)
 			if len(byNamespace) > 1 {
 				fName = strings.ToLower(namespace) + "_" + fName
 			}
-			err := writeFile(fSys, folderPath, fName, res)
+			err := writeFile(fSys, folderPath, fName, format, res)
 			if err != nil {
 				return err
 			}
 		}
 	}
 	for _, res := range m.NonNamespaceable() {
-		err := writeFile(fSys, folderPath, fileName(res), res)
+		err := writeFile(
+			fSys, folderPath, fileName(res, format), format, res)
 		if err != nil {
 			return err
 		}
@@ -198,14 +349,15 @@ func writeIndividualFiles(
 	return nil
 }
 
-func fileName(res *resource.Resource) string {
+func fileName(res *resource.Resource, format outputFormat) string {
 	return strings.ToLower(res.GetGvk().String()) +
-		"_" + strings.ToLower(res.GetName()) + ".yaml"
+		"_" + strings.ToLower(res.GetName()) + format.extension()
 }
 
 func writeFile(
-	fSys filesys.FileSystem, path, fName string, res *resource.Resource) error {
-	out, err := yaml.Marshal(res.Map())
+	fSys filesys.FileSystem, path, fName string,
+	format outputFormat, res *resource.Resource) error {
+	out, err := format.encode([]*resource.Resource{res})
 	if err != nil {
 		return err
 	}
@@ -4,10 +4,14 @@
 package build
 
 import (
+	"strconv"
 	"testing"
 
 	"sigs.k8s.io/kustomize/api/filesys"
 	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/types"
 )
 
 func TestNewOptionsToSilenceCodeInspectionError(t *testing.T) {
@@ -52,3 +56,168 @@ func TestBuildValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildConcurrencyDefault(t *testing.T) {
+	// As in krusty, so that building from the command line
+	// doesn't read remote bases at once unless asked to.
+	expected := strconv.Itoa(krusty.MakeDefaultOptions().Concurrency)
+	d := NewCmdBuild(nil).Flags().Lookup("concurrency").DefValue
+	if d != expected {
+		t.Fatalf("expected --concurrency to default to %s, got %s", expected, d)
+	}
+}
+
+func TestBuildValidateGitCacheFlags(t *testing.T) {
+	opts := Options{offline: true, refreshGitCache: true}
+	err := opts.Validate([]string{})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	opts.refreshGitCache = false
+	err = opts.Validate([]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c := opts.makeOptions().GitCache; c == nil || !c.Offline {
+		t.Fatalf("expected an offline git cache, got %v", c)
+	}
+}
+
+func TestBuildValidateGitClonerFlag(t *testing.T) {
+	defer func(v string) { flagGcValue = v }(flagGcValue)
+	var opts Options
+	flagGcValue = "svn"
+	if err := opts.Validate([]string{}); err == nil {
+		t.Fatalf("expected an error")
+	}
+	flagGcValue = "go"
+	if err := opts.Validate([]string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gc := opts.makeOptions().GitCloner; gc != types.GitClonerGo {
+		t.Fatalf("expected %v, got %v", types.GitClonerGo, gc)
+	}
+}
+
+func TestBuildValidateImagePinningFlag(t *testing.T) {
+	defer func(v string) { flagIpValue = v }(flagIpValue)
+	var opts Options
+	flagIpValue = "always"
+	if err := opts.Validate([]string{}); err == nil {
+		t.Fatalf("expected an error")
+	}
+	flagIpValue = "check"
+	if err := opts.Validate([]string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ip := opts.makeOptions().ImagePinning; ip != types.ImagePinningCheck {
+		t.Fatalf("expected %v, got %v", types.ImagePinningCheck, ip)
+	}
+}
+
+func TestBuildValidateOutputFlags(t *testing.T) {
+	defer func(v string) { flagOutputFormatValue = v }(flagOutputFormatValue)
+	flagOutputFormatValue = "xml"
+	opts := Options{}
+	if err := opts.Validate([]string{}); err == nil {
+		t.Fatalf("expected an error")
+	}
+	flagOutputFormatValue = "resourcelist"
+	if err := opts.Validate([]string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.outFormat != resourceListFormat {
+		t.Fatalf("expected %v, got %v", resourceListFormat, opts.outFormat)
+	}
+	opts = Options{outputLayout: "{{.Name}}.yaml"}
+	if err := opts.Validate([]string{}); err == nil {
+		t.Fatalf("expected an error for a layout without an output directory")
+	}
+	opts = Options{outputPath: "out", outputLayout: "{{.Name"}
+	if err := opts.Validate([]string{}); err == nil {
+		t.Fatalf("expected an error for a bad layout")
+	}
+}
+
+func TestBuildValidateWatchFlags(t *testing.T) {
+	opts := Options{watchDiff: true}
+	if err := opts.Validate([]string{}); err == nil {
+		t.Fatalf("expected an error for --watch_diff without --watch")
+	}
+	opts = Options{watch: true, watchDiff: true, outputPath: "out"}
+	if err := opts.Validate([]string{}); err == nil {
+		t.Fatalf("expected an error for --watch_diff with --output")
+	}
+	opts = Options{watch: true, watchDiff: true}
+	if err := opts.Validate([]string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseSelector(t *testing.T) {
+	testCases := map[string]struct {
+		expected types.Selector
+		err      bool
+	}{
+		"kind=Deployment": {
+			expected: types.Selector{Gvk: resid.Gvk{Kind: "Deployment"}},
+		},
+		"group=apps,version=v1,name=web-.*,namespace=prod": {
+			expected: types.Selector{
+				Gvk:       resid.Gvk{Group: "apps", Version: "v1"},
+				Name:      "web-.*",
+				Namespace: "prod",
+			},
+		},
+		"labelSelector=app=web,tier in (a,b),kind=Service": {
+			expected: types.Selector{
+				Gvk:           resid.Gvk{Kind: "Service"},
+				LabelSelector: "app=web,tier in (a,b)",
+			},
+		},
+		"annotationSelector=owner": {
+			expected: types.Selector{AnnotationSelector: "owner"},
+		},
+		"Deployment":          {err: true},
+		"kind=A,kind=B":       {err: true},
+		"name=web,tier=front": {err: true},
+		"name=(":              {err: true},
+		"":                    {err: true},
+	}
+	for value, tc := range testCases {
+		s, err := parseSelector(value)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%q: expected an error", value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", value, err)
+			continue
+		}
+		if s != tc.expected {
+			t.Errorf("%q: expected %v, got %v", value, tc.expected, s)
+		}
+	}
+}
+
+func TestBuildValidateSelectFlags(t *testing.T) {
+	defer func(s, e []string) {
+		flagSelectValue, flagExcludeValue = s, e
+	}(flagSelectValue, flagExcludeValue)
+	flagSelectValue = []string{"kind=Deployment", "kind=Service"}
+	flagExcludeValue = []string{"name=db"}
+	var opts Options
+	if err := opts.Validate([]string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	k := opts.makeOptions()
+	if len(k.Select) != 2 || len(k.Exclude) != 1 || k.Exclude[0].Name != "db" {
+		t.Fatalf("unexpected selectors %v, exclusions %v", k.Select, k.Exclude)
+	}
+	flagExcludeValue = []string{"nom=db"}
+	if err := opts.Validate([]string{}); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
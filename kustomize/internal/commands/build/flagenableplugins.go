@@ -5,25 +5,77 @@ package build
 
 import (
 	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/types"
 )
 
 const (
 	flagEnablePluginsName = "enable_alpha_plugins"
 	flagEnablePluginsHelp = `enable plugins, an alpha feature.
 See https://github.com/kubernetes-sigs/kustomize/blob/master/docs/plugins/README.md
+`
+	flagPluginPolicyName = "plugin_policy"
+	flagPluginPolicyHelp = `path to a plugin policy file, enabling only the
+plugins it lists, pinned by the sha256 of their files.
+`
+	flagHelmCommandName = "helm_command"
+	flagHelmCommandHelp = `the helm program that inflates the charts of
+helmCharts fields; defaults to helm, found on the PATH.
+`
+	flagExecPluginTimeoutName = "exec_plugin_timeout"
+	flagExecPluginTimeoutHelp = `if not zero, how long an exec plugin may run
+before it, and all it started, are terminated; the
+plugin policy may set a timeout for each plugin.
+`
+	flagExecPluginEnvName = "exec_plugin_env"
+	flagExecPluginEnvHelp = `if set, the only environment variables passed
+on to exec plugins, e.g. PATH,HOME.
+`
+	flagExecPluginTempDirName = "exec_plugin_temp_dir"
+	flagExecPluginTempDirHelp = `if true, run exec plugins in a new, empty,
+directory, rather than in the kustomization root.
 `
 )
 
 var (
 	flagPluginsEnabledValue = false
+	flagPluginPolicyValue   = ""
+	flagExecPluginOptions   types.ExecPluginOptions
+	flagHelmCommandValue    = ""
 )
 
 func addFlagEnablePlugins(set *pflag.FlagSet) {
 	set.BoolVar(
 		&flagPluginsEnabledValue, flagEnablePluginsName,
 		false, flagEnablePluginsHelp)
+	set.StringVar(
+		&flagPluginPolicyValue, flagPluginPolicyName,
+		"", flagPluginPolicyHelp)
+	set.StringVar(
+		&flagHelmCommandValue, flagHelmCommandName,
+		"", flagHelmCommandHelp)
+	set.DurationVar(
+		&flagExecPluginOptions.Timeout, flagExecPluginTimeoutName,
+		0, flagExecPluginTimeoutHelp)
+	set.StringSliceVar(
+		&flagExecPluginOptions.Env, flagExecPluginEnvName,
+		nil, flagExecPluginEnvHelp)
+	set.BoolVar(
+		&flagExecPluginOptions.TempWorkDir, flagExecPluginTempDirName,
+		false, flagExecPluginTempDirHelp)
 }
 
 func isFlagEnablePluginsSet() bool {
 	return flagPluginsEnabledValue
 }
+
+func getFlagPluginPolicyValue() string {
+	return flagPluginPolicyValue
+}
+
+func getFlagExecPluginOptions() types.ExecPluginOptions {
+	return flagExecPluginOptions
+}
+
+func getFlagHelmCommandValue() string {
+	return flagHelmCommandValue
+}
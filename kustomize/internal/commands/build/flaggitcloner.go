@@ -0,0 +1,51 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const (
+	flagGitClonerName = "git_cloner"
+)
+
+var (
+	flagGcValue = types.GitClonerExec.String()
+	flagGcHelp  = "if set to '" + types.GitClonerGo.String() +
+		"', remote git bases are cloned by a git implementation in Go, " +
+		"rather than by the git program on the path."
+)
+
+func addFlagGitCloner(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagGcValue, flagGitClonerName,
+		types.GitClonerExec.String(), flagGcHelp)
+}
+
+func validateFlagGitCloner() error {
+	switch getFlagGitClonerValue() {
+	case types.GitClonerExec, types.GitClonerGo:
+		return nil
+	default:
+		return fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagGitClonerName, flagGcValue,
+			[]string{types.GitClonerExec.String(), types.GitClonerGo.String()})
+	}
+}
+
+func getFlagGitClonerValue() types.GitCloner {
+	switch flagGcValue {
+	case types.GitClonerExec.String(), "exec":
+		return types.GitClonerExec
+	case types.GitClonerGo.String(), "go":
+		return types.GitClonerGo
+	default:
+		return types.GitClonerUnknown
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/imagedigest"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const (
+	flagImagePinningName = "image_pinning"
+)
+
+var (
+	flagIpValue = types.ImagePinningNone.String()
+	flagIpHelp  = "if set to '" + types.ImagePinningResolve.String() +
+		"', replace the tag of each container image with its digest, " +
+		"as recorded in " + imagedigest.LockFileName + " next to the " +
+		"kustomization file, else as resolved with the registry of the " +
+		"image, and then recorded.  If set to '" +
+		types.ImagePinningCheck.String() +
+		"', fail if any container image isn't pinned by digest."
+)
+
+func addFlagImagePinning(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagIpValue, flagImagePinningName,
+		types.ImagePinningNone.String(), flagIpHelp)
+}
+
+func validateFlagImagePinning() error {
+	switch getFlagImagePinningValue() {
+	case types.ImagePinningNone, types.ImagePinningResolve, types.ImagePinningCheck:
+		return nil
+	default:
+		return fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagImagePinningName, flagIpValue,
+			[]string{
+				types.ImagePinningNone.String(),
+				types.ImagePinningResolve.String(),
+				types.ImagePinningCheck.String()})
+	}
+}
+
+func getFlagImagePinningValue() types.ImagePinning {
+	switch flagIpValue {
+	case types.ImagePinningNone.String(), "none":
+		return types.ImagePinningNone
+	case types.ImagePinningResolve.String(), "resolve":
+		return types.ImagePinningResolve
+	case types.ImagePinningCheck.String(), "check":
+		return types.ImagePinningCheck
+	default:
+		return types.ImagePinningUnknown
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const (
+	flagSelectName  = "select"
+	flagExcludeName = "exclude"
+)
+
+// The fields of a selector, as in a kustomization file.
+var selectorFields = []string{
+	"group", "version", "kind", "name", "namespace",
+	"labelSelector", "annotationSelector"}
+
+var (
+	flagSelectValue  []string
+	flagExcludeValue []string
+	flagSelectorHelp = "a comma separated list of field=value, the fields " +
+		"being those of selectors in kustomization files: " +
+		strings.Join(selectorFields, ", ") + ", e.g. " +
+		"'kind=Deployment,labelSelector=app=web,tier=front'.  " +
+		"Names and namespaces are regular expressions.  " +
+		"Selection is made after all transformations, " +
+		"which apply to the resources left out too.  " +
+		"May be repeated."
+	flagSelectHelp  = "If specified, output only the resources matching one of these selectors, each " + flagSelectorHelp
+	flagExcludeHelp = "If specified, don't output the resources matching any of these selectors, each " + flagSelectorHelp
+)
+
+func addFlagSelect(set *pflag.FlagSet) {
+	set.StringArrayVar(
+		&flagSelectValue, flagSelectName, nil, flagSelectHelp)
+	set.StringArrayVar(
+		&flagExcludeValue, flagExcludeName, nil, flagExcludeHelp)
+}
+
+func validateFlagSelect() (selectors, exclusions []types.Selector, err error) {
+	selectors, err = parseSelectors(flagSelectName, flagSelectValue)
+	if err != nil {
+		return nil, nil, err
+	}
+	exclusions, err = parseSelectors(flagExcludeName, flagExcludeValue)
+	if err != nil {
+		return nil, nil, err
+	}
+	return selectors, exclusions, nil
+}
+
+func parseSelectors(flag string, values []string) ([]types.Selector, error) {
+	var result []types.Selector
+	for _, v := range values {
+		s, err := parseSelector(v)
+		if err != nil {
+			return nil, fmt.Errorf("illegal flag value --%s %s; %v", flag, v, err)
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// parseSelector parses a comma separated list of field=value.
+// Since label and annotation selectors hold commas themselves,
+// what follows one, up to the next field, is part of it.
+func parseSelector(value string) (types.Selector, error) {
+	var s types.Selector
+	fields := map[string]*string{
+		"group":              &s.Group,
+		"version":            &s.Version,
+		"kind":               &s.Kind,
+		"name":               &s.Name,
+		"namespace":          &s.Namespace,
+		"labelSelector":      &s.LabelSelector,
+		"annotationSelector": &s.AnnotationSelector,
+	}
+	var last *string
+	for _, part := range strings.Split(value, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if f, ok := fields[kv[0]]; ok && len(kv) == 2 {
+			if *f != "" {
+				return s, fmt.Errorf("%s given twice", kv[0])
+			}
+			*f, last = kv[1], f
+			continue
+		}
+		if last == &s.LabelSelector || last == &s.AnnotationSelector {
+			*last += "," + part
+			continue
+		}
+		return s, fmt.Errorf(
+			"expected field=value, with fields %v", selectorFields)
+	}
+	if s == (types.Selector{}) {
+		return s, fmt.Errorf("empty selector")
+	}
+	for _, re := range []string{s.Name, s.Namespace} {
+		if _, err := regexp.Compile(re); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
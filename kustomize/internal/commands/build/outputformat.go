@@ -0,0 +1,118 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/yaml"
+)
+
+//go:generate stringer -type=outputFormat -linecomment
+type outputFormat int
+
+const (
+	unknownFormat      outputFormat = iota // unknown
+	yamlFormat                             // yaml
+	jsonFormat                             // json
+	listFormat                             // list
+	resourceListFormat                     // resourcelist
+)
+
+const (
+	flagOutputFormatName = "output_format"
+)
+
+var (
+	flagOutputFormatValue = yamlFormat.String()
+	flagOutputFormatHelp  = "The format of the output. " +
+		"Use '" + yamlFormat.String() + "' for a stream of yaml documents. " +
+		"Use '" + jsonFormat.String() + "' for a stream of json objects. " +
+		"Use '" + listFormat.String() + "' for one v1 List holding the resources. " +
+		"Use '" + resourceListFormat.String() + "' for one " + kio.ResourceListKind +
+		", as read by kyaml functions. " +
+		"When writing to a directory, the format applies to each file."
+)
+
+func addFlagOutputFormat(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagOutputFormatValue, flagOutputFormatName,
+		yamlFormat.String(), flagOutputFormatHelp)
+}
+
+func validateFlagOutputFormat() (outputFormat, error) {
+	for _, f := range []outputFormat{
+		yamlFormat, jsonFormat, listFormat, resourceListFormat} {
+		if flagOutputFormatValue == f.String() {
+			return f, nil
+		}
+	}
+	return unknownFormat, fmt.Errorf(
+		"illegal flag value --%s %s; legal values: %v",
+		flagOutputFormatName, flagOutputFormatValue,
+		[]string{yamlFormat.String(), jsonFormat.String(),
+			listFormat.String(), resourceListFormat.String()})
+}
+
+// extension returns the file name extension
+// of the format.
+func (f outputFormat) extension() string {
+	if f == jsonFormat {
+		return ".json"
+	}
+	return ".yaml"
+}
+
+// encode writes the resources in the format.
+func (f outputFormat) encode(resources []*resource.Resource) ([]byte, error) {
+	switch f {
+	case jsonFormat:
+		var buf bytes.Buffer
+		for _, res := range resources {
+			out, err := json.MarshalIndent(res.Map(), "", "  ")
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(out)
+			buf.WriteString("\n")
+		}
+		return buf.Bytes(), nil
+	case listFormat:
+		return encodeList("v1", "List", resources)
+	case resourceListFormat:
+		return encodeList(
+			kio.ResourceListAPIVersion, kio.ResourceListKind, resources)
+	default:
+		var buf bytes.Buffer
+		for i, res := range resources {
+			out, err := yaml.Marshal(res.Map())
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 {
+				buf.WriteString("---\n")
+			}
+			buf.Write(out)
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+func encodeList(
+	apiVersion, kind string, resources []*resource.Resource) ([]byte, error) {
+	items := make([]interface{}, 0, len(resources))
+	for _, res := range resources {
+		items = append(items, res.Map())
+	}
+	return yaml.Marshal(map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"items":      items,
+	})
+}
@@ -0,0 +1,208 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	resmaptest_test "sigs.k8s.io/kustomize/api/testutils/resmaptest"
+)
+
+var resourceFactory = resource.NewFactory(kunstruct.NewKunstructuredFactoryImpl())
+
+func makeResMap(t *testing.T) resmap.ResMap {
+	return resmaptest_test.NewRmBuilder(t, resourceFactory).
+		Add(map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      "web",
+				"namespace": "prod",
+			}}).
+		Add(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata": map[string]interface{}{
+				"name":      "web",
+				"namespace": "prod",
+			}}).
+		Add(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata": map[string]interface{}{
+				"name": "prod",
+			}}).ResMap()
+}
+
+func TestEmitResourcesFormats(t *testing.T) {
+	testCases := map[outputFormat]string{
+		yamlFormat: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: prod
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: prod
+`,
+		jsonFormat: `{
+  "apiVersion": "apps/v1",
+  "kind": "Deployment",
+  "metadata": {
+    "name": "web",
+    "namespace": "prod"
+  }
+}
+{
+  "apiVersion": "v1",
+  "kind": "Service",
+  "metadata": {
+    "name": "web",
+    "namespace": "prod"
+  }
+}
+{
+  "apiVersion": "v1",
+  "kind": "Namespace",
+  "metadata": {
+    "name": "prod"
+  }
+}
+`,
+		listFormat: `apiVersion: v1
+items:
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: web
+    namespace: prod
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: web
+    namespace: prod
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: prod
+kind: List
+`,
+		resourceListFormat: `apiVersion: config.kubernetes.io/v1alpha1
+items:
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: web
+    namespace: prod
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: web
+    namespace: prod
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: prod
+kind: ResourceList
+`,
+	}
+	for f, expected := range testCases {
+		t.Run(f.String(), func(t *testing.T) {
+			o := Options{outFormat: f}
+			var out bytes.Buffer
+			err := o.emitResources(&out, filesys.MakeFsInMemory(), makeResMap(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != expected {
+				t.Fatalf("expected\n%s\nbut got\n%s", expected, out.String())
+			}
+		})
+	}
+}
+
+func TestEmitResourcesLayout(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	o := Options{
+		outputPath:   "out",
+		outputLayout: "{{.Namespace}}/{{.Kind | lower}}s.json",
+	}
+	defer func(v string) { flagOutputFormatValue = v }(flagOutputFormatValue)
+	flagOutputFormatValue = jsonFormat.String()
+	if err := o.Validate(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := o.emitResources(nil, fSys, makeResMap(t)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, p := range []string{
+		"out/prod/deployments.json",
+		"out/prod/services.json",
+		"out/namespaces.json",
+	} {
+		if !fSys.Exists(p) {
+			t.Errorf("expected %s", p)
+		}
+	}
+	b, err := fSys.ReadFile("out/namespaces.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "apiVersion": "v1",
+  "kind": "Namespace",
+  "metadata": {
+    "name": "prod"
+  }
+}
+`
+	if string(b) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b)
+	}
+
+	o.outputLayout = "../{{.Name}}.yaml"
+	if err := o.Validate(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := o.emitResources(nil, fSys, makeResMap(t)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fSys.Exists("out/web.yaml") {
+		t.Errorf("expected the layout to stay in the output directory")
+	}
+}
+
+func TestEmitResourcesIndividualFiles(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	if err := fSys.Mkdir("out"); err != nil {
+		t.Fatal(err)
+	}
+	o := Options{outputPath: "out", outFormat: jsonFormat}
+	m := resmaptest_test.NewRmBuilder(t, resourceFactory).
+		Add(map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name": "web",
+			}}).ResMap()
+	if err := o.emitResources(nil, fSys, m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fSys.Exists("out/apps_v1_deployment_web.json") {
+		t.Errorf("expected out/apps_v1_deployment_web.json")
+	}
+}
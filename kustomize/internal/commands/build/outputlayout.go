@@ -0,0 +1,96 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+// layoutFields are the fields of a resource that
+// an output layout template may use.
+type layoutFields struct {
+	Group     string
+	Version   string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// parseLayout parses a template of the paths, relative to the
+// output directory, of the files to write resources to, e.g.
+//
+//	{{.Namespace}}/{{.Kind | lower}}/{{.Name}}.yaml
+func parseLayout(layout string) (*template.Template, error) {
+	return template.New("layout").
+		Funcs(template.FuncMap{"lower": strings.ToLower}).
+		Option("missingkey=error").
+		Parse(layout)
+}
+
+// layoutPath returns the path of the file, relative to the
+// output directory, for the resource.  Paths can't leave
+// the directory.
+func layoutPath(layout *template.Template, res *resource.Resource) (string, error) {
+	gvk := res.GetGvk()
+	var b strings.Builder
+	err := layout.Execute(&b, layoutFields{
+		Group:     gvk.Group,
+		Version:   gvk.Version,
+		Kind:      gvk.Kind,
+		Namespace: res.GetNamespace(),
+		Name:      res.GetName(),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "output layout of %s", res.CurId())
+	}
+	p := strings.TrimPrefix(
+		filepath.Clean(string(filepath.Separator)+b.String()),
+		string(filepath.Separator))
+	if p == "" {
+		return "", fmt.Errorf("output layout gives no file for %s", res.CurId())
+	}
+	return p, nil
+}
+
+// writeLayout writes the resources to the files in dir
+// given by the layout, in the format.  Resources given
+// the same file are written to it together, in order.
+func writeLayout(
+	fSys filesys.FileSystem, dir string, layout *template.Template,
+	format outputFormat, m resmap.ResMap) error {
+	var paths []string
+	files := make(map[string][]*resource.Resource)
+	for _, res := range m.Resources() {
+		p, err := layoutPath(layout, res)
+		if err != nil {
+			return err
+		}
+		if _, ok := files[p]; !ok {
+			paths = append(paths, p)
+		}
+		files[p] = append(files[p], res)
+	}
+	for _, p := range paths {
+		path := filepath.Join(dir, p)
+		if err := fSys.MkdirAll(filepath.Dir(path)); err != nil {
+			return err
+		}
+		out, err := format.encode(files[p])
+		if err != nil {
+			return err
+		}
+		if err = fSys.WriteFile(path, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
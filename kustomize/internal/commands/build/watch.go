@@ -0,0 +1,212 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+)
+
+// The interval at which watched files are checked.
+const watchPollInterval = 250 * time.Millisecond
+
+// recordingFs is a file system that records
+// the files read through it.
+type recordingFs struct {
+	filesys.FileSystem
+	mu    sync.Mutex
+	paths map[string]bool
+	// Directories of helm charts, which helm reads
+	// itself, rather than through the file system.
+	charts map[string]bool
+}
+
+func newRecordingFs(fSys filesys.FileSystem) *recordingFs {
+	return &recordingFs{
+		FileSystem: fSys,
+		paths:      make(map[string]bool),
+		charts:     make(map[string]bool),
+	}
+}
+
+func (fs *recordingFs) record(path string) {
+	if p, err := filepath.Abs(path); err == nil {
+		path = p
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.paths[path] = true
+	// The helm chart generator loads the Chart.yaml
+	// of a local chart before running helm on it.
+	if filepath.Base(path) == "Chart.yaml" {
+		fs.charts[filepath.Dir(path)] = true
+	}
+}
+
+// Open implements filesys.FileSystem.
+func (fs *recordingFs) Open(path string) (filesys.File, error) {
+	fs.record(path)
+	return fs.FileSystem.Open(path)
+}
+
+// ReadFile implements filesys.FileSystem.
+func (fs *recordingFs) ReadFile(path string) ([]byte, error) {
+	fs.record(path)
+	return fs.FileSystem.ReadFile(path)
+}
+
+// files returns the sorted absolute paths of the files
+// read, and of everything below the directories of the
+// charts read, so that adding a template is seen too.
+func (fs *recordingFs) files() []string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	paths := make(map[string]bool, len(fs.paths))
+	for p := range fs.paths {
+		paths[p] = true
+	}
+	for dir := range fs.charts {
+		// A chart gone missing is seen by its Chart.yaml.
+		_ = fs.FileSystem.Walk(dir,
+			func(path string, _ os.FileInfo, err error) error {
+				if err == nil {
+					paths[path] = true
+				}
+				return nil
+			})
+	}
+	result := make([]string, 0, len(paths))
+	for p := range paths {
+		result = append(result, p)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// fileState is what's checked for changes of a watched file.
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func snapshot(paths []string) map[string]fileState {
+	result := make(map[string]fileState, len(paths))
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil {
+			result[p] = fileState{
+				exists: true, size: info.Size(), modTime: info.ModTime()}
+		} else {
+			result[p] = fileState{}
+		}
+	}
+	return result
+}
+
+// waitForChange polls the files until some change, and then
+// until none has changed for the debounce period, returning
+// the files changed; or returns false once stop is closed.
+func waitForChange(
+	paths []string, interval, debounce time.Duration,
+	stop <-chan struct{}) ([]string, bool) {
+	before := snapshot(paths)
+	changed := make(map[string]bool)
+	var last time.Time
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil, false
+		case now := <-ticker.C:
+			after := snapshot(paths)
+			for _, p := range paths {
+				if after[p] != before[p] {
+					changed[p] = true
+					last = now
+				}
+			}
+			before = after
+			if len(changed) > 0 && now.Sub(last) >= debounce {
+				var result []string
+				for p := range changed {
+					result = append(result, p)
+				}
+				sort.Strings(result)
+				return result, true
+			}
+		}
+	}
+}
+
+// runWatch builds, and rebuilds whenever a file read by the
+// previous build changes, until stopWatch is closed.  Build
+// errors are reported to errOut, and the files read until the
+// error are watched for a fix.
+func (o *Options) runWatch(out io.Writer) error {
+	errOut := o.watchErrOut
+	if errOut == nil {
+		errOut = os.Stderr
+	}
+	interval := o.watchPollInterval
+	if interval == 0 {
+		interval = watchPollInterval
+	}
+	var previous string
+	for {
+		fSys := newRecordingFs(filesys.MakeFsOnDisk())
+		if err := o.watchBuild(out, fSys, &previous); err != nil {
+			fmt.Fprintf(errOut, "Error: %v\n", err)
+		}
+		files := fSys.files()
+		if len(files) == 0 {
+			return fmt.Errorf("no files read building %s", o.kustomizationPath)
+		}
+		changed, ok := waitForChange(files, interval, o.watchDebounce, o.stopWatch)
+		if !ok {
+			return nil
+		}
+		fmt.Fprintf(errOut, "Rebuilding, changed: %s\n", strings.Join(changed, ", "))
+	}
+}
+
+func (o *Options) watchBuild(
+	out io.Writer, fSys filesys.FileSystem, previous *string) error {
+	k := krusty.MakeKustomizer(fSys, o.makeOptions())
+	m, err := k.Run(o.kustomizationPath)
+	if err != nil {
+		return err
+	}
+	if !o.watchDiff {
+		return o.emitResources(out, fSys, m)
+	}
+	b, err := o.outFormat.encode(m.Resources())
+	if err != nil {
+		return err
+	}
+	current := string(b)
+	if *previous == "" {
+		_, err = io.WriteString(out, current)
+	} else {
+		err = difflib.WriteUnifiedDiff(out, difflib.UnifiedDiff{
+			A:        difflib.SplitLines(*previous),
+			B:        difflib.SplitLines(current),
+			FromFile: "previous",
+			ToFile:   "current",
+			Context:  3,
+		})
+	}
+	*previous = current
+	return err
+}
@@ -0,0 +1,134 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+)
+
+// syncBuffer is a buffer written by a watch, and
+// read by a test.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitFor waits for the buffer to contain s.
+func (b *syncBuffer) waitFor(t *testing.T, s string) {
+	deadline := time.Now().Add(10 * time.Second)
+	for !strings.Contains(b.String(), s) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected output to contain\n%s\nbut got\n%s", s, b.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRecordingFs(t *testing.T) {
+	fSys := newRecordingFs(filesys.MakeFsInMemory())
+	fSys.WriteFile("/a/b.yaml", []byte("b"))
+	fSys.WriteFile("/a/c.yaml", []byte("c"))
+	fSys.ReadFile("/a/b.yaml")
+	fSys.Exists("/a/c.yaml")
+	fSys.ReadFile("/a/d.yaml")
+	if f := fSys.files(); strings.Join(f, " ") != "/a/b.yaml /a/d.yaml" {
+		t.Fatalf("unexpected files read: %v", f)
+	}
+}
+
+func TestRecordingFsCharts(t *testing.T) {
+	fSys := newRecordingFs(filesys.MakeFsInMemory())
+	fSys.WriteFile("/a/chart/Chart.yaml", []byte("name: web"))
+	fSys.WriteFile("/a/chart/templates/web.yaml", []byte("web"))
+	fSys.WriteFile("/a/other.yaml", []byte("other"))
+	fSys.ReadFile("/a/chart/Chart.yaml")
+	expected := "/a/chart /a/chart/Chart.yaml /a/chart/templates " +
+		"/a/chart/templates/web.yaml"
+	if f := fSys.files(); strings.Join(f, " ") != expected {
+		t.Fatalf("unexpected files: %v", f)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-watch-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, content string) {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("kustomization.yaml", `configMapGenerator:
+- name: app
+  envs:
+  - app.env
+generatorOptions:
+  disableNameSuffixHash: true
+`)
+	write("app.env", "COLOR=red\n")
+
+	var out, errOut syncBuffer
+	stop := make(chan struct{})
+	done := make(chan error)
+	o := Options{
+		kustomizationPath: dir,
+		watch:             true,
+		watchDiff:         true,
+		watchDebounce:     20 * time.Millisecond,
+		watchPollInterval: 5 * time.Millisecond,
+		watchErrOut:       &errOut,
+		stopWatch:         stop,
+	}
+	go func() { done <- o.RunBuild(&out) }()
+
+	out.waitFor(t, "  COLOR: red\n")
+	write("app.env", "COLOR=blue\n")
+	out.waitFor(t, `@@ -1,6 +1,6 @@
+ apiVersion: v1
+ data:
+-  COLOR: red
++  COLOR: blue
+ kind: ConfigMap
+`)
+	errOut.waitFor(t, "Rebuilding, changed: "+filepath.Join(dir, "app.env"))
+
+	// A broken build is reported, and fixing it rebuilds.
+	write("kustomization.yaml", "resources:\n- missing.yaml\n")
+	errOut.waitFor(t, "Error: ")
+	write("kustomization.yaml", `configMapGenerator:
+- name: app
+  envs:
+  - app.env
+`)
+	out.waitFor(t, "+  name: app-")
+
+	close(stop)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/loader"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// NewCmdCache returns an instance of 'cache' subcommand.
+func NewCmdCache(out io.Writer) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "cache",
+		Short: "Manages the cache of remote git bases",
+		Long: `Manages the cache of clones of remote git bases,
+kept in ` + konfig.DefaultAbsGitCacheDir() + `
+when building with --git_cache.`,
+		Args: cobra.MinimumNArgs(1),
+	}
+	c.AddCommand(newCmdPrune(out, konfig.DefaultGitCacheConfig()))
+	return c
+}
+
+func newCmdPrune(out io.Writer, c *types.GitCacheConfig) *cobra.Command {
+	var maxAge time.Duration
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Removes cached clones that builds haven't used lately",
+		Example: `
+  # Remove clones unused for a week.
+  kustomize cache prune --max_age 168h
+`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return fmt.Errorf("prune takes no arguments")
+			}
+			removed, err := loader.PruneGitCache(c, maxAge)
+			for _, dir := range removed {
+				fmt.Fprintf(out, "removed %s\n", dir)
+			}
+			return err
+		},
+	}
+	cmd.Flags().DurationVar(
+		&maxAge, "max_age", 30*24*time.Hour,
+		"Remove clones not used for at least this long.")
+	return cmd
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func TestPrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-cache-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	old := filepath.Join(dir, "example.com", "org%2Frepo", "v1")
+	recent := filepath.Join(dir, "example.com", "org%2Frepo", "v2")
+	for _, d := range []string{old, recent} {
+		if err := os.MkdirAll(d, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	then := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(old, then, then); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	cmd := newCmdPrune(&out, &types.GitCacheConfig{AbsCacheDir: dir})
+	cmd.SetArgs([]string{"--max_age", "1h"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "removed "+old+"\n" {
+		t.Fatalf("unexpected output: %s", out.String())
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Fatalf("recent entry removed: %v", err)
+	}
+}
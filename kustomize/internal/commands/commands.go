@@ -14,12 +14,16 @@ import (
 	"sigs.k8s.io/kustomize/api/k8sdeps/validator"
 	"sigs.k8s.io/kustomize/api/konfig"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/build"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/cache"
 
 	// "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/config"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/create"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/diff"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/edit"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/graph"
 
 	// "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/status"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/validate"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/version"
 )
 
@@ -42,6 +46,10 @@ See https://sigs.k8s.io/kustomize
 		build.NewCmdBuild(stdOut),
 		edit.NewCmdEdit(fSys, v, uf),
 		create.NewCmdCreate(fSys, uf),
+		diff.NewCmdDiff(stdOut, fSys),
+		graph.NewCmdGraph(stdOut, fSys),
+		cache.NewCmdCache(stdOut),
+		validate.NewCmdValidate(stdOut, fSys),
 		// config.NewCmdConfig(fSys),
 		version.NewCmdVersion(stdOut),
 		// status.NewCmdStatus(),
@@ -0,0 +1,214 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+const (
+	formatText = "text"
+	formatJson = "json"
+)
+
+// Options contain the options for running a diff.
+type Options struct {
+	oldPath string
+	newPath string
+	format  string
+}
+
+var examples = `
+To compare the output of two overlays, run
+
+  kustomize diff overlays/staging overlays/production
+
+Either argument may instead be a directory of
+manifests without a kustomization file, e.g. the
+output of 'kustomize build -o someDir', or a single
+manifest file.
+
+Resources are matched by their id (group, version,
+kind, namespace and name), falling back to their id
+before name prefixes, suffixes and hashes were applied.
+
+For output meant for programs rather than people, run
+
+  kustomize diff --format json old new
+`
+
+// NewCmdDiff creates a new diff command.
+func NewCmdDiff(out io.Writer, fSys filesys.FileSystem) *cobra.Command {
+	var o Options
+	cmd := &cobra.Command{
+		Use:          "diff {oldPath} {newPath}",
+		Short:        "Print the differences between two builds",
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunDiff(out, fSys)
+		},
+	}
+	cmd.Flags().StringVar(
+		&o.format,
+		"format", formatText,
+		"Output format, one of: "+formatText+", "+formatJson+".")
+	return cmd
+}
+
+// Validate validates diff command.
+func (o *Options) Validate(args []string) error {
+	if len(args) != 2 {
+		return errors.New("specify two paths to compare")
+	}
+	o.oldPath = args[0]
+	o.newPath = args[1]
+	switch o.format {
+	case "":
+		o.format = formatText
+	case formatText, formatJson:
+	default:
+		return fmt.Errorf(
+			"illegal flag value --format %s; legal values: %v",
+			o.format, []string{formatText, formatJson})
+	}
+	return nil
+}
+
+// RunDiff builds both paths and prints their differences.
+func (o *Options) RunDiff(out io.Writer, fSys filesys.FileSystem) error {
+	m1, err := resMapAt(fSys, o.oldPath)
+	if err != nil {
+		return err
+	}
+	m2, err := resMapAt(fSys, o.newPath)
+	if err != nil {
+		return err
+	}
+	d := m1.Diff(m2)
+	if o.format == formatJson {
+		b, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(b))
+		return err
+	}
+	return writeText(out, d)
+}
+
+// resMapAt returns the result of building the kustomization at
+// the given path or, lacking a kustomization, the resources in
+// the manifest file or directory of manifests at that path.
+func resMapAt(fSys filesys.FileSystem, path string) (resmap.ResMap, error) {
+	if fSys.Exists(path) && !hasKustomizationFile(fSys, path) {
+		m, err := readManifests(fSys, path)
+		return m, errors.Wrapf(err, "reading manifests from '%s'", path)
+	}
+	opts := krusty.MakeDefaultOptions()
+	opts.RerorderTransformer = "none"
+	m, err := krusty.MakeKustomizer(fSys, opts).Run(path)
+	return m, errors.Wrapf(err, "building '%s'", path)
+}
+
+func hasKustomizationFile(fSys filesys.FileSystem, path string) bool {
+	if !fSys.IsDir(path) {
+		return false
+	}
+	for _, n := range konfig.RecognizedKustomizationFileNames() {
+		if fSys.Exists(filepath.Join(path, n)) {
+			return true
+		}
+	}
+	return false
+}
+
+func readManifests(fSys filesys.FileSystem, path string) (resmap.ResMap, error) {
+	rmF := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	result := resmap.New()
+	err := fSys.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !isManifest(p) {
+			return nil
+		}
+		content, err := fSys.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		m, err := rmF.NewResMapFromBytes(content)
+		if err != nil {
+			return errors.Wrapf(err, "reading '%s'", p)
+		}
+		return result.AppendAll(m)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func isManifest(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+func writeText(out io.Writer, d *resmap.Diff) error {
+	var b strings.Builder
+	for _, id := range d.Removed {
+		fmt.Fprintf(&b, "- %s\n", id)
+	}
+	for _, id := range d.Added {
+		fmt.Fprintf(&b, "+ %s\n", id)
+	}
+	for _, rd := range d.Changed {
+		if rd.OldId == rd.NewId {
+			fmt.Fprintf(&b, "~ %s\n", rd.NewId)
+		} else {
+			fmt.Fprintf(&b, "~ %s -> %s\n", rd.OldId, rd.NewId)
+		}
+		for _, f := range rd.Fields {
+			fmt.Fprintf(&b, "    %s: %s -> %s\n",
+				f.Path, asText(f.Old), asText(f.New))
+		}
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// asText renders a field value compactly, as JSON,
+// with absent values rendered as <none>.
+func asText(v interface{}) string {
+	if v == nil {
+		return "<none>"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
@@ -0,0 +1,156 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+)
+
+func writeOverlays(t *testing.T) filesys.FileSystem {
+	fSys := filesys.MakeFsInMemory()
+	for p, content := range map[string]string{
+		"/base/kustomization.yaml": `
+resources:
+- deployment.yaml
+configMapGenerator:
+- name: config
+  literals:
+  - fruit=apple
+`,
+		"/base/deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+spec:
+  replicas: 1
+`,
+		"/staging/kustomization.yaml": `
+resources:
+- ../base
+namePrefix: staging-
+`,
+		"/prod/kustomization.yaml": `
+resources:
+- ../base
+- service.yaml
+namePrefix: prod-
+patchesStrategicMerge:
+- patch.yaml
+`,
+		"/prod/patch.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+spec:
+  replicas: 3
+`,
+		"/prod/service.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`,
+		"/manifests/deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: staging-dep
+spec:
+  replicas: 2
+`,
+	} {
+		if err := fSys.WriteFile(p, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return fSys
+}
+
+func TestDiffValidate(t *testing.T) {
+	o := Options{}
+	if err := o.Validate([]string{"a"}); err == nil {
+		t.Fatalf("expected error for one path")
+	}
+	o = Options{format: "xml"}
+	err := o.Validate([]string{"a", "b"})
+	if err == nil || !strings.Contains(err.Error(), "illegal flag value") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDiffText(t *testing.T) {
+	fSys := writeOverlays(t)
+	out := &bytes.Buffer{}
+	cmd := NewCmdDiff(out, fSys)
+	err := cmd.RunE(cmd, []string{"/staging", "/prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `+ ~G_v1_Service|~X|prod-svc
+~ apps_v1_Deployment|~X|staging-dep -> apps_v1_Deployment|~X|prod-dep
+    metadata.name: "staging-dep" -> "prod-dep"
+    spec.replicas: 1 -> 3
+~ ~G_v1_ConfigMap|~X|staging-config-hcg65bg7f2 -> ~G_v1_ConfigMap|~X|prod-config-fk2cf6fmcf
+    metadata.name: "staging-config-hcg65bg7f2" -> "prod-config-fk2cf6fmcf"
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestDiffJsonAgainstManifests(t *testing.T) {
+	fSys := writeOverlays(t)
+	out := &bytes.Buffer{}
+	o := Options{format: formatJson}
+	err := o.Validate([]string{"/manifests", "/staging"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = o.RunDiff(out, fSys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "added": [
+    {
+      "version": "v1",
+      "kind": "ConfigMap",
+      "name": "staging-config-hcg65bg7f2"
+    }
+  ],
+  "changed": [
+    {
+      "oldId": {
+        "group": "apps",
+        "version": "v1",
+        "kind": "Deployment",
+        "name": "staging-dep"
+      },
+      "newId": {
+        "group": "apps",
+        "version": "v1",
+        "kind": "Deployment",
+        "name": "staging-dep"
+      },
+      "fields": [
+        {
+          "path": "spec.replicas",
+          "old": 2,
+          "new": 1
+        }
+      ]
+    }
+  ]
+}
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package add
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/edit/filelist"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/util"
+)
+
+type addFileListOptions struct {
+	field filelist.Field
+	paths []string
+}
+
+// newCmdAddFileList adds the paths of files, or directories,
+// to a list of them in the kustomization file.
+func newCmdAddFileList(fSys filesys.FileSystem, f filelist.Field) *cobra.Command {
+	o := addFileListOptions{field: f}
+
+	cmd := &cobra.Command{
+		Use:   f.Name,
+		Short: "Add the path of a file, or directory, holding " + f.What + " to the kustomization file.",
+		Example: `
+		add ` + f.Name + ` {filepath}`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunAddFileList(fSys)
+		},
+	}
+	return cmd
+}
+
+// Validate validates addFileList command.
+func (o *addFileListOptions) Validate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("must specify a %s file", o.field.Name)
+	}
+	o.paths = args
+	return nil
+}
+
+// RunAddFileList runs addFileList command (do real work).
+func (o *addFileListOptions) RunAddFileList(fSys filesys.FileSystem) error {
+	paths, err := util.GlobPatterns(fSys, o.paths)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	if err != nil {
+		return err
+	}
+
+	m, err := mf.Read()
+	if err != nil {
+		return err
+	}
+
+	field := o.field.Get(m)
+	for _, p := range paths {
+		if kustfile.StringInSlice(p, *field) {
+			log.Printf("%s %s already in kustomization file", o.field.Name, p)
+			continue
+		}
+		*field = append(*field, p)
+	}
+
+	return mf.Write(m)
+}
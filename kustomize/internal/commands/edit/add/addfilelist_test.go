@@ -0,0 +1,56 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package add
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/edit/filelist"
+	testutils_test "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
+)
+
+func TestAddFileList(t *testing.T) {
+	for _, f := range filelist.Fields {
+		t.Run(f.Name, func(t *testing.T) {
+			fSys := filesys.MakeEmptyDirInMemory()
+			fSys.WriteFile("a.yaml", []byte(""))
+			fSys.WriteFile("b.yaml", []byte(""))
+			testutils_test.WriteTestKustomization(fSys)
+
+			cmd := newCmdAddFileList(fSys, f)
+			err := cmd.RunE(cmd, []string{"a.yaml"})
+			if err != nil {
+				t.Fatalf("unexpected cmd error: %v", err)
+			}
+			// adding an existing path doesn't return an error
+			err = cmd.RunE(cmd, []string{"*.yaml"})
+			if err != nil {
+				t.Fatalf("unexpected cmd error: %v", err)
+			}
+			content, err := testutils_test.ReadTestKustomization(fSys)
+			if err != nil {
+				t.Fatalf("unexpected read error: %v", err)
+			}
+			if !strings.Contains(string(content), "\n- a.yaml\n- b.yaml\n") {
+				t.Errorf("expected paths in kustomization, got\n%s", content)
+			}
+			// comments are kept
+			if !strings.Contains(string(content), "# There could be secrets in Base") {
+				t.Errorf("expected comments in kustomization, got\n%s", content)
+			}
+		})
+	}
+}
+
+func TestAddFileListNoArgs(t *testing.T) {
+	fSys := filesys.MakeEmptyDirInMemory()
+
+	cmd := newCmdAddFileList(fSys, filelist.Fields[1])
+	err := cmd.Execute()
+	if err == nil || err.Error() != "must specify a crd file" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package add
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
+)
+
+type addHelmChartOptions struct {
+	chart types.HelmChartArgs
+}
+
+// newCmdAddHelmChart adds a helm chart to inflate to the kustomization file.
+func newCmdAddHelmChart(fSys filesys.FileSystem) *cobra.Command {
+	var o addHelmChartOptions
+
+	cmd := &cobra.Command{
+		Use:   "helmchart CHART",
+		Short: "Add a helm chart, to inflate into resources, to the kustomization file.",
+		Example: `
+		add helmchart ./charts/minecraft --release-name moria --values-file values.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunAddHelmChart(fSys)
+		},
+	}
+	cmd.Flags().StringVar(&o.chart.ReleaseName, "release-name", "",
+		"Name of the release")
+	cmd.Flags().StringVar(&o.chart.ReleaseNamespace, "release-namespace", "",
+		"Namespace of the release")
+	cmd.Flags().StringVar(&o.chart.ValuesFile, "values-file", "",
+		"Path of a file holding values for the chart")
+	cmd.Flags().BoolVar(&o.chart.IncludeCRDs, "include-crds", false,
+		"If true, include the CRDs of the chart")
+	return cmd
+}
+
+// Validate validates addHelmChart command.
+func (o *addHelmChartOptions) Validate(args []string) error {
+	if len(args) != 1 {
+		return errors.New("must specify one chart")
+	}
+	o.chart.Chart = args[0]
+	return nil
+}
+
+// RunAddHelmChart runs addHelmChart command (do real work).
+func (o *addHelmChartOptions) RunAddHelmChart(fSys filesys.FileSystem) error {
+	if o.chart.ValuesFile != "" && !fSys.Exists(o.chart.ValuesFile) {
+		return errors.New(o.chart.ValuesFile + " does not exist")
+	}
+
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	if err != nil {
+		return err
+	}
+
+	m, err := mf.Read()
+	if err != nil {
+		return err
+	}
+
+	for _, c := range m.HelmCharts {
+		if c.Chart == o.chart.Chart && c.ReleaseName == o.chart.ReleaseName {
+			return fmt.Errorf(
+				"chart %s, released as '%s', already in kustomization file",
+				c.Chart, c.ReleaseName)
+		}
+	}
+	m.HelmCharts = append(m.HelmCharts, o.chart)
+
+	return mf.Write(m)
+}
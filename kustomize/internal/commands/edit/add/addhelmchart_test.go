@@ -0,0 +1,53 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package add
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	testutils_test "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
+)
+
+func TestAddHelmChart(t *testing.T) {
+	fSys := filesys.MakeEmptyDirInMemory()
+	fSys.WriteFile("values.yaml", []byte("replicas: 2\n"))
+	testutils_test.WriteTestKustomization(fSys)
+
+	cmd := newCmdAddHelmChart(fSys)
+	for _, f := range [][2]string{
+		{"release-name", "moria"},
+		{"values-file", "values.yaml"},
+		{"include-crds", "true"},
+	} {
+		if err := cmd.Flags().Set(f[0], f[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	args := []string{"./charts/minecraft"}
+	err := cmd.RunE(cmd, args)
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := `helmCharts:
+- chart: ./charts/minecraft
+  includeCRDs: true
+  releaseName: moria
+  valuesFile: values.yaml
+`
+	if !strings.Contains(string(content), expected) {
+		t.Errorf("expected\n%s\nin kustomization, got\n%s", expected, content)
+	}
+
+	err = cmd.RunE(cmd, args)
+	if err == nil || err.Error() !=
+		"chart ./charts/minecraft, released as 'moria', already in kustomization file" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
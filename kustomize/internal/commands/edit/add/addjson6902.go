@@ -0,0 +1,83 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package add
+
+import (
+	"errors"
+	"log"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/edit/patch"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
+)
+
+type addJson6902Options struct {
+	patchFilePath string
+	target        patch.TargetFlags
+}
+
+// newCmdAddJson6902 adds a JSON patch, and its target,
+// to the patchesJson6902 field of the kustomization file.
+func newCmdAddJson6902(fSys filesys.FileSystem) *cobra.Command {
+	var o addJson6902Options
+
+	cmd := &cobra.Command{
+		Use:   "json6902",
+		Short: "Add the name of a file containing a JSON patch, and its target, to the kustomization file.",
+		Example: `
+		add json6902 {filepath} --group apps --version v1 --kind Deployment --name web`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunAddJson6902(fSys)
+		},
+	}
+	o.target.AddFlags(cmd.Flags())
+	return cmd
+}
+
+// Validate validates addJson6902 command.
+func (o *addJson6902Options) Validate(args []string) error {
+	if len(args) != 1 {
+		return errors.New("must specify one patch file")
+	}
+	o.patchFilePath = args[0]
+	return nil
+}
+
+// RunAddJson6902 runs addJson6902 command (do real work).
+func (o *addJson6902Options) RunAddJson6902(fSys filesys.FileSystem) error {
+	target, err := o.target.PatchTarget()
+	if err != nil {
+		return err
+	}
+	if !fSys.Exists(o.patchFilePath) {
+		return errors.New(o.patchFilePath + " does not exist")
+	}
+
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	if err != nil {
+		return err
+	}
+
+	m, err := mf.Read()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range m.PatchesJson6902 {
+		if p.Path == o.patchFilePath && p.Target != nil && *p.Target == *target {
+			log.Printf("patch %s already in kustomization file", o.patchFilePath)
+			return nil
+		}
+	}
+	m.PatchesJson6902 = append(m.PatchesJson6902,
+		types.PatchJson6902{Target: target, Path: o.patchFilePath})
+
+	return mf.Write(m)
+}
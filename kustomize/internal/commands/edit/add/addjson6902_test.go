@@ -0,0 +1,65 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package add
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	testutils_test "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
+)
+
+func TestAddJson6902(t *testing.T) {
+	fSys := filesys.MakeEmptyDirInMemory()
+	fSys.WriteFile("patch.json", []byte(`[]`))
+	testutils_test.WriteTestKustomization(fSys)
+
+	cmd := newCmdAddJson6902(fSys)
+	args := []string{"patch.json"}
+	err := cmd.RunE(cmd, args)
+	if err == nil || err.Error() !=
+		"the target of a JSON patch needs a version, a kind and a name" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, f := range [][2]string{
+		{"group", "apps"},
+		{"version", "v1"},
+		{"kind", "Deployment"},
+		{"name", "web"},
+	} {
+		if err = cmd.Flags().Set(f[0], f[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = cmd.RunE(cmd, args)
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	// adding an existing patch doesn't return an error
+	err = cmd.RunE(cmd, args)
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := `patchesJson6902:
+- path: patch.json
+  target:
+    group: apps
+    kind: Deployment
+    name: web
+    version: v1
+`
+	if !strings.Contains(string(content), expected) {
+		t.Errorf("expected\n%s\nin kustomization, got\n%s", expected, content)
+	}
+
+	err = cmd.RunE(cmd, []string{"missing.json"})
+	if err == nil || err.Error() != "missing.json does not exist" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/edit/patch"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/util"
@@ -16,6 +17,9 @@ import (
 
 type addPatchOptions struct {
 	patchFilePaths []string
+	path           string
+	patch          string
+	target         patch.TargetFlags
 }
 
 // newCmdAddPatch adds the name of a file containing a patch to the kustomization file.
@@ -25,8 +29,14 @@ func newCmdAddPatch(fSys filesys.FileSystem) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "patch",
 		Short: "Add the name of a file containing a patch to the kustomization file.",
+		Long: `Without flags, adds the paths of files holding strategic merge
+patches to the patchesStrategicMerge field.  With --path, or with
+--patch, adds a patch, strategic merge or JSON, to the patches field,
+applying to the resources selected by the target flags, if any.`,
 		Example: `
-		add patch {filepath}`,
+		add patch {filepath}
+		add patch --path {filepath} --kind Deployment --label-selector app=web
+		add patch --patch '[{"op": "replace", "path": "/spec/replicas", "value": 3}]' --kind Deployment`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := o.Validate(args)
 			if err != nil {
@@ -35,11 +45,26 @@ func newCmdAddPatch(fSys filesys.FileSystem) *cobra.Command {
 			return o.RunAddPatch(fSys)
 		},
 	}
+	cmd.Flags().StringVar(&o.path, "path", "",
+		"Path of a file holding a patch for the patches field")
+	cmd.Flags().StringVar(&o.patch, "patch", "",
+		"A patch, inline, for the patches field")
+	o.target.AddFlags(cmd.Flags())
 	return cmd
 }
 
 // Validate validates addPatch command.
 func (o *addPatchOptions) Validate(args []string) error {
+	if o.path != "" || o.patch != "" || o.target.IsSet() {
+		if len(args) > 0 {
+			return errors.New(
+				"patch files can't be given as arguments with --path or --patch")
+		}
+		if (o.path == "") == (o.patch == "") {
+			return errors.New("must specify one of --path and --patch")
+		}
+		return nil
+	}
 	if len(args) == 0 {
 		return errors.New("must specify a patch file")
 	}
@@ -49,6 +74,9 @@ func (o *addPatchOptions) Validate(args []string) error {
 
 // RunAddPatch runs addPatch command (do real work).
 func (o *addPatchOptions) RunAddPatch(fSys filesys.FileSystem) error {
+	if o.path != "" || o.patch != "" {
+		return o.addToPatches(fSys)
+	}
 	patches, err := util.GlobPatterns(fSys, o.patchFilePaths)
 	if err != nil {
 		return err
@@ -77,3 +105,31 @@ func (o *addPatchOptions) RunAddPatch(fSys filesys.FileSystem) error {
 
 	return mf.Write(m)
 }
+
+// addToPatches adds a patch to the patches field.
+func (o *addPatchOptions) addToPatches(fSys filesys.FileSystem) error {
+	if o.path != "" && !fSys.Exists(o.path) {
+		return errors.New(o.path + " does not exist")
+	}
+
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	if err != nil {
+		return err
+	}
+
+	m, err := mf.Read()
+	if err != nil {
+		return err
+	}
+
+	p := types.Patch{Path: o.path, Patch: o.patch, Target: o.target.Selector()}
+	for _, q := range m.Patches {
+		if patch.EqualPatches(p, q) {
+			log.Printf("patch already in kustomization file")
+			return nil
+		}
+	}
+	m.Patches = append(m.Patches, p)
+
+	return mf.Write(m)
+}
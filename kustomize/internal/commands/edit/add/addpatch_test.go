@@ -74,3 +74,66 @@ func TestAddPatchNoArgs(t *testing.T) {
 		t.Errorf("incorrect error: %v", err.Error())
 	}
 }
+
+func TestAddPatchWithTarget(t *testing.T) {
+	fSys := filesys.MakeEmptyDirInMemory()
+	fSys.WriteFile(patchFileName, []byte(patchFileContent))
+	testutils_test.WriteTestKustomization(fSys)
+
+	cmd := newCmdAddPatch(fSys)
+	for _, f := range [][2]string{
+		{"path", patchFileName},
+		{"kind", "Deployment"},
+		{"label-selector", "app=web"},
+	} {
+		if err := cmd.Flags().Set(f[0], f[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := cmd.RunE(cmd, nil)
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	// adding an existing patch shouldn't return an error
+	err = cmd.RunE(cmd, nil)
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := `patches:
+- path: myWonderfulPatch.yaml
+  target:
+    kind: Deployment
+    labelSelector: app=web
+`
+	if !strings.Contains(string(content), expected) {
+		t.Errorf("expected\n%s\nin kustomization, got\n%s", expected, content)
+	}
+	if strings.Contains(string(content), "patchesStrategicMerge") {
+		t.Errorf("unexpected patchesStrategicMerge in kustomization")
+	}
+}
+
+func TestAddPatchWithTargetErrors(t *testing.T) {
+	fSys := filesys.MakeEmptyDirInMemory()
+	testutils_test.WriteTestKustomization(fSys)
+
+	cmd := newCmdAddPatch(fSys)
+	cmd.Flags().Set("kind", "Deployment")
+	err := cmd.RunE(cmd, nil)
+	if err == nil || err.Error() != "must specify one of --path and --patch" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	cmd.Flags().Set("path", patchFileName)
+	err = cmd.RunE(cmd, []string{patchFileName})
+	if err == nil || !strings.Contains(err.Error(), "can't be given as arguments") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err = cmd.RunE(cmd, nil)
+	if err == nil || err.Error() != patchFileName+" does not exist" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
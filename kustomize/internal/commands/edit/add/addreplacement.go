@@ -0,0 +1,115 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package add
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/edit/patch"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
+)
+
+type addReplacementOptions struct {
+	sourceAPIVersion string
+	sourceKind       string
+	sourceName       string
+	sourceNamespace  string
+	sourceFieldRef   string
+	sourceValue      string
+	target           patch.TargetFlags
+	targetFieldRefs  []string
+}
+
+// newCmdAddReplacement adds a replacement to the kustomization file.
+func newCmdAddReplacement(fSys filesys.FileSystem) *cobra.Command {
+	var o addReplacementOptions
+
+	cmd := &cobra.Command{
+		Use: "replacement",
+		Short: "Add a replacement, copying a field of one resource, or a value, " +
+			"into fields of others, to the kustomization file.",
+		Example: `
+		add replacement --source-kind Service --source-name web --source-fieldref metadata.name \
+		    --kind Deployment --fieldref spec.template.spec.containers[0].env[0].value`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunAddReplacement(fSys)
+		},
+	}
+	cmd.Flags().StringVar(&o.sourceAPIVersion, "source-api-version", "",
+		"API version of the source resource, e.g. apps/v1")
+	cmd.Flags().StringVar(&o.sourceKind, "source-kind", "",
+		"Kind of the source resource")
+	cmd.Flags().StringVar(&o.sourceName, "source-name", "",
+		"Name of the source resource")
+	cmd.Flags().StringVar(&o.sourceNamespace, "source-namespace", "",
+		"Namespace of the source resource")
+	cmd.Flags().StringVar(&o.sourceFieldRef, "source-fieldref", "",
+		"Path of the field of the source resource")
+	cmd.Flags().StringVar(&o.sourceValue, "source-value", "",
+		"A value to copy, rather than a field of a source resource")
+	o.target.AddFlags(cmd.Flags())
+	cmd.Flags().StringSliceVar(&o.targetFieldRefs, "fieldref", nil,
+		"Paths of the fields of the target resources to replace")
+	return cmd
+}
+
+// Validate validates addReplacement command.
+func (o *addReplacementOptions) Validate(args []string) error {
+	if len(args) > 0 {
+		return errors.New("a replacement takes no arguments, only flags")
+	}
+	if (o.sourceValue == "") == (o.sourceKind == "") {
+		return errors.New("must specify one of --source-kind and --source-value")
+	}
+	if o.sourceKind != "" && o.sourceName == "" {
+		return errors.New("must specify the name of the source resource")
+	}
+	if len(o.targetFieldRefs) == 0 {
+		return errors.New("must specify the fields to replace")
+	}
+	return nil
+}
+
+// RunAddReplacement runs addReplacement command (do real work).
+func (o *addReplacementOptions) RunAddReplacement(fSys filesys.FileSystem) error {
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	if err != nil {
+		return err
+	}
+
+	m, err := mf.Read()
+	if err != nil {
+		return err
+	}
+
+	r := types.Replacement{
+		Source: &types.ReplSource{
+			FieldRef: o.sourceFieldRef,
+			Value:    o.sourceValue,
+		},
+		Target: &types.ReplTarget{
+			ObjRef:    o.target.Selector(),
+			FieldRefs: o.targetFieldRefs,
+		},
+	}
+	if o.sourceKind != "" {
+		r.Source.ObjRef = &types.Target{
+			APIVersion: o.sourceAPIVersion,
+			Gvk:        resid.Gvk{Kind: o.sourceKind},
+			Name:       o.sourceName,
+			Namespace:  o.sourceNamespace,
+		}
+	}
+	m.Replacements = append(m.Replacements, r)
+
+	return mf.Write(m)
+}
@@ -0,0 +1,58 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package add
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	testutils_test "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
+)
+
+func TestAddReplacement(t *testing.T) {
+	fSys := filesys.MakeEmptyDirInMemory()
+	testutils_test.WriteTestKustomization(fSys)
+
+	cmd := newCmdAddReplacement(fSys)
+	err := cmd.RunE(cmd, nil)
+	if err == nil || err.Error() !=
+		"must specify one of --source-kind and --source-value" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, f := range [][2]string{
+		{"source-kind", "Service"},
+		{"source-name", "web"},
+		{"source-fieldref", "metadata.name"},
+		{"kind", "Deployment"},
+		{"fieldref", "spec.template.spec.containers[0].env[0].value"},
+	} {
+		if err = cmd.Flags().Set(f[0], f[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = cmd.RunE(cmd, nil)
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := `replacements:
+- source:
+    fieldref: metadata.name
+    objref:
+      kind: Service
+      name: web
+  target:
+    fieldrefs:
+    - spec.template.spec.containers[0].env[0].value
+    objref:
+      kind: Deployment
+`
+	if !strings.Contains(string(content), expected) {
+		t.Errorf("expected\n%s\nin kustomization, got\n%s", expected, content)
+	}
+}
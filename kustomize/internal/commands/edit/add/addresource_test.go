@@ -74,3 +74,29 @@ func TestAddResourceNoArgs(t *testing.T) {
 		t.Errorf("incorrect error: %v", err.Error())
 	}
 }
+
+func TestAddResourceKeepsFieldOrder(t *testing.T) {
+	fSys := filesys.MakeEmptyDirInMemory()
+	fSys.WriteFile("a.yaml", []byte(resourceFileContent))
+	fSys.WriteFile("b.yaml", []byte(resourceFileContent))
+	testutils_test.WriteTestKustomizationWith(fSys, []byte("resources:\n- a.yaml\n"))
+
+	cmd := newCmdAddResource(fSys)
+	err := cmd.RunE(cmd, []string{"b.yaml"})
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- a.yaml
+- b.yaml
+`
+	if string(content) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, content)
+	}
+}
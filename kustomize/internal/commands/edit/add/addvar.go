@@ -0,0 +1,83 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package add
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
+)
+
+type addVarOptions struct {
+	v types.Var
+}
+
+// newCmdAddVar adds a var to the kustomization file.
+func newCmdAddVar(fSys filesys.FileSystem) *cobra.Command {
+	var o addVarOptions
+
+	cmd := &cobra.Command{
+		Use:   "var NAME --kind KIND --name NAME",
+		Short: "Add a var, referring to a field of a resource, to the kustomization file.",
+		Example: `
+		add var SERVICE_NAME --kind Service --name web
+		add var DB_PORT --api-version apps/v1 --kind StatefulSet --name db --field-path spec.template.spec.containers[0].ports[0].containerPort`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunAddVar(fSys)
+		},
+	}
+	cmd.Flags().StringVar(&o.v.ObjRef.APIVersion, "api-version", "",
+		"API version of the resource, e.g. apps/v1")
+	cmd.Flags().StringVar(&o.v.ObjRef.Kind, "kind", "",
+		"Kind of the resource")
+	cmd.Flags().StringVar(&o.v.ObjRef.Name, "name", "",
+		"Name of the resource")
+	cmd.Flags().StringVar(&o.v.ObjRef.Namespace, "namespace", "",
+		"Namespace of the resource")
+	cmd.Flags().StringVar(&o.v.FieldRef.FieldPath, "field-path", "",
+		"Path of the field of the resource; if not set, metadata.name")
+	return cmd
+}
+
+// Validate validates addVar command.
+func (o *addVarOptions) Validate(args []string) error {
+	if len(args) != 1 {
+		return errors.New("must specify one var name")
+	}
+	o.v.Name = args[0]
+	if o.v.ObjRef.Kind == "" || o.v.ObjRef.Name == "" {
+		return errors.New("must specify the kind and the name of the resource")
+	}
+	return nil
+}
+
+// RunAddVar runs addVar command (do real work).
+func (o *addVarOptions) RunAddVar(fSys filesys.FileSystem) error {
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	if err != nil {
+		return err
+	}
+
+	m, err := mf.Read()
+	if err != nil {
+		return err
+	}
+
+	for _, v := range m.Vars {
+		if v.Name == o.v.Name {
+			return fmt.Errorf("var %s already in kustomization file", v.Name)
+		}
+	}
+	m.Vars = append(m.Vars, o.v)
+
+	return mf.Write(m)
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package add
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	testutils_test "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
+)
+
+func TestAddVar(t *testing.T) {
+	fSys := filesys.MakeEmptyDirInMemory()
+	testutils_test.WriteTestKustomization(fSys)
+
+	cmd := newCmdAddVar(fSys)
+	args := []string{"DB_PORT"}
+	err := cmd.RunE(cmd, args)
+	if err == nil || err.Error() !=
+		"must specify the kind and the name of the resource" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, f := range [][2]string{
+		{"api-version", "apps/v1"},
+		{"kind", "StatefulSet"},
+		{"name", "db"},
+		{"field-path", "spec.template.spec.containers[0].ports[0].containerPort"},
+	} {
+		if err = cmd.Flags().Set(f[0], f[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = cmd.RunE(cmd, args)
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := `vars:
+- fieldref:
+    fieldPath: spec.template.spec.containers[0].ports[0].containerPort
+  name: DB_PORT
+  objref:
+    apiVersion: apps/v1
+    kind: StatefulSet
+    name: db
+`
+	if !strings.Contains(string(content), expected) {
+		t.Errorf("expected\n%s\nin kustomization, got\n%s", expected, content)
+	}
+
+	err = cmd.RunE(cmd, args)
+	if err == nil || err.Error() != "var DB_PORT already in kustomization file" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/kustomize/api/filesys"
 	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/edit/filelist"
 )
 
 // NewCmdAdd returns an instance of 'add' subcommand.
@@ -31,6 +32,29 @@ func NewCmdAdd(
 	# Adds a patch to the kustomization
 	kustomize edit add patch <filepath>
 
+	# Adds a patch, with a target selector, to the kustomization
+	kustomize edit add patch --path <filepath> --kind Deployment --label-selector app=web
+
+	# Adds a JSON patch, with its target, to the kustomization
+	kustomize edit add json6902 <filepath> --version v1 --kind Service --name web
+
+	# Adds one or more components, CRDs, transformer configurations,
+	# generators or transformers to the kustomization
+	kustomize edit add component <dirpath>
+	kustomize edit add crd <filepath>
+	kustomize edit add configuration <filepath>
+	kustomize edit add generator <filepath>
+	kustomize edit add transformer <filepath>
+
+	# Adds a var to the kustomization
+	kustomize edit add var NAME --kind Service --name web
+
+	# Adds a replacement to the kustomization
+	kustomize edit add replacement --source-kind Service --source-name web --kind Deployment --fieldref <fieldpath>
+
+	# Adds a helm chart to the kustomization
+	kustomize edit add helmchart <chart> --release-name <name>
+
 	# Adds one or more base directories to the kustomization
 	kustomize edit add base <filepath>
 	kustomize edit add base <filepath1>,<filepath2>,<filepath3>
@@ -51,6 +75,13 @@ func NewCmdAdd(
 		newCmdAddBase(fSys),
 		newCmdAddLabel(fSys, ldr.Validator().MakeLabelValidator()),
 		newCmdAddAnnotation(fSys, ldr.Validator().MakeAnnotationValidator()),
+		newCmdAddJson6902(fSys),
+		newCmdAddVar(fSys),
+		newCmdAddReplacement(fSys),
+		newCmdAddHelmChart(fSys),
 	)
+	for _, f := range filelist.Fields {
+		c.AddCommand(newCmdAddFileList(fSys, f))
+	}
 	return c
 }
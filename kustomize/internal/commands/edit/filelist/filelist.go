@@ -0,0 +1,49 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package filelist describes the kustomization fields
+// that list paths of files, or directories, and nothing
+// else, so that they may all be edited alike.
+package filelist
+
+import "sigs.k8s.io/kustomize/api/types"
+
+// Field is a kustomization field listing paths.
+type Field struct {
+	// Name of one item, as in edit commands, e.g. crd.
+	Name string
+	// What an item holds, e.g. "a CRD".
+	What string
+	// Get returns the field of a kustomization.
+	Get func(m *types.Kustomization) *[]string
+}
+
+// Fields are the fields edited alike; resources and
+// bases, having their own commands, aren't among them.
+var Fields = []Field{
+	{
+		Name: "component",
+		What: "a component",
+		Get:  func(m *types.Kustomization) *[]string { return &m.Components },
+	},
+	{
+		Name: "crd",
+		What: "a CRD",
+		Get:  func(m *types.Kustomization) *[]string { return &m.Crds },
+	},
+	{
+		Name: "configuration",
+		What: "transformer configurations",
+		Get:  func(m *types.Kustomization) *[]string { return &m.Configurations },
+	},
+	{
+		Name: "generator",
+		What: "generator plugin configurations",
+		Get:  func(m *types.Kustomization) *[]string { return &m.Generators },
+	},
+	{
+		Name: "transformer",
+		What: "transformer plugin configurations",
+		Get:  func(m *types.Kustomization) *[]string { return &m.Transformers },
+	},
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package patch
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// TargetFlags select the resources a patch applies to.
+type TargetFlags struct {
+	Group              string
+	Version            string
+	Kind               string
+	Name               string
+	Namespace          string
+	LabelSelector      string
+	AnnotationSelector string
+}
+
+// AddFlags adds the flags to set.
+func (f *TargetFlags) AddFlags(set *pflag.FlagSet) {
+	set.StringVar(&f.Group, "group", "", "API group of the target resources")
+	set.StringVar(&f.Version, "version", "", "API version of the target resources")
+	set.StringVar(&f.Kind, "kind", "", "Kind of the target resources")
+	set.StringVar(&f.Name, "name", "", "Name of the target resources")
+	set.StringVar(&f.Namespace, "namespace", "", "Namespace of the target resources")
+	set.StringVar(&f.LabelSelector, "label-selector", "",
+		"Label selector of the target resources, e.g. app=web")
+	set.StringVar(&f.AnnotationSelector, "annotation-selector", "",
+		"Annotation selector of the target resources")
+}
+
+// IsSet is true if any flag is set.
+func (f *TargetFlags) IsSet() bool {
+	return *f != TargetFlags{}
+}
+
+// Selector returns the target as a selector,
+// nil if no flag is set.
+func (f *TargetFlags) Selector() *types.Selector {
+	if !f.IsSet() {
+		return nil
+	}
+	return &types.Selector{
+		Gvk: resid.Gvk{
+			Group: f.Group, Version: f.Version, Kind: f.Kind},
+		Name:               f.Name,
+		Namespace:          f.Namespace,
+		LabelSelector:      f.LabelSelector,
+		AnnotationSelector: f.AnnotationSelector,
+	}
+}
+
+// PatchTarget returns the target of a JSON patch,
+// which names one resource.
+func (f *TargetFlags) PatchTarget() (*types.PatchTarget, error) {
+	if f.LabelSelector != "" || f.AnnotationSelector != "" {
+		return nil, errors.New(
+			"the target of a JSON patch can't be selected by labels or annotations")
+	}
+	if f.Version == "" || f.Kind == "" || f.Name == "" {
+		return nil, errors.New(
+			"the target of a JSON patch needs a version, a kind and a name")
+	}
+	return &types.PatchTarget{
+		Gvk: resid.Gvk{
+			Group: f.Group, Version: f.Version, Kind: f.Kind},
+		Name:      f.Name,
+		Namespace: f.Namespace,
+	}, nil
+}
+
+// Matches is true if the flags that are set equal
+// the fields of s.
+func (f *TargetFlags) Matches(s *types.Selector) bool {
+	if s == nil {
+		return !f.IsSet()
+	}
+	for _, x := range [][2]string{
+		{f.Group, s.Group},
+		{f.Version, s.Version},
+		{f.Kind, s.Kind},
+		{f.Name, s.Name},
+		{f.Namespace, s.Namespace},
+		{f.LabelSelector, s.LabelSelector},
+		{f.AnnotationSelector, s.AnnotationSelector},
+	} {
+		if x[0] != "" && x[0] != x[1] {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualPatches is true if a and b are the same patch,
+// with the same target.
+func EqualPatches(a, b types.Patch) bool {
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package patch
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func TestTargetFlagsMatches(t *testing.T) {
+	s := &types.Selector{
+		Gvk:           resid.Gvk{Group: "apps", Version: "v1", Kind: "Deployment"},
+		Name:          "web",
+		LabelSelector: "app=web",
+	}
+	testCases := []struct {
+		flags    TargetFlags
+		selector *types.Selector
+		expected bool
+	}{
+		{flags: TargetFlags{}, selector: s, expected: true},
+		{flags: TargetFlags{}, selector: nil, expected: true},
+		{flags: TargetFlags{Kind: "Deployment"}, selector: s, expected: true},
+		{flags: TargetFlags{Kind: "Deployment"}, selector: nil, expected: false},
+		{flags: TargetFlags{Kind: "Deployment", Name: "db"}, selector: s, expected: false},
+		{flags: TargetFlags{LabelSelector: "app=web"}, selector: s, expected: true},
+	}
+	for i, tc := range testCases {
+		if actual := tc.flags.Matches(tc.selector); actual != tc.expected {
+			t.Errorf("%d: expected %v, got %v", i, tc.expected, actual)
+		}
+	}
+}
+
+func TestTargetFlagsPatchTarget(t *testing.T) {
+	f := TargetFlags{Version: "v1", Kind: "Service", Name: "web", Namespace: "prod"}
+	target, err := f.PatchTarget()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := types.PatchTarget{
+		Gvk: resid.Gvk{Version: "v1", Kind: "Service"}, Name: "web", Namespace: "prod"}
+	if *target != expected {
+		t.Errorf("expected %v, got %v", expected, *target)
+	}
+	f.LabelSelector = "app=web"
+	if _, err = f.PatchTarget(); err == nil {
+		t.Errorf("expected an error")
+	}
+	if _, err = (&TargetFlags{Kind: "Service"}).PatchTarget(); err == nil {
+		t.Errorf("expected an error")
+	}
+}
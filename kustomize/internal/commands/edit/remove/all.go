@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/kustomize/api/filesys"
 	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/edit/filelist"
 )
 
 // NewCmdRemove returns an instance of 'remove' subcommand.
@@ -25,6 +26,33 @@ func NewCmdRemove(
 	# Removes one or more patches from the kustomization file
 	kustomize edit remove patch <filepath>
 
+	# Removes patches, selected by path or target, from the patches field
+	kustomize edit remove patch --path <filepath>
+	kustomize edit remove patch --kind Deployment --label-selector app=web
+
+	# Removes one or more JSON patches from the kustomization file
+	kustomize edit remove json6902 <filepath>
+
+	# Removes components, CRDs, transformer configurations,
+	# generators or transformers from the kustomization file
+	kustomize edit remove component <dirpath>
+	kustomize edit remove crd <filepath>
+	kustomize edit remove configuration <filepath>
+	kustomize edit remove generator <filepath>
+	kustomize edit remove transformer <filepath>
+
+	# Removes vars, generated configmaps or secrets, images,
+	# replicas or helm charts, by name, from the kustomization file
+	kustomize edit remove var <name>
+	kustomize edit remove configmap <name>
+	kustomize edit remove secret <name>
+	kustomize edit remove image <name>
+	kustomize edit remove replicas <name>
+	kustomize edit remove helmchart <name>
+
+	# Removes replacements, by source, from the kustomization file
+	kustomize edit remove replacement --source-kind Service --source-name web
+
 	# Removes one or more commonLabels from the kustomization file
 	kustomize edit remove label {labelKey1},{labelKey2}
 
@@ -38,6 +66,14 @@ func NewCmdRemove(
 		newCmdRemoveLabel(fSys, v.MakeLabelNameValidator()),
 		newCmdRemoveAnnotation(fSys, v.MakeAnnotationNameValidator()),
 		newCmdRemovePatch(fSys),
+		newCmdRemoveJson6902(fSys),
+		newCmdRemoveReplacement(fSys),
 	)
+	for _, f := range filelist.Fields {
+		c.AddCommand(newCmdRemoveFileList(fSys, f))
+	}
+	for _, f := range namedFields {
+		c.AddCommand(newCmdRemoveNamed(fSys, f))
+	}
 	return c
 }
@@ -0,0 +1,86 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package remove
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/edit/filelist"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
+)
+
+type removeFileListOptions struct {
+	field    filelist.Field
+	patterns []string
+}
+
+// newCmdRemoveFileList removes paths of files, or directories,
+// from a list of them in the kustomization file.
+func newCmdRemoveFileList(fSys filesys.FileSystem, f filelist.Field) *cobra.Command {
+	o := removeFileListOptions{field: f}
+
+	cmd := &cobra.Command{
+		Use: f.Name,
+		Short: "Removes one or more paths of files, or directories, holding " +
+			f.What + " from " + konfig.DefaultKustomizationFileName(),
+		Example: `
+		remove ` + f.Name + ` {filepath} {filepath}
+		remove ` + f.Name + ` {pattern}
+		`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunRemoveFileList(fSys)
+		},
+	}
+	return cmd
+}
+
+// Validate validates removeFileList command.
+func (o *removeFileListOptions) Validate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("must specify a %s file", o.field.Name)
+	}
+	o.patterns = args
+	return nil
+}
+
+// RunRemoveFileList runs removeFileList command (do real work).
+func (o *removeFileListOptions) RunRemoveFileList(fSys filesys.FileSystem) error {
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	if err != nil {
+		return err
+	}
+
+	m, err := mf.Read()
+	if err != nil {
+		return err
+	}
+
+	field := o.field.Get(m)
+	paths, err := globPatterns(*field, o.patterns)
+	if err != nil {
+		return err
+	}
+
+	if len(paths) == 0 {
+		return nil
+	}
+
+	kept := make([]string, 0, len(*field))
+	for _, p := range *field {
+		if kustfile.StringInSlice(p, paths) {
+			continue
+		}
+		kept = append(kept, p)
+	}
+
+	*field = kept
+	return mf.Write(m)
+}
@@ -0,0 +1,74 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package remove
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/edit/filelist"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
+	testutils_test "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
+)
+
+func TestRemoveFileList(t *testing.T) {
+	for _, f := range filelist.Fields {
+		t.Run(f.Name, func(t *testing.T) {
+			fSys := filesys.MakeFsInMemory()
+			testutils_test.WriteTestKustomizationWith(fSys, []byte(`
+# The resources are kept.
+resources:
+- foo/r.yaml
+components:
+- foo/a
+- bar
+crds:
+- foo/a
+- bar
+configurations:
+- foo/a
+- bar
+generators:
+- foo/a
+- bar
+transformers:
+- foo/a
+- bar
+`))
+			cmd := newCmdRemoveFileList(fSys, f)
+			err := cmd.RunE(cmd, []string{"foo/*", "baz"})
+			if err != nil {
+				t.Fatalf("unexpected cmd error: %v", err)
+			}
+			mf, err := kustfile.NewKustomizationFile(fSys)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			m, err := mf.Read()
+			if err != nil {
+				t.Fatalf("unexpected read error: %v", err)
+			}
+			if !reflect.DeepEqual(*f.Get(m), []string{"bar"}) {
+				t.Errorf("unexpected %s: %v", f.Name, *f.Get(m))
+			}
+			for _, g := range filelist.Fields {
+				if g.Name != f.Name && len(*g.Get(m)) != 2 {
+					t.Errorf("unexpected %s: %v", g.Name, *g.Get(m))
+				}
+			}
+			if !reflect.DeepEqual(m.Resources, []string{"foo/r.yaml"}) {
+				t.Errorf("unexpected resources: %v", m.Resources)
+			}
+			content, err := testutils_test.ReadTestKustomization(fSys)
+			if err != nil {
+				t.Fatalf("unexpected read error: %v", err)
+			}
+			if !strings.Contains(string(content), "# The resources are kept.\n") {
+				t.Errorf("expected comment in kustomization file:\n%s", content)
+			}
+		})
+	}
+}
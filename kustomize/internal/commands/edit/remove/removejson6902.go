@@ -0,0 +1,98 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package remove
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/edit/patch"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
+)
+
+type removeJson6902Options struct {
+	patterns []string
+	target   patch.TargetFlags
+}
+
+// newCmdRemoveJson6902 removes JSON patches from the
+// patchesJson6902 field of the kustomization file.
+func newCmdRemoveJson6902(fSys filesys.FileSystem) *cobra.Command {
+	var o removeJson6902Options
+
+	cmd := &cobra.Command{
+		Use: "json6902",
+		Short: "Removes one or more JSON patches from " +
+			konfig.DefaultKustomizationFileName(),
+		Example: `
+		remove json6902 {filepath}
+		remove json6902 {pattern} --kind Deployment --name web`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunRemoveJson6902(fSys)
+		},
+	}
+	o.target.AddFlags(cmd.Flags())
+	return cmd
+}
+
+// Validate validates removeJson6902 command.
+func (o *removeJson6902Options) Validate(args []string) error {
+	if len(args) == 0 {
+		return errors.New("must specify a patch file")
+	}
+	o.patterns = args
+	return nil
+}
+
+// RunRemoveJson6902 runs removeJson6902 command (do real work).
+func (o *removeJson6902Options) RunRemoveJson6902(fSys filesys.FileSystem) error {
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	if err != nil {
+		return err
+	}
+
+	m, err := mf.Read()
+	if err != nil {
+		return err
+	}
+
+	kept := make([]types.PatchJson6902, 0, len(m.PatchesJson6902))
+	for _, p := range m.PatchesJson6902 {
+		match, err := o.matches(p)
+		if err != nil {
+			return err
+		}
+		if !match {
+			kept = append(kept, p)
+		}
+	}
+	if len(kept) == len(m.PatchesJson6902) {
+		return nil
+	}
+
+	m.PatchesJson6902 = kept
+	return mf.Write(m)
+}
+
+func (o *removeJson6902Options) matches(p types.PatchJson6902) (bool, error) {
+	if p.Target != nil && !o.target.Matches(&types.Selector{
+		Gvk: p.Target.Gvk, Name: p.Target.Name, Namespace: p.Target.Namespace}) {
+		return false, nil
+	}
+	for _, pattern := range o.patterns {
+		match, err := filepath.Match(pattern, p.Path)
+		if err != nil || match {
+			return match, err
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package remove
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	testutils_test "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
+)
+
+func TestRemoveJson6902(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	testutils_test.WriteTestKustomizationWith(fSys, []byte(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+patchesJson6902:
+- path: patch1.json
+  target:
+    kind: Deployment
+    name: web
+    version: v1
+- path: patch1.json
+  target:
+    kind: Service
+    name: web
+    version: v1
+- path: patch2.json
+  target:
+    kind: Service
+    name: web
+    version: v1
+`))
+	cmd := newCmdRemoveJson6902(fSys)
+	cmd.Flags().Set("kind", "Service")
+	err := cmd.RunE(cmd, []string{"patch*.json"})
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+patchesJson6902:
+- path: patch1.json
+  target:
+    kind: Deployment
+    name: web
+    version: v1
+`
+	if string(content) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, content)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package remove
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
+)
+
+// namedField is a kustomization field listing
+// entries known by name, e.g. vars.
+type namedField struct {
+	// Name of the command, and of one entry, e.g. var.
+	name string
+	// What the name names, e.g. "the name of a var".
+	what string
+	// filter drops the entries whose names match,
+	// returning how many it dropped.
+	filter func(m *types.Kustomization, match func(string) bool) int
+}
+
+var namedFields = []namedField{
+	{
+		name: "var",
+		what: "the name of a var",
+		filter: func(m *types.Kustomization, match func(string) bool) int {
+			kept := m.Vars[:0]
+			for _, v := range m.Vars {
+				if !match(v.Name) {
+					kept = append(kept, v)
+				}
+			}
+			n := len(m.Vars) - len(kept)
+			m.Vars = kept
+			return n
+		},
+	},
+	{
+		name: "configmap",
+		what: "the name of a generated configmap",
+		filter: func(m *types.Kustomization, match func(string) bool) int {
+			kept := m.ConfigMapGenerator[:0]
+			for _, a := range m.ConfigMapGenerator {
+				if !match(a.Name) {
+					kept = append(kept, a)
+				}
+			}
+			n := len(m.ConfigMapGenerator) - len(kept)
+			m.ConfigMapGenerator = kept
+			return n
+		},
+	},
+	{
+		name: "secret",
+		what: "the name of a generated secret",
+		filter: func(m *types.Kustomization, match func(string) bool) int {
+			kept := m.SecretGenerator[:0]
+			for _, a := range m.SecretGenerator {
+				if !match(a.Name) {
+					kept = append(kept, a)
+				}
+			}
+			n := len(m.SecretGenerator) - len(kept)
+			m.SecretGenerator = kept
+			return n
+		},
+	},
+	{
+		name: "image",
+		what: "the name of an image",
+		filter: func(m *types.Kustomization, match func(string) bool) int {
+			kept := m.Images[:0]
+			for _, im := range m.Images {
+				if !match(im.Name) {
+					kept = append(kept, im)
+				}
+			}
+			n := len(m.Images) - len(kept)
+			m.Images = kept
+			return n
+		},
+	},
+	{
+		name: "replicas",
+		what: "the name of a resource whose replicas are set",
+		filter: func(m *types.Kustomization, match func(string) bool) int {
+			kept := m.Replicas[:0]
+			for _, r := range m.Replicas {
+				if !match(r.Name) {
+					kept = append(kept, r)
+				}
+			}
+			n := len(m.Replicas) - len(kept)
+			m.Replicas = kept
+			return n
+		},
+	},
+	{
+		name: "helmchart",
+		what: "the release name, else the chart, of a helm chart",
+		filter: func(m *types.Kustomization, match func(string) bool) int {
+			kept := m.HelmCharts[:0]
+			for _, c := range m.HelmCharts {
+				name := c.ReleaseName
+				if name == "" {
+					name = c.Chart
+				}
+				if !match(name) {
+					kept = append(kept, c)
+				}
+			}
+			n := len(m.HelmCharts) - len(kept)
+			m.HelmCharts = kept
+			return n
+		},
+	},
+}
+
+type removeNamedOptions struct {
+	field    namedField
+	patterns []string
+}
+
+// newCmdRemoveNamed removes entries, by name, from the kustomization file.
+func newCmdRemoveNamed(fSys filesys.FileSystem, f namedField) *cobra.Command {
+	o := removeNamedOptions{field: f}
+
+	cmd := &cobra.Command{
+		Use: f.name + " NAME...",
+		Short: "Removes the entries with the given names, " + f.what +
+			", or patterns, from " + konfig.DefaultKustomizationFileName(),
+		Example: `
+		remove ` + f.name + ` {name} {name}
+		remove ` + f.name + ` {pattern}
+		`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunRemoveNamed(fSys)
+		},
+	}
+	return cmd
+}
+
+// Validate validates removeNamed command.
+func (o *removeNamedOptions) Validate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("must specify %s", o.field.what)
+	}
+	for _, p := range args {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return err
+		}
+	}
+	o.patterns = args
+	return nil
+}
+
+// RunRemoveNamed runs removeNamed command (do real work).
+func (o *removeNamedOptions) RunRemoveNamed(fSys filesys.FileSystem) error {
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	if err != nil {
+		return err
+	}
+
+	m, err := mf.Read()
+	if err != nil {
+		return err
+	}
+
+	n := o.field.filter(m, func(name string) bool {
+		for _, p := range o.patterns {
+			if match, _ := filepath.Match(p, name); match {
+				return true
+			}
+		}
+		return false
+	})
+	if n == 0 {
+		log.Printf("no %s %v in kustomization file", o.field.name, o.patterns)
+		return nil
+	}
+
+	return mf.Write(m)
+}
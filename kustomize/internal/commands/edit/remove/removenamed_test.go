@@ -0,0 +1,177 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package remove
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	testutils_test "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
+)
+
+func TestRemoveNamed(t *testing.T) {
+	const kustomization = `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+configMapGenerator:
+- literals:
+  - k=v
+  name: web-config
+- literals:
+  - k=v
+  name: db-config
+secretGenerator:
+- literals:
+  - k=v
+  name: web-secret
+- literals:
+  - k=v
+  name: db-secret
+helmCharts:
+- chart: ./charts/minecraft
+  releaseName: moria
+- chart: ./charts/web
+vars:
+- fieldref:
+    fieldPath: metadata.name
+  name: WEB
+  objref:
+    kind: Service
+    name: web
+- fieldref:
+    fieldPath: metadata.name
+  name: DB
+  objref:
+    kind: Service
+    name: db
+images:
+- name: web
+  newTag: v1
+- name: db
+  newTag: v2
+replicas:
+- count: 1
+  name: web
+- count: 2
+  name: db
+`
+	expected := map[string]string{
+		"var": `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+configMapGenerator:
+- literals:
+  - k=v
+  name: web-config
+- literals:
+  - k=v
+  name: db-config
+secretGenerator:
+- literals:
+  - k=v
+  name: web-secret
+- literals:
+  - k=v
+  name: db-secret
+helmCharts:
+- chart: ./charts/minecraft
+  releaseName: moria
+- chart: ./charts/web
+vars:
+- fieldref:
+    fieldPath: metadata.name
+  name: DB
+  objref:
+    kind: Service
+    name: db
+images:
+- name: web
+  newTag: v1
+- name: db
+  newTag: v2
+replicas:
+- count: 1
+  name: web
+- count: 2
+  name: db
+`,
+		"configmap": `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+configMapGenerator:
+- literals:
+  - k=v
+  name: db-config
+secretGenerator:
+- literals:
+  - k=v
+  name: web-secret
+- literals:
+  - k=v
+  name: db-secret
+helmCharts:
+- chart: ./charts/minecraft
+  releaseName: moria
+- chart: ./charts/web
+vars:
+- fieldref:
+    fieldPath: metadata.name
+  name: WEB
+  objref:
+    kind: Service
+    name: web
+- fieldref:
+    fieldPath: metadata.name
+  name: DB
+  objref:
+    kind: Service
+    name: db
+images:
+- name: web
+  newTag: v1
+- name: db
+  newTag: v2
+replicas:
+- count: 1
+  name: web
+- count: 2
+  name: db
+`,
+	}
+	args := map[string][]string{
+		"var":       {"WEB"},
+		"configmap": {"web-*"},
+		"secret":    {"web-*", "db-*"},
+		"image":     {"db"},
+		"replicas":  {"web", "db"},
+		"helmchart": {"./charts/web", "moria"},
+	}
+	for _, f := range namedFields {
+		t.Run(f.name, func(t *testing.T) {
+			fSys := filesys.MakeFsInMemory()
+			testutils_test.WriteTestKustomizationWith(fSys, []byte(kustomization))
+			cmd := newCmdRemoveNamed(fSys, f)
+			err := cmd.RunE(cmd, args[f.name])
+			if err != nil {
+				t.Fatalf("unexpected cmd error: %v", err)
+			}
+			content, err := testutils_test.ReadTestKustomization(fSys)
+			if err != nil {
+				t.Fatalf("unexpected read error: %v", err)
+			}
+			if e, ok := expected[f.name]; ok && string(content) != e {
+				t.Errorf("expected\n%s\ngot\n%s", e, content)
+			}
+			if string(content) == kustomization {
+				t.Errorf("expected %s %v to be removed", f.name, args[f.name])
+			}
+		})
+	}
+}
+
+func TestRemoveNamedNoArgs(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	cmd := newCmdRemoveNamed(fSys, namedFields[0])
+	err := cmd.RunE(cmd, nil)
+	if err == nil || err.Error() != "must specify the name of a var" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/kustomize/api/filesys"
 	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/edit/patch"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/util"
@@ -17,6 +18,9 @@ import (
 
 type removePatchOptions struct {
 	patchFilePaths []string
+	path           string
+	patch          string
+	target         patch.TargetFlags
 }
 
 // newCmdRemovePatch removes the name of a file containing a patch from the kustomization file.
@@ -27,8 +31,14 @@ func newCmdRemovePatch(fSys filesys.FileSystem) *cobra.Command {
 		Use: "patch",
 		Short: "Removes one or more patches from " +
 			konfig.DefaultKustomizationFileName(),
+		Long: `Without flags, removes the paths of files holding strategic merge
+patches from the patchesStrategicMerge field.  With --path, --patch
+or target flags, removes the patches of the patches field that have
+the given path, or inline patch, and the given target fields.`,
 		Example: `
-		remove patch {filepath}`,
+		remove patch {filepath}
+		remove patch --path {filepath}
+		remove patch --kind Deployment --label-selector app=web`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := o.Validate(args)
 			if err != nil {
@@ -37,11 +47,23 @@ func newCmdRemovePatch(fSys filesys.FileSystem) *cobra.Command {
 			return o.RunRemovePatch(fSys)
 		},
 	}
+	cmd.Flags().StringVar(&o.path, "path", "",
+		"Path of a file holding a patch of the patches field")
+	cmd.Flags().StringVar(&o.patch, "patch", "",
+		"A patch, inline, of the patches field")
+	o.target.AddFlags(cmd.Flags())
 	return cmd
 }
 
 // Validate validates removePatch command.
 func (o *removePatchOptions) Validate(args []string) error {
+	if o.isForPatches() {
+		if len(args) > 0 {
+			return errors.New(
+				"patch files can't be given as arguments with flags")
+		}
+		return nil
+	}
 	if len(args) == 0 {
 		return errors.New("must specify a patch file")
 	}
@@ -51,6 +73,9 @@ func (o *removePatchOptions) Validate(args []string) error {
 
 // RunRemovePatch runs removePatch command (do real work).
 func (o *removePatchOptions) RunRemovePatch(fSys filesys.FileSystem) error {
+	if o.isForPatches() {
+		return o.removeFromPatches(fSys)
+	}
 	patches, err := util.GlobPatterns(fSys, o.patchFilePaths)
 	if err != nil {
 		return err
@@ -81,3 +106,37 @@ func (o *removePatchOptions) RunRemovePatch(fSys filesys.FileSystem) error {
 
 	return mf.Write(m)
 }
+
+func (o *removePatchOptions) isForPatches() bool {
+	return o.path != "" || o.patch != "" || o.target.IsSet()
+}
+
+// removeFromPatches removes patches from the patches field.
+func (o *removePatchOptions) removeFromPatches(fSys filesys.FileSystem) error {
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	if err != nil {
+		return err
+	}
+
+	m, err := mf.Read()
+	if err != nil {
+		return err
+	}
+
+	kept := make([]types.Patch, 0, len(m.Patches))
+	for _, p := range m.Patches {
+		if (o.path == "" || o.path == p.Path) &&
+			(o.patch == "" || o.patch == p.Patch) &&
+			o.target.Matches(p.Target) {
+			continue
+		}
+		kept = append(kept, p)
+	}
+	if len(kept) == len(m.Patches) {
+		log.Printf("no such patch in kustomization file")
+		return nil
+	}
+
+	m.Patches = kept
+	return mf.Write(m)
+}
@@ -135,3 +135,65 @@ func TestRemovePatchNoArgs(t *testing.T) {
 		t.Errorf("incorrect error: %v", err.Error())
 	}
 }
+
+func TestRemovePatchWithTarget(t *testing.T) {
+	fSys := filesys.MakeEmptyDirInMemory()
+	testutils_test.WriteTestKustomizationWith(fSys, []byte(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+patchesStrategicMerge:
+- patch1.yaml
+patches:
+- path: patch1.yaml
+  target:
+    kind: Deployment
+    labelSelector: app=web
+- path: patch1.yaml
+  target:
+    kind: Service
+- path: patch2.yaml
+`))
+	cmd := newCmdRemovePatch(fSys)
+	cmd.Flags().Set("kind", "Deployment")
+	err := cmd.RunE(cmd, nil)
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+patchesStrategicMerge:
+- patch1.yaml
+patches:
+- path: patch1.yaml
+  target:
+    kind: Service
+- path: patch2.yaml
+`
+	if string(content) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, content)
+	}
+
+	cmd = newCmdRemovePatch(fSys)
+	cmd.Flags().Set("path", "patch1.yaml")
+	err = cmd.RunE(cmd, nil)
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	content, err = testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected = `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+patchesStrategicMerge:
+- patch1.yaml
+patches:
+- path: patch2.yaml
+`
+	if string(content) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, content)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package remove
+
+import (
+	"errors"
+	"log"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
+)
+
+type removeReplacementOptions struct {
+	sourceKind     string
+	sourceName     string
+	sourceFieldRef string
+	sourceValue    string
+}
+
+// newCmdRemoveReplacement removes replacements, by source,
+// from the kustomization file.
+func newCmdRemoveReplacement(fSys filesys.FileSystem) *cobra.Command {
+	var o removeReplacementOptions
+
+	cmd := &cobra.Command{
+		Use: "replacement",
+		Short: "Removes the replacements with the given source from " +
+			konfig.DefaultKustomizationFileName(),
+		Example: `
+		remove replacement --source-kind Service --source-name web
+		remove replacement --source-value 3`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunRemoveReplacement(fSys)
+		},
+	}
+	cmd.Flags().StringVar(&o.sourceKind, "source-kind", "",
+		"Kind of the source resource")
+	cmd.Flags().StringVar(&o.sourceName, "source-name", "",
+		"Name of the source resource")
+	cmd.Flags().StringVar(&o.sourceFieldRef, "source-fieldref", "",
+		"Path of the field of the source resource")
+	cmd.Flags().StringVar(&o.sourceValue, "source-value", "",
+		"Value of the source")
+	return cmd
+}
+
+// Validate validates removeReplacement command.
+func (o *removeReplacementOptions) Validate(args []string) error {
+	if len(args) > 0 {
+		return errors.New("replacements are removed by flags, not arguments")
+	}
+	if *o == (removeReplacementOptions{}) {
+		return errors.New("must specify the source of the replacements")
+	}
+	return nil
+}
+
+// RunRemoveReplacement runs removeReplacement command (do real work).
+func (o *removeReplacementOptions) RunRemoveReplacement(fSys filesys.FileSystem) error {
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	if err != nil {
+		return err
+	}
+
+	m, err := mf.Read()
+	if err != nil {
+		return err
+	}
+
+	kept := make([]types.Replacement, 0, len(m.Replacements))
+	for _, r := range m.Replacements {
+		if !o.matches(r.Source) {
+			kept = append(kept, r)
+		}
+	}
+	if len(kept) == len(m.Replacements) {
+		log.Printf("no such replacement in kustomization file")
+		return nil
+	}
+
+	m.Replacements = kept
+	return mf.Write(m)
+}
+
+func (o *removeReplacementOptions) matches(s *types.ReplSource) bool {
+	if s == nil {
+		return false
+	}
+	var kind, name string
+	if s.ObjRef != nil {
+		kind, name = s.ObjRef.Kind, s.ObjRef.Name
+	}
+	for _, x := range [][2]string{
+		{o.sourceKind, kind},
+		{o.sourceName, name},
+		{o.sourceFieldRef, s.FieldRef},
+		{o.sourceValue, s.Value},
+	} {
+		if x[0] != "" && x[0] != x[1] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package remove
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	testutils_test "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
+)
+
+func TestRemoveReplacement(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	testutils_test.WriteTestKustomizationWith(fSys, []byte(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+replacements:
+- source:
+    fieldref: metadata.name
+    objref:
+      kind: Service
+      name: web
+  target:
+    fieldrefs:
+    - spec.serviceName
+- source:
+    value: "3"
+  target:
+    fieldrefs:
+    - spec.replicas
+`))
+	cmd := newCmdRemoveReplacement(fSys)
+	err := cmd.RunE(cmd, nil)
+	if err == nil || err.Error() != "must specify the source of the replacements" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cmd.Flags().Set("source-kind", "Service")
+	err = cmd.RunE(cmd, nil)
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+replacements:
+- source:
+    value: "3"
+  target:
+    fieldrefs:
+    - spec.replicas
+`
+	if string(content) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, content)
+	}
+}
@@ -21,6 +21,16 @@ func NewCmdSet(fSys filesys.FileSystem, v ifc.Validator) *cobra.Command {
 
 	# Sets the namesuffix field
 	kustomize edit set namesuffix <suffix-value>
+
+	# Edits an existing configmap, or secret, generator
+	kustomize edit set configmap <name> --from-literal=k=v --behavior=merge
+	kustomize edit set secret <name> --remove-key=k
+
+	# Sets the generatorOptions field
+	kustomize edit set generatoroptions --disable-name-suffix-hash
+
+	# Sets the inventory field
+	kustomize edit set inventory <name> --namespace <namespace>
 `,
 		Args: cobra.MinimumNArgs(1),
 	}
@@ -31,6 +41,10 @@ func NewCmdSet(fSys filesys.FileSystem, v ifc.Validator) *cobra.Command {
 		newCmdSetNamespace(fSys, v),
 		newCmdSetImage(fSys),
 		newCmdSetReplicas(fSys),
+		newCmdSetConfigMap(fSys),
+		newCmdSetSecret(fSys),
+		newCmdSetGeneratorOptions(fSys),
+		newCmdSetInventory(fSys),
 	)
 	return c
 }
@@ -0,0 +1,178 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package set
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
+)
+
+type setGeneratedOptions struct {
+	kind                  string
+	name                  string
+	literals              []string
+	removeKeys            []string
+	behavior              string
+	namespace             string
+	secretType            string
+	disableNameSuffixHash bool
+	cmd                   *cobra.Command
+}
+
+// newCmdSetConfigMap edits a configmap generator of the kustomization.
+func newCmdSetConfigMap(fSys filesys.FileSystem) *cobra.Command {
+	return newCmdSetGenerated(fSys, "configmap")
+}
+
+// newCmdSetSecret edits a secret generator of the kustomization.
+func newCmdSetSecret(fSys filesys.FileSystem) *cobra.Command {
+	return newCmdSetGenerated(fSys, "secret")
+}
+
+func newCmdSetGenerated(fSys filesys.FileSystem, kind string) *cobra.Command {
+	o := setGeneratedOptions{kind: kind}
+
+	cmd := &cobra.Command{
+		Use:   kind + " NAME",
+		Short: "Edits an existing " + kind + " generator of the kustomization file",
+		Example: `
+The command
+	set ` + kind + ` my-` + kind + ` --from-literal=key1=value1 --remove-key=key2 --behavior=merge
+sets the literal key1 of the generator named my-` + kind + ` to value1,
+replacing any literal with that key, removes the literal key2, and
+sets the behavior of the generator to merge.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunSetGenerated(fSys)
+		},
+	}
+	o.cmd = cmd
+	cmd.Flags().StringArrayVar(&o.literals, "from-literal", nil,
+		"A key and a literal value to set, replacing any with that key (i.e. mykey=somevalue)")
+	cmd.Flags().StringSliceVar(&o.removeKeys, "remove-key", nil,
+		"Keys of literals to remove")
+	cmd.Flags().StringVar(&o.behavior, "behavior", "",
+		"How to handle a "+kind+" of the same name in a base: create, merge or replace")
+	cmd.Flags().StringVar(&o.namespace, "namespace", "",
+		"Namespace of the "+kind)
+	cmd.Flags().BoolVar(&o.disableNameSuffixHash, "disable-name-suffix-hash", false,
+		"If true, don't append a hash of the content to the name of the "+kind)
+	if kind == "secret" {
+		cmd.Flags().StringVar(&o.secretType, "type", "",
+			"Type of the secret, e.g. kubernetes.io/tls")
+	}
+	return cmd
+}
+
+// Validate validates setGenerated command.
+func (o *setGeneratedOptions) Validate(args []string) error {
+	if len(args) != 1 {
+		return errors.New("must specify one " + o.kind + " name")
+	}
+	o.name = args[0]
+	for _, l := range o.literals {
+		if !strings.Contains(l, "=") {
+			return fmt.Errorf("invalid literal source %v, expected key=value", l)
+		}
+	}
+	if o.behavior != "" &&
+		types.NewGenerationBehavior(o.behavior) == types.BehaviorUnspecified {
+		return fmt.Errorf(
+			"invalid behavior %s; legal values: create, merge, replace", o.behavior)
+	}
+	return nil
+}
+
+// RunSetGenerated runs setGenerated command (does real work).
+func (o *setGeneratedOptions) RunSetGenerated(fSys filesys.FileSystem) error {
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	if err != nil {
+		return err
+	}
+	m, err := mf.Read()
+	if err != nil {
+		return err
+	}
+	args, secretType := o.find(m)
+	if args == nil {
+		return fmt.Errorf("%s %s not in kustomization file", o.kind, o.name)
+	}
+	for _, l := range o.literals {
+		args.LiteralSources = setLiteral(args.LiteralSources, l)
+	}
+	for _, k := range o.removeKeys {
+		args.LiteralSources = removeLiteral(args.LiteralSources, k)
+	}
+	if o.behavior != "" {
+		args.Behavior = o.behavior
+	}
+	if o.namespace != "" {
+		args.Namespace = o.namespace
+	}
+	if o.cmd.Flags().Changed("disable-name-suffix-hash") {
+		if args.GeneratorOptions == nil {
+			args.GeneratorOptions = &types.GeneratorOptions{}
+		}
+		args.GeneratorOptions.DisableNameSuffixHash = o.disableNameSuffixHash
+	}
+	if o.secretType != "" {
+		*secretType = o.secretType
+	}
+	return mf.Write(m)
+}
+
+// find returns the generator args of the named
+// configmap or secret, and the type of the secret.
+func (o *setGeneratedOptions) find(m *types.Kustomization) (*types.GeneratorArgs, *string) {
+	if o.kind == "secret" {
+		for i := range m.SecretGenerator {
+			if m.SecretGenerator[i].Name == o.name {
+				return &m.SecretGenerator[i].GeneratorArgs, &m.SecretGenerator[i].Type
+			}
+		}
+		return nil, nil
+	}
+	for i := range m.ConfigMapGenerator {
+		if m.ConfigMapGenerator[i].Name == o.name {
+			return &m.ConfigMapGenerator[i].GeneratorArgs, nil
+		}
+	}
+	return nil, nil
+}
+
+func literalKey(l string) string {
+	return strings.SplitN(l, "=", 2)[0]
+}
+
+// setLiteral replaces the literal with the key of l,
+// if any, with l, else appends l.
+func setLiteral(literals []string, l string) []string {
+	for i, x := range literals {
+		if literalKey(x) == literalKey(l) {
+			literals[i] = l
+			return literals
+		}
+	}
+	return append(literals, l)
+}
+
+func removeLiteral(literals []string, key string) []string {
+	kept := literals[:0]
+	for _, x := range literals {
+		if literalKey(x) != key {
+			kept = append(kept, x)
+		}
+	}
+	return kept
+}
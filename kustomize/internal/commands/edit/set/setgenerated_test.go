@@ -0,0 +1,119 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package set
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	testutils_test "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
+)
+
+const generatedKustomization = `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+# The configmap of the web server.
+configMapGenerator:
+- literals:
+  - a=1
+  - b=2
+  name: web
+secretGenerator:
+- literals:
+  - password=hunter2
+  name: db
+`
+
+func TestSetConfigMap(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	testutils_test.WriteTestKustomizationWith(fSys, []byte(generatedKustomization))
+	cmd := newCmdSetConfigMap(fSys)
+	for _, f := range [][2]string{
+		{"from-literal", "a=3"},
+		{"from-literal", "c=4"},
+		{"remove-key", "b"},
+		{"behavior", "merge"},
+		{"disable-name-suffix-hash", "true"},
+	} {
+		if err := cmd.Flags().Set(f[0], f[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := cmd.RunE(cmd, []string{"web"})
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+# The configmap of the web server.
+configMapGenerator:
+- literals:
+  - a=3
+  - c=4
+  name: web
+  behavior: merge
+  generatorOptions:
+    disableNameSuffixHash: true
+secretGenerator:
+- literals:
+  - password=hunter2
+  name: db
+`
+	if string(content) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, content)
+	}
+}
+
+func TestSetSecret(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	testutils_test.WriteTestKustomizationWith(fSys, []byte(generatedKustomization))
+	cmd := newCmdSetSecret(fSys)
+	cmd.Flags().Set("type", "kubernetes.io/basic-auth")
+	cmd.Flags().Set("namespace", "prod")
+	err := cmd.RunE(cmd, []string{"db"})
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+# The configmap of the web server.
+configMapGenerator:
+- literals:
+  - a=1
+  - b=2
+  name: web
+secretGenerator:
+- literals:
+  - password=hunter2
+  name: db
+  namespace: prod
+  type: kubernetes.io/basic-auth
+`
+	if string(content) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, content)
+	}
+}
+
+func TestSetGeneratedErrors(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	testutils_test.WriteTestKustomizationWith(fSys, []byte(generatedKustomization))
+	cmd := newCmdSetConfigMap(fSys)
+	err := cmd.RunE(cmd, []string{"db"})
+	if err == nil || err.Error() != "configmap db not in kustomization file" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	cmd.Flags().Set("behavior", "append")
+	err = cmd.RunE(cmd, []string{"web"})
+	if err == nil || err.Error() !=
+		"invalid behavior append; legal values: create, merge, replace" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
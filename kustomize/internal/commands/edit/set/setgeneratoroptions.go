@@ -0,0 +1,102 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package set
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/util"
+)
+
+type setGeneratorOptionsOptions struct {
+	labels                map[string]string
+	annotations           map[string]string
+	disableNameSuffixHash bool
+	cmd                   *cobra.Command
+}
+
+// newCmdSetGeneratorOptions sets the generatorOptions field in the kustomization.
+func newCmdSetGeneratorOptions(fSys filesys.FileSystem) *cobra.Command {
+	var o setGeneratorOptionsOptions
+	var labels, annotations string
+
+	cmd := &cobra.Command{
+		Use:   "generatoroptions",
+		Short: "Sets the options of all generators of the kustomization file",
+		Example: `
+The command
+	set generatoroptions --disable-name-suffix-hash --label app:web
+sets disableNameSuffixHash to true, and the label app to web, in the
+generatorOptions field of the kustomization file, keeping other labels.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			o.labels, err = util.ConvertToMap(labels, "label")
+			if err != nil {
+				return err
+			}
+			o.annotations, err = util.ConvertToMap(annotations, "annotation")
+			if err != nil {
+				return err
+			}
+			err = o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunSetGeneratorOptions(fSys)
+		},
+	}
+	o.cmd = cmd
+	cmd.Flags().StringVar(&labels, "label", "",
+		"Labels to add to generated resources, e.g. {key1:value1},{key2:value2}")
+	cmd.Flags().StringVar(&annotations, "annotation", "",
+		"Annotations to add to generated resources, e.g. {key1:value1},{key2:value2}")
+	cmd.Flags().BoolVar(&o.disableNameSuffixHash, "disable-name-suffix-hash", false,
+		"If true, don't append hashes of their content to the names of generated resources")
+	return cmd
+}
+
+// Validate validates setGeneratorOptions command.
+func (o *setGeneratorOptionsOptions) Validate(args []string) error {
+	if len(args) > 0 {
+		return errors.New("generator options are set by flags, not arguments")
+	}
+	return nil
+}
+
+// RunSetGeneratorOptions runs setGeneratorOptions command (does real work).
+func (o *setGeneratorOptionsOptions) RunSetGeneratorOptions(fSys filesys.FileSystem) error {
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	if err != nil {
+		return err
+	}
+	m, err := mf.Read()
+	if err != nil {
+		return err
+	}
+	if m.GeneratorOptions == nil {
+		m.GeneratorOptions = &types.GeneratorOptions{}
+	}
+	opts := m.GeneratorOptions
+	if len(o.labels) > 0 && opts.Labels == nil {
+		opts.Labels = make(map[string]string)
+	}
+	for k, v := range o.labels {
+		opts.Labels[k] = v
+	}
+	if len(o.annotations) > 0 && opts.Annotations == nil {
+		opts.Annotations = make(map[string]string)
+	}
+	for k, v := range o.annotations {
+		opts.Annotations[k] = v
+	}
+	if o.cmd.Flags().Changed("disable-name-suffix-hash") {
+		opts.DisableNameSuffixHash = o.disableNameSuffixHash
+	}
+	return mf.Write(m)
+}
@@ -0,0 +1,44 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package set
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	testutils_test "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
+)
+
+func TestSetGeneratorOptions(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	testutils_test.WriteTestKustomizationWith(fSys, []byte(`
+generatorOptions:
+  labels:
+    team: a
+`))
+	cmd := newCmdSetGeneratorOptions(fSys)
+	cmd.Flags().Set("label", "app:web,team:b")
+	cmd.Flags().Set("annotation", "note:generated")
+	cmd.Flags().Set("disable-name-suffix-hash", "true")
+	err := cmd.RunE(cmd, nil)
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := `generatorOptions:
+  labels:
+    team: b
+    app: web
+  annotations:
+    note: generated
+  disableNameSuffixHash: true
+`
+	if !strings.Contains(string(content), expected) {
+		t.Errorf("expected\n%s\ngot\n%s", expected, content)
+	}
+}
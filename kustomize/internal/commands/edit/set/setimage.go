@@ -13,11 +13,13 @@ import (
 
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/imagedigest"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
 )
 
 type setImageOptions struct {
-	imageMap map[string]types.Image
+	imageMap       map[string]types.Image
+	resolveDigests bool
 }
 
 var pattern = regexp.MustCompile("^(.*):([a-zA-Z0-9._-]*)$")
@@ -73,6 +75,18 @@ images:
 
 to the kustomization file if it doesn't exist,
 and overwrite the previous ones if the image name exists.
+
+The command
+  set image --resolve-digests postgres=eu.gcr.io/my-project/postgres:12
+will add
+
+images:
+- digest: sha256:<digest of eu.gcr.io/my-project/postgres:12>
+  name: postgres
+  newName: eu.gcr.io/my-project/postgres
+
+to the kustomization file, recording the digest in the
+lock file ` + imagedigest.LockFileName + ` next to it.
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := o.Validate(args)
@@ -82,6 +96,13 @@ and overwrite the previous ones if the image name exists.
 			return o.RunSetImage(fSys)
 		},
 	}
+	cmd.Flags().BoolVar(
+		&o.resolveDigests,
+		"resolve-digests",
+		false,
+		"Replace each new tag with the digest it refers to, as recorded in "+
+			imagedigest.LockFileName+", else as resolved with the registry "+
+			"of the image, and then recorded.")
 	return cmd
 }
 
@@ -121,6 +142,13 @@ func (o *setImageOptions) RunSetImage(fSys filesys.FileSystem) error {
 		return err
 	}
 
+	if o.resolveDigests {
+		err = o.resolve(fSys)
+		if err != nil {
+			return err
+		}
+	}
+
 	// append only new images from kustomize file
 	for _, im := range m.Images {
 		if _, ok := o.imageMap[im.Name]; ok {
@@ -143,6 +171,36 @@ func (o *setImageOptions) RunSetImage(fSys filesys.FileSystem) error {
 	return mf.Write(m)
 }
 
+// resolve replaces the tags of the images given with
+// their digests, per the lock file, recording any
+// digests that had to be resolved.
+func (o *setImageOptions) resolve(fSys filesys.FileSystem) error {
+	lock, err := imagedigest.ReadLockFile(fSys, filesys.SelfDir)
+	if err != nil {
+		return err
+	}
+	r := imagedigest.NewResolver()
+	for k, im := range o.imageMap {
+		if im.Digest != "" {
+			continue
+		}
+		image := im.Name
+		if im.NewName != "" {
+			image = im.NewName
+		}
+		if im.NewTag != "" {
+			image += ":" + im.NewTag
+		}
+		im.Digest, err = lock.Resolve(r, image)
+		if err != nil {
+			return err
+		}
+		im.NewTag = ""
+		o.imageMap[k] = im
+	}
+	return lock.Write(fSys, filesys.SelfDir)
+}
+
 func parse(arg string) (types.Image, error) {
 
 	// matches if there is an image name to overwrite
@@ -9,6 +9,8 @@ import (
 	"testing"
 
 	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/imagedigest"
+	registrytest_test "sigs.k8s.io/kustomize/api/testutils/registrytest"
 	testutils_test "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
 )
 
@@ -164,9 +166,9 @@ func TestSetImage(t *testing.T) {
 					"- name: image1",
 					"  newName: foo.bar.foo:8800/foo/image1",
 					"  newTag: foo-bar",
-					"- digest: sha256:24a0c4b4a4c0eb97a1aabb8e29f18e917d05abfe1b7a7c07857230879ce7d3d3",
-					"  name: image2",
+					"- name: image2",
 					"  newName: my-image2",
+					"  digest: sha256:24a0c4b4a4c0eb97a1aabb8e29f18e917d05abfe1b7a7c07857230879ce7d3d3",
 					"- name: image3",
 					"  newTag: my-tag",
 				}},
@@ -223,3 +225,54 @@ func TestSetImage(t *testing.T) {
 		})
 	}
 }
+
+func TestSetImageResolveDigests(t *testing.T) {
+	reg := registrytest_test.NewRegistry()
+	defer reg.Close()
+	digest := reg.Push("team/app", "v1")
+	fSys := filesys.MakeFsInMemory()
+	testutils_test.WriteTestKustomization(fSys)
+	cmd := newCmdSetImage(fSys)
+	if err := cmd.Flags().Set("resolve-digests", "true"); err != nil {
+		t.Fatal(err)
+	}
+	err := cmd.RunE(cmd, []string{
+		"app=" + reg.Host + "/team/app:v1",
+		"other@sha256:24a0c4b4a4c0eb97a1aabb8e29f18e917d05abfe1b7a7c07857230879ce7d3d3",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := `images:
+- digest: ` + digest + `
+  name: app
+  newName: ` + reg.Host + `/team/app
+- digest: sha256:24a0c4b4a4c0eb97a1aabb8e29f18e917d05abfe1b7a7c07857230879ce7d3d3
+  name: other
+`
+	if !strings.Contains(string(content), expected) {
+		t.Fatalf("unexpected kustomization file:\n%s\nexpected:\n%s", content, expected)
+	}
+	lock, err := fSys.ReadFile(imagedigest.LockFileName)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if !strings.Contains(string(lock), "image: "+reg.Host+"/team/app:v1") {
+		t.Fatalf("unexpected lock file:\n%s", lock)
+	}
+
+	reg.Close()
+	cmd = newCmdSetImage(fSys)
+	if err = cmd.Flags().Set("resolve-digests", "true"); err != nil {
+		t.Fatal(err)
+	}
+	err = cmd.RunE(cmd, []string{"app=" + reg.Host + "/team/app:v2"})
+	if err == nil || !strings.Contains(err.Error(),
+		"resolving digest of image "+reg.Host+"/team/app:v2") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
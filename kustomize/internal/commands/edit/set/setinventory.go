@@ -0,0 +1,73 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package set
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
+)
+
+type setInventoryOptions struct {
+	name      string
+	namespace string
+}
+
+// newCmdSetInventory sets the inventory field in the kustomization.
+func newCmdSetInventory(fSys filesys.FileSystem) *cobra.Command {
+	var o setInventoryOptions
+
+	cmd := &cobra.Command{
+		Use:   "inventory NAME",
+		Short: "Sets the configmap recording the inventory of the kustomization file",
+		Example: `
+The command
+	set inventory my-inventory --namespace default
+sets the inventory of the kustomization file to the configmap
+my-inventory in the namespace default.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunSetInventory(fSys)
+		},
+	}
+	cmd.Flags().StringVar(&o.namespace, "namespace", "",
+		"Namespace of the inventory configmap")
+	return cmd
+}
+
+// Validate validates setInventory command.
+func (o *setInventoryOptions) Validate(args []string) error {
+	if len(args) != 1 {
+		return errors.New("must specify exactly one inventory name")
+	}
+	o.name = args[0]
+	return nil
+}
+
+// RunSetInventory runs setInventory command (does real work).
+func (o *setInventoryOptions) RunSetInventory(fSys filesys.FileSystem) error {
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	if err != nil {
+		return err
+	}
+	m, err := mf.Read()
+	if err != nil {
+		return err
+	}
+	m.Inventory = &types.Inventory{
+		Type: "ConfigMap",
+		ConfigMap: types.NameArgs{
+			Name:      o.name,
+			Namespace: o.namespace,
+		},
+	}
+	return mf.Write(m)
+}
@@ -0,0 +1,41 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package set
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	testutils_test "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
+)
+
+func TestSetInventory(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	testutils_test.WriteTestKustomization(fSys)
+	cmd := newCmdSetInventory(fSys)
+	cmd.Flags().Set("namespace", "default")
+	err := cmd.RunE(cmd, []string{"my-inventory"})
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := `inventory:
+  configMap:
+    name: my-inventory
+    namespace: default
+  type: ConfigMap
+`
+	if !strings.Contains(string(content), expected) {
+		t.Errorf("expected\n%s\ngot\n%s", expected, content)
+	}
+
+	err = cmd.RunE(cmd, nil)
+	if err == nil || err.Error() != "must specify exactly one inventory name" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
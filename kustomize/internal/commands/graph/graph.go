@@ -0,0 +1,160 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/depgraph"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+)
+
+const (
+	formatDot  = "dot"
+	formatJson = "json"
+)
+
+// Options contain the options for running graph.
+type Options struct {
+	paths      []string
+	format     string
+	affectedBy []string
+}
+
+var examples = `
+To print the graph of what the kustomization in someDir
+includes, in the DOT language of Graphviz, run
+
+  kustomize graph someDir | dot -Tsvg > graph.svg
+
+Bases, components and the files of resources, patches,
+generator inputs and the like are nodes.  Remote bases
+are nodes too, but aren't fetched.  A local helm chart
+directory is a single node, including every file below it.
+
+For output meant for programs, run
+
+  kustomize graph --format json overlays/*
+
+To print which of several kustomizations include any of
+some files, e.g. to know which to build again when they
+change, run
+
+  kustomize graph overlays/* --affected_by base/deployment.yaml
+`
+
+// NewCmdGraph creates a new graph command.
+func NewCmdGraph(out io.Writer, fSys filesys.FileSystem) *cobra.Command {
+	var o Options
+	cmd := &cobra.Command{
+		Use:          "graph [path...]",
+		Short:        "Print the graph of what kustomizations include",
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunGraph(out, fSys)
+		},
+	}
+	cmd.Flags().StringVar(
+		&o.format,
+		"format", formatDot,
+		"Output format, one of: "+formatDot+", "+formatJson+".")
+	cmd.Flags().StringSliceVar(
+		&o.affectedBy,
+		"affected_by", nil,
+		"If specified, instead of the graph, print the paths "+
+			"given as arguments whose kustomizations include "+
+			"any of these files or directories.")
+	return cmd
+}
+
+// Validate validates graph command.
+func (o *Options) Validate(args []string) error {
+	o.paths = args
+	if len(o.paths) == 0 {
+		o.paths = []string{filesys.SelfDir}
+	}
+	switch o.format {
+	case "":
+		o.format = formatDot
+	case formatDot, formatJson:
+	default:
+		return fmt.Errorf(
+			"illegal flag value --format %s; legal values: %v",
+			o.format, []string{formatDot, formatJson})
+	}
+	return nil
+}
+
+// RunGraph walks the kustomizations, and prints their graph,
+// or the roots affected by the files given.
+func (o *Options) RunGraph(out io.Writer, fSys filesys.FileSystem) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	g := depgraph.New()
+	opts := krusty.MakeDefaultOptions()
+	k := krusty.MakeKustomizer(fSys, opts)
+	for _, path := range o.paths {
+		if err = k.AddToGraph(g, path); err != nil {
+			return errors.Wrapf(err, "walking '%s'", path)
+		}
+	}
+	g = g.Relabel(func(id string) string {
+		return relative(cwd, id)
+	})
+	if len(o.affectedBy) > 0 {
+		affected := make(map[string]bool)
+		for _, p := range o.affectedBy {
+			if !filepath.IsAbs(p) {
+				p = filepath.Join(cwd, p)
+			}
+			for _, r := range g.RootsIncluding(relative(cwd, p)) {
+				affected[r] = true
+			}
+		}
+		for _, r := range g.Roots {
+			if affected[r] {
+				fmt.Fprintln(out, r)
+			}
+		}
+		return nil
+	}
+	if o.format == formatJson {
+		b, err := json.MarshalIndent(g, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(b))
+		return err
+	}
+	return g.WriteDot(out)
+}
+
+// relative returns path relative to dir, if it's
+// in dir, else path.
+func relative(dir, path string) string {
+	if !filepath.IsAbs(path) {
+		return path
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." ||
+		strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/depgraph"
+	"sigs.k8s.io/kustomize/api/filesys"
+)
+
+func writeOverlays(t *testing.T) filesys.FileSystem {
+	fSys := filesys.MakeFsInMemory()
+	for p, content := range map[string]string{
+		"/base/kustomization.yaml": `
+resources:
+- deployment.yaml
+`,
+		"/base/deployment.yaml": "",
+		"/staging/kustomization.yaml": `
+resources:
+- ../base
+`,
+		"/prod/kustomization.yaml": `
+resources:
+- ../base
+patchesStrategicMerge:
+- patch.yaml
+`,
+		"/prod/patch.yaml": "",
+	} {
+		if err := fSys.WriteFile(p, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return fSys
+}
+
+func TestGraphValidate(t *testing.T) {
+	o := Options{format: "svg"}
+	if err := o.Validate(nil); err == nil {
+		t.Fatalf("expected error for an unknown format")
+	}
+	o = Options{}
+	if err := o.Validate(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o.format != formatDot || len(o.paths) != 1 || o.paths[0] != "." {
+		t.Fatalf("unexpected defaults %v", o)
+	}
+}
+
+func TestGraphJson(t *testing.T) {
+	o := Options{format: formatJson}
+	if err := o.Validate([]string{"/prod", "/staging"}); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := o.RunGraph(&out, writeOverlays(t)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var g depgraph.Graph
+	if err := json.Unmarshal(out.Bytes(), &g); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out.String())
+	}
+	if len(g.Roots) != 2 || len(g.Nodes) != 8 || len(g.Edges) != 7 {
+		t.Fatalf("unexpected graph\n%s", out.String())
+	}
+}
+
+func TestGraphAffectedBy(t *testing.T) {
+	fSys := writeOverlays(t)
+	for _, tc := range []struct {
+		files    []string
+		expected string
+	}{
+		{[]string{"/base/deployment.yaml"}, "/prod\n/staging\n"},
+		{[]string{"/prod/patch.yaml", "/unrelated.yaml"}, "/prod\n"},
+		{[]string{"/staging"}, "/staging\n"},
+		{[]string{"/unrelated.yaml"}, ""},
+	} {
+		o := Options{affectedBy: tc.files}
+		if err := o.Validate([]string{"/prod", "/staging"}); err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		if err := o.RunGraph(&out, fSys); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.String() != tc.expected {
+			t.Errorf("%v: expected\n%s\nbut got\n%s", tc.files, tc.expected, out.String())
+		}
+	}
+}
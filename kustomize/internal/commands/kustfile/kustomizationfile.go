@@ -7,16 +7,15 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"io"
 	"log"
 	"reflect"
-	"regexp"
 	"strings"
 
 	"sigs.k8s.io/kustomize/api/filesys"
 	"sigs.k8s.io/kustomize/api/konfig"
 	"sigs.k8s.io/kustomize/api/types"
-	"sigs.k8s.io/yaml"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	k8syaml "sigs.k8s.io/yaml"
 )
 
 var fieldMarshallingOrder = determineFieldOrder()
@@ -39,11 +38,13 @@ func determineFieldOrder() []string {
 
 	ordered := []string{
 		"Resources",
+		"Components",
 		"Bases",
 		"NamePrefix",
 		"NameSuffix",
 		"Namespace",
 		"Crds",
+		"OpenAPI",
 		"CommonLabels",
 		"CommonAnnotations",
 		"PatchesStrategicMerge",
@@ -51,8 +52,10 @@ func determineFieldOrder() []string {
 		"Patches",
 		"ConfigMapGenerator",
 		"SecretGenerator",
+		"HelmCharts",
 		"GeneratorOptions",
 		"Vars",
+		"Replacements",
 		"Images",
 		"Replicas",
 		"Configurations",
@@ -84,26 +87,12 @@ func determineFieldOrder() []string {
 	return result
 }
 
-// commentedField records the comment associated with a kustomization field
-// field has to be a recognized kustomization field
-// comment can be empty
-type commentedField struct {
-	field   string
-	comment []byte
-}
-
-func (cf *commentedField) appendComment(comment []byte) {
-	cf.comment = append(cf.comment, comment...)
-}
-
-func squash(x [][]byte) []byte {
-	return bytes.Join(x, []byte(``))
-}
-
 type kustomizationFile struct {
-	path           string
-	fSys           filesys.FileSystem
-	originalFields []*commentedField
+	path string
+	fSys filesys.FileSystem
+	// The file as last read, with deprecated field names
+	// fixed, which Write edits.
+	original []byte
 }
 
 // NewKustomizationFile returns a new instance.
@@ -150,15 +139,12 @@ func (mf *kustomizationFile) Read() (*types.Kustomization, error) {
 	}
 	data = types.FixKustomizationPreUnmarshalling(data)
 	var k types.Kustomization
-	err = yaml.Unmarshal(data, &k)
+	err = k8syaml.Unmarshal(data, &k)
 	if err != nil {
 		return nil, err
 	}
 	k.FixKustomizationPostUnmarshalling()
-	err = mf.parseCommentedFields(data)
-	if err != nil {
-		return nil, err
-	}
+	mf.original = data
 	return &k, err
 }
 
@@ -170,7 +156,11 @@ func (mf *kustomizationFile) Write(kustomization *types.Kustomization) error {
 	if err != nil {
 		return err
 	}
-	return mf.fSys.WriteFile(mf.path, data)
+	if err = mf.fSys.WriteFile(mf.path, data); err != nil {
+		return err
+	}
+	mf.original = data
+	return nil
 }
 
 // StringInSlice returns true if the string is in the slice.
@@ -183,113 +173,457 @@ func StringInSlice(str string, list []string) bool {
 	return false
 }
 
-func (mf *kustomizationFile) parseCommentedFields(content []byte) error {
-	buffer := bytes.NewBuffer(content)
-	var comments [][]byte
-
-	line, err := buffer.ReadBytes('\n')
-	for err == nil {
-		if isCommentOrBlankLine(line) {
-			comments = append(comments, line)
-		} else {
-			matched, field := findMatchedField(line)
-			if matched {
-				mf.originalFields = append(mf.originalFields, &commentedField{field: field, comment: squash(comments)})
-				comments = [][]byte{}
-			} else if len(comments) > 0 {
-				mf.originalFields[len(mf.originalFields)-1].appendComment(squash(comments))
-				comments = [][]byte{}
+// fieldNames maps the lower case names of the fields
+// of a kustomization in yaml to their names, for the
+// case insensitive matching of fields in files.
+var fieldNames = determineFieldNames()
+
+func determineFieldNames() map[string]string {
+	result := make(map[string]string)
+	var add func(t reflect.Type)
+	add = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Anonymous {
+				add(f.Type)
+				continue
 			}
+			n := yamlName(f)
+			result[strings.ToLower(n)] = n
 		}
-		line, err = buffer.ReadBytes('\n')
 	}
+	add(reflect.TypeOf(types.Kustomization{}))
+	return result
+}
 
-	if err != io.EOF {
-		return err
+// yamlName returns the name of the field in yaml, per
+// its json tag, as for sigs.k8s.io/yaml.
+func yamlName(f reflect.StructField) string {
+	n := strings.Split(f.Tag.Get("json"), ",")[0]
+	if n == "" {
+		return f.Name
 	}
-	return nil
+	return n
+}
+
+// fieldLine is a top level field of a kustomization file.
+type fieldLine struct {
+	key   *yaml.Node
+	value *yaml.Node
+	// The lines of the field, [start, end), excluding
+	// the comments and blank lines that follow it.
+	start, end int
 }
 
-// marshal converts a kustomization to a byte stream.
+// marshal converts a kustomization to a byte stream, as an edit
+// of the file as read.  The fields whose values are unchanged keep
+// their bytes, as do comments, blank lines and fields unknown to
+// kustomize.  A changed field has the new value merged into its
+// nodes, keeping the comments and quoting of the parts that stay,
+// and only that field is reformatted.  New fields are inserted
+// in fieldMarshallingOrder among the fields of the file, so e.g.
+// apiVersion and kind go to the top.
 func (mf *kustomizationFile) marshal(kustomization *types.Kustomization) ([]byte, error) {
-	var output []byte
-	for _, comment := range mf.originalFields {
-		output = append(output, comment.comment...)
-		content, err := marshalField(comment.field, kustomization)
-		if err != nil {
-			return content, err
-		}
-		output = append(output, content...)
+	b, err := k8syaml.Marshal(kustomization)
+	if err != nil {
+		return nil, err
 	}
+	desired, err := yaml.Parse(string(b))
+	if err != nil {
+		return nil, err
+	}
+	lines, fields, err := splitFields(mf.original)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
 	for _, field := range fieldMarshallingOrder {
-		if mf.hasField(field) {
+		f, _ := reflect.TypeOf(types.Kustomization{}).FieldByName(field)
+		names = append(names, yamlName(f))
+	}
+	others, err := desired.Fields()
+	if err != nil {
+		return nil, err
+	}
+	order := make(map[string]int)
+	for _, name := range append(names, others...) {
+		if _, ok := order[name]; !ok {
+			order[name] = len(order)
+		}
+	}
+
+	written := make(map[string]bool)
+	for _, f := range fields {
+		if name, known := fieldNames[strings.ToLower(f.key.Value)]; known {
+			written[name] = true
+		}
+	}
+	// newFields returns the fields missing from the file
+	// that come, in fieldMarshallingOrder, before the
+	// field at index limit.
+	newFields := func(limit int) ([]string, error) {
+		var result []string
+		for _, name := range append(names, others...) {
+			want := desired.Field(name)
+			if order[name] >= limit || written[name] || want == nil {
+				continue
+			}
+			written[name] = true
+			s, err := marshalField(name, want.Key.YNode(), want.Value.YNode())
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, s)
+		}
+		return result, nil
+	}
+
+	next := headerEnd(lines)
+	output := append([]string(nil), lines[:next]...)
+	for _, f := range fields {
+		name, known := fieldNames[strings.ToLower(f.key.Value)]
+		if !known {
+			output = append(output, lines[next:f.end]...)
+			next = f.end
+			continue
+		}
+		// New fields go before the first field that follows
+		// them, and before the comments leading up to it.
+		added, err := newFields(order[name])
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, added...)
+		output = append(output, lines[next:f.start]...)
+		next = f.end
+		want := desired.Field(name)
+		if want == nil {
+			continue
+		}
+		if equalNodes(f.value, want.Value.YNode()) && renameKey(lines, f, name) {
+			output = append(output, lines[f.start:f.end]...)
 			continue
 		}
-		content, err := marshalField(field, kustomization)
+		// The comments after the field are kept in its
+		// trailing lines.
+		dropFootComments(f.value)
+		updateNode(f.value, want.Value.YNode())
+		s, err := marshalField(name, f.key, f.value)
 		if err != nil {
-			return content, nil
+			return nil, err
 		}
-		output = append(output, content...)
+		output = append(output, s)
 	}
-	return output, nil
+	output = append(output, lines[next:]...)
+	if n := len(output); n > 0 && !strings.HasSuffix(output[n-1], "\n") {
+		output[n-1] += "\n"
+	}
+	added, err := newFields(len(order))
+	if err != nil {
+		return nil, err
+	}
+	output = append(output, added...)
+	return []byte(strings.Join(output, "")), nil
 }
 
-func (mf *kustomizationFile) hasField(name string) bool {
-	for _, n := range mf.originalFields {
-		if n.field == name {
-			return true
+// splitFields splits the content of a kustomization file
+// into lines, and finds the lines of its top level fields.
+func splitFields(content []byte) ([]string, []fieldLine, error) {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return lines, nil, nil
+	}
+	root := doc.Content[0]
+	if root.Style&yaml.FlowStyle != 0 {
+		// Rewritten as a whole.
+		return nil, nil, nil
+	}
+	var fields []fieldLine
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		fields = append(fields, fieldLine{
+			key:   root.Content[i],
+			value: root.Content[i+1],
+			start: root.Content[i].Line - 1,
+		})
+	}
+	for i := range fields {
+		limit := len(lines)
+		if i+1 < len(fields) {
+			limit = fields[i+1].start
 		}
+		fields[i].end = fieldEnd(lines, fields[i], limit)
 	}
-	return false
+	return lines, fields, nil
 }
 
-/*
- isCommentOrBlankLine determines if a line is a comment or blank line
- Return true for following lines
- # This line is a comment
-       # This line is also a comment with several leading white spaces
+// fieldEnd returns the line after the last line of the
+// field, not counting the comments and blank lines before
+// the limit, but the lines of a trailing block scalar.
+func fieldEnd(lines []string, f fieldLine, limit int) int {
+	last := lastNode(f.value)
+	isBlock := last.Kind == yaml.ScalarNode &&
+		last.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0
+	end := limit
+	for end > last.Line && isCommentOrBlankLine([]byte(lines[end-1])) {
+		if isBlock && indentation(lines[end-1]) > f.key.Column-1 {
+			break
+		}
+		end--
+	}
+	return end
+}
 
- (The line above is a blank line)
-*/
-func isCommentOrBlankLine(line []byte) bool {
-	s := bytes.TrimRight(bytes.TrimLeft(line, " "), "\n")
-	return len(s) == 0 || bytes.HasPrefix(s, []byte(`#`))
+// headerEnd returns the line after the document start
+// marker, ---, at the top of a file, and any comments and
+// blank lines before it, or 0 if there's none.
+func headerEnd(lines []string) int {
+	for i, line := range lines {
+		s := strings.TrimRight(line, " \t\r\n")
+		if s == "---" || strings.HasPrefix(s, "--- #") {
+			return i + 1
+		}
+		if !isCommentOrBlankLine([]byte(line)) {
+			break
+		}
+	}
+	return 0
 }
 
-func findMatchedField(line []byte) (bool, string) {
-	for _, field := range fieldMarshallingOrder {
-		// (?i) is for case insensitive regexp matching
-		r := regexp.MustCompile("^(" + "(?i)" + field + "):")
-		if r.Match(line) {
-			return true, field
+// dropFootComments removes the comments after the node and
+// after its last descendants, which are the comments after
+// the field holding it.
+func dropFootComments(n *yaml.Node) {
+	for {
+		n.FootComment = ""
+		if len(n.Content) == 0 {
+			return
 		}
+		n = n.Content[len(n.Content)-1]
 	}
-	return false, ""
 }
 
-// marshalField marshal a given field of a kustomization object into yaml format.
-// If the field wasn't in the original kustomization.yaml file or wasn't added,
-// an empty []byte is returned.
-func marshalField(field string, kustomization *types.Kustomization) ([]byte, error) {
-	r := reflect.ValueOf(*kustomization)
-	v := r.FieldByName(strings.Title(field))
+func lastNode(n *yaml.Node) *yaml.Node {
+	for len(n.Content) > 0 {
+		n = n.Content[len(n.Content)-1]
+	}
+	return n
+}
 
-	if !v.IsValid() || isEmpty(v) {
-		return []byte{}, nil
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// renameKey writes the key of the field as name, unless it
+// can't be done in place, returning whether the field's
+// lines can be kept.
+func renameKey(lines []string, f fieldLine, name string) bool {
+	if f.key.Value == name {
+		return true
+	}
+	line := lines[f.start]
+	col := f.key.Column - 1
+	if f.key.Style != 0 || !strings.HasPrefix(line[col:], f.key.Value) {
+		return false
+	}
+	lines[f.start] = line[:col] + name + line[col+len(f.key.Value):]
+	return true
+}
+
+// marshalField marshals a top level field of a kustomization.
+// The comments before it, and after it, stay where they are
+// in the file.
+func marshalField(name string, key, value *yaml.Node) (string, error) {
+	k := *key
+	k.Value = name
+	k.Style = 0
+	k.HeadComment = ""
+	k.FootComment = ""
+	dropFootComments(value)
+	s, err := yaml.String(&yaml.Node{
+		Kind:    yaml.MappingNode,
+		Content: []*yaml.Node{&k, value},
+	})
+	if err != nil {
+		return "", err
 	}
+	return compactSequences(s)
+}
 
-	k := &types.Kustomization{}
-	kr := reflect.ValueOf(k)
-	kv := kr.Elem().FieldByName(strings.Title(field))
-	kv.Set(v)
+// compactSequences writes the items of block sequences that
+// are the values of mapping keys at the indentation of the
+// keys, as kustomize always has, rather than indented under
+// them, as later versions of the YAML emitter do.
+func compactSequences(s string) (string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(s), &doc); err != nil {
+		return "", err
+	}
+	lines := strings.SplitAfter(s, "\n")
+	shift := make([]int, len(lines))
+	// walk finds the sequences in n, whose lines
+	// end before the line at index end.
+	var walk func(n *yaml.Node, end int)
+	walk = func(n *yaml.Node, end int) {
+		step := 1
+		if n.Kind == yaml.MappingNode {
+			step = 2
+		}
+		for i := 0; i < len(n.Content); i += step {
+			limit := end
+			if i+step < len(n.Content) {
+				limit = n.Content[i+step].Line - 1
+			}
+			c := n.Content[i]
+			if n.Kind == yaml.MappingNode {
+				key := c
+				c = n.Content[i+1]
+				if c.Kind == yaml.SequenceNode &&
+					c.Style&yaml.FlowStyle == 0 && c.Column > key.Column {
+					for l := key.Line; l < limit; l++ {
+						if indentation(lines[l]) >= c.Column-1 {
+							shift[l] += c.Column - key.Column
+						}
+					}
+				}
+			}
+			walk(c, limit)
+		}
+	}
+	for _, n := range doc.Content {
+		walk(n, len(lines))
+	}
+	for l := range lines {
+		lines[l] = lines[l][shift[l]:]
+	}
+	return strings.Join(lines, ""), nil
+}
 
-	return yaml.Marshal(k)
+// equalNodes is true if the nodes have equal values.
+func equalNodes(a, b *yaml.Node) bool {
+	var x, y interface{}
+	if a.Decode(&x) != nil || b.Decode(&y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
 }
 
-func isEmpty(v reflect.Value) bool {
-	// If v is a pointer type
-	if v.Type().Kind() == reflect.Ptr {
-		return v.IsNil()
+// updateNode sets the value of node to that of want, keeping
+// the comments and styles of node, and of the nodes in it
+// that stay.
+func updateNode(node, want *yaml.Node) {
+	if node.Kind != want.Kind {
+		head, line := node.HeadComment, node.LineComment
+		*node = *want
+		node.HeadComment, node.LineComment = head, line
+		return
 	}
-	return v.Len() == 0
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Value == want.Value && node.ShortTag() == want.ShortTag() {
+			return
+		}
+		quoted := node.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle) != 0
+		if node.ShortTag() != want.ShortTag() || strings.Contains(want.Value, "\n") ||
+			(want.Style != 0 && !quoted) {
+			node.Style = want.Style
+		}
+		node.Value, node.Tag = want.Value, want.Tag
+	case yaml.MappingNode:
+		var content []*yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if w := mapValue(want, node.Content[i].Value); w != nil {
+				updateNode(node.Content[i+1], w)
+				content = append(content, node.Content[i], node.Content[i+1])
+			}
+		}
+		for i := 0; i+1 < len(want.Content); i += 2 {
+			if mapValue(node, want.Content[i].Value) == nil {
+				content = append(content, want.Content[i], want.Content[i+1])
+			}
+		}
+		node.Content = content
+	case yaml.SequenceNode:
+		node.Content = updateElements(node.Content, want.Content)
+	}
+}
+
+// updateElements returns the elements wanted in a sequence,
+// reusing those of old that are equal, or that are the same
+// item, as identified by name or path, or by position.
+func updateElements(old, want []*yaml.Node) []*yaml.Node {
+	used := make([]bool, len(old))
+	result := make([]*yaml.Node, len(want))
+	for i, w := range want {
+		for j, o := range old {
+			if !used[j] && equalNodes(o, w) {
+				used[j], result[i] = true, o
+				break
+			}
+		}
+	}
+	for i, w := range want {
+		for j, o := range old {
+			if result[i] == nil && !used[j] && sameItem(o, w) {
+				updateNode(o, w)
+				used[j], result[i] = true, o
+			}
+		}
+	}
+	for i, w := range want {
+		if result[i] != nil {
+			continue
+		}
+		if i < len(old) && !used[i] && old[i].Kind == yaml.MappingNode {
+			updateNode(old[i], w)
+			used[i], result[i] = true, old[i]
+			continue
+		}
+		result[i] = w
+	}
+	return result
+}
+
+func sameItem(a, b *yaml.Node) bool {
+	for _, k := range []string{"name", "path"} {
+		x, y := mapValue(a, k), mapValue(b, k)
+		if x != nil && y != nil && x.Kind == yaml.ScalarNode &&
+			y.Kind == yaml.ScalarNode && x.Value == y.Value {
+			return true
+		}
+	}
+	return false
+}
+
+func mapValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+/*
+isCommentOrBlankLine determines if a line is a comment or blank line
+Return true for following lines
+# This line is a comment
+
+	# This line is also a comment with several leading white spaces
+
+(The line above is a blank line)
+*/
+func isCommentOrBlankLine(line []byte) bool {
+	s := bytes.TrimSpace(line)
+	return len(s) == 0 || bytes.HasPrefix(s, []byte(`#`))
 }
@@ -19,11 +19,13 @@ func TestFieldOrder(t *testing.T) {
 		"APIVersion",
 		"Kind",
 		"Resources",
+		"Components",
 		"Bases",
 		"NamePrefix",
 		"NameSuffix",
 		"Namespace",
 		"Crds",
+		"OpenAPI",
 		"CommonLabels",
 		"CommonAnnotations",
 		"PatchesStrategicMerge",
@@ -31,8 +33,10 @@ func TestFieldOrder(t *testing.T) {
 		"Patches",
 		"ConfigMapGenerator",
 		"SecretGenerator",
+		"HelmCharts",
 		"GeneratorOptions",
 		"Vars",
+		"Replacements",
 		"Images",
 		"Replicas",
 		"Configurations",
@@ -206,10 +210,10 @@ generatorOptions:
 # Some comments
 # This is some comment we should preserve
 # don't delete it
-  # See which field this comment goes into
 resources:
 - ../namespaces
 - pod.yaml
+  # See which field this comment goes into
 - service.yaml
 
 apiVersion: kustomize.config.k8s.io/v1beta1
@@ -265,12 +269,12 @@ patches:
 - patch2.yaml
 `)
 
-	expected := []byte(`
+	expected := []byte(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+
 patchesStrategicMerge:
 - patch1.yaml
 - patch2.yaml
-apiVersion: kustomize.config.k8s.io/v1beta1
-kind: Kustomization
 `)
 	fSys := filesys.MakeFsInMemory()
 	testutils_test.WriteTestKustomizationWith(
@@ -307,7 +311,9 @@ patches:
     kind: Service
 `)
 
-	expected := []byte(`
+	expected := []byte(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+
 patches:
 - path: patch1.yaml
   target:
@@ -315,8 +321,6 @@ patches:
 - path: patch2.yaml
   target:
     kind: Service
-apiVersion: kustomize.config.k8s.io/v1beta1
-kind: Kustomization
 `)
 	fSys := filesys.MakeFsInMemory()
 	testutils_test.WriteTestKustomizationWith(fSys, kustomizationContentWithComments)
@@ -340,3 +344,252 @@ kind: Kustomization
 			string(expected), string(bytes))
 	}
 }
+
+func TestEditPreservesLayout(t *testing.T) {
+	content := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+
+# The namespace of everything.
+namespace: "prod"   # not staging
+
+resources:
+  - deployment.yaml  # the app
+  - service.yaml
+
+x-owner: 'team-a'  # unknown to kustomize
+
+images:
+- name: nginx # the web server
+  newTag: '1.19'
+- name: redis
+  newTag: "6"  # pinned
+
+# Patches, last.
+patchesStrategicMerge:
+- patch.yaml
+`
+	testCases := map[string]struct {
+		edit     func(k *types.Kustomization)
+		expected string
+	}{
+		"unchanged": {
+			edit:     func(k *types.Kustomization) {},
+			expected: content,
+		},
+		"scalar": {
+			edit: func(k *types.Kustomization) { k.Namespace = "dev" },
+			expected: strings.Replace(content,
+				`namespace: "prod"   # not staging`,
+				`namespace: "dev" # not staging`, 1),
+		},
+		"list element": {
+			edit: func(k *types.Kustomization) { k.Images[0].NewTag = "1.20" },
+			expected: strings.Replace(content, `  newTag: '1.19'
+- name: redis
+  newTag: "6"  # pinned
+`, `  newTag: '1.20'
+- name: redis
+  newTag: "6" # pinned
+`, 1),
+		},
+		"list append": {
+			edit: func(k *types.Kustomization) {
+				k.Resources = append(k.Resources, "configmap.yaml")
+			},
+			expected: strings.Replace(content, `resources:
+  - deployment.yaml  # the app
+  - service.yaml
+`, `resources:
+- deployment.yaml # the app
+- service.yaml
+- configmap.yaml
+`, 1),
+		},
+		"remove field": {
+			edit: func(k *types.Kustomization) { k.Images = nil },
+			expected: strings.Replace(content, `images:
+- name: nginx # the web server
+  newTag: '1.19'
+- name: redis
+  newTag: "6"  # pinned
+`, "", 1),
+		},
+		"remove list element": {
+			edit: func(k *types.Kustomization) { k.Images = k.Images[1:] },
+			expected: strings.Replace(content, `images:
+- name: nginx # the web server
+  newTag: '1.19'
+- name: redis
+  newTag: "6"  # pinned
+`, `images:
+- name: redis
+  newTag: "6" # pinned
+`, 1),
+		},
+		"add field": {
+			edit: func(k *types.Kustomization) { k.NamePrefix = "web-" },
+			expected: strings.Replace(content, "kind: Kustomization\n",
+				"kind: Kustomization\nnamePrefix: web-\n", 1),
+		},
+		"add field between": {
+			edit: func(k *types.Kustomization) {
+				k.GeneratorOptions = &types.GeneratorOptions{DisableNameSuffixHash: true}
+			},
+			expected: strings.Replace(content, "\nimages:\n",
+				"generatorOptions:\n  disableNameSuffixHash: true\n\nimages:\n", 1),
+		},
+		"add field last": {
+			edit: func(k *types.Kustomization) {
+				k.Replicas = []types.Replica{{Name: "web", Count: 2}}
+			},
+			expected: content + "replicas:\n- count: 2\n  name: web\n",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			fSys := filesys.MakeFsInMemory()
+			testutils_test.WriteTestKustomizationWith(fSys, []byte(content))
+			mf, err := NewKustomizationFile(fSys)
+			if err != nil {
+				t.Fatalf("Unexpected Error: %v", err)
+			}
+			k, err := mf.Read()
+			if err != nil {
+				t.Fatalf("Unexpected Error: %v", err)
+			}
+			tc.edit(k)
+			if err = mf.Write(k); err != nil {
+				t.Fatalf("Unexpected Error: %v", err)
+			}
+			actual, _ := fSys.ReadFile(mf.path)
+			if string(actual) != tc.expected {
+				t.Fatalf(
+					"expected =\n%s\n\nactual =\n%s\n", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEditKeepsHeaderAndTrailingComments(t *testing.T) {
+	testCases := map[string]struct {
+		content  string
+		expected string
+	}{
+		"document start": {
+			content: "---\nresources:\n- a.yaml\n",
+			expected: `---
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- a.yaml
+- b.yaml
+`,
+		},
+		"comments and document start": {
+			content: "# The app.\n\n--- # first\nresources:\n- a.yaml\n",
+			expected: `# The app.
+
+--- # first
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- a.yaml
+- b.yaml
+`,
+		},
+		"trailing comment": {
+			content: "resources:\n- a.yaml\n# trailing\n",
+			expected: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- a.yaml
+- b.yaml
+# trailing
+`,
+		},
+		"indented trailing comment": {
+			content: "resources:\n  - a.yaml\n  # trailing\nnamePrefix: web-\n",
+			expected: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- a.yaml
+- b.yaml
+  # trailing
+namePrefix: web-
+`,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			fSys := filesys.MakeFsInMemory()
+			testutils_test.WriteTestKustomizationWith(fSys, []byte(tc.content))
+			mf, err := NewKustomizationFile(fSys)
+			if err != nil {
+				t.Fatalf("Unexpected Error: %v", err)
+			}
+			k, err := mf.Read()
+			if err != nil {
+				t.Fatalf("Unexpected Error: %v", err)
+			}
+			k.Resources = append(k.Resources, "b.yaml")
+			if err = mf.Write(k); err != nil {
+				t.Fatalf("Unexpected Error: %v", err)
+			}
+			actual, _ := fSys.ReadFile(mf.path)
+			if string(actual) != tc.expected {
+				t.Fatalf(
+					"expected =\n%s\n\nactual =\n%s\n", tc.expected, actual)
+			}
+			mf, err = NewKustomizationFile(fSys)
+			if err != nil {
+				t.Fatalf("Unexpected Error: %v", err)
+			}
+			k, err = mf.Read()
+			if err != nil {
+				t.Fatalf("Unexpected Error: %v", err)
+			}
+			if !reflect.DeepEqual(k.Resources, []string{"a.yaml", "b.yaml"}) {
+				t.Fatalf("unexpected resources read back: %v", k.Resources)
+			}
+		})
+	}
+}
+
+func TestCompactSequences(t *testing.T) {
+	actual, err := compactSequences(`replacements:
+  - source:
+      kind: ConfigMap
+    targets:
+      # The deployment.
+      - fieldPaths:
+          - spec.replicas
+        select:
+          kind: Deployment
+  - path: r.yaml
+script: |
+    - not an item
+      - nor this
+flow: [a, b]
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `replacements:
+- source:
+    kind: ConfigMap
+  targets:
+  # The deployment.
+  - fieldPaths:
+    - spec.replicas
+    select:
+      kind: Deployment
+- path: r.yaml
+script: |
+    - not an item
+      - nor this
+flow: [a, b]
+`
+	if actual != expected {
+		t.Fatalf("expected =\n%s\n\nactual =\n%s\n", expected, actual)
+	}
+}
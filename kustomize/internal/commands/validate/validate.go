@@ -0,0 +1,50 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/kustvalidate"
+)
+
+// NewCmdValidate returns an instance of 'validate' subcommand.
+func NewCmdValidate(out io.Writer, fSys filesys.FileSystem) *cobra.Command {
+	return &cobra.Command{
+		Use: "validate {dir}",
+		Short: "Checks the " + konfig.DefaultKustomizationFileName() +
+			" file in a directory for mistakes",
+		Long: `Checks the kustomization file in a directory, by default
+the current one, for unknown or misspelled fields, values
+of the wrong type, and deprecated fields, printing every
+problem found with its line and column.  Only errors, not
+warnings, make the command fail.`,
+		Example: `
+  kustomize validate someDir
+`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return fmt.Errorf("specify one directory")
+			}
+			dir := filesys.SelfDir
+			if len(args) == 1 {
+				dir = args[0]
+			}
+			issues, err := kustvalidate.ValidateDir(fSys, dir)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(out, issues.String())
+			if n := len(issues.Errors()); n > 0 {
+				return fmt.Errorf("found %d error(s)", n)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"bytes"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+)
+
+func TestValidate(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	err := fSys.WriteFile("/app/kustomization.yaml", []byte(`bases:
+- ../base
+namePrefx: p-
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	cmd := NewCmdValidate(&out, fSys)
+	err = cmd.RunE(cmd, []string{"/app"})
+	if err == nil || err.Error() != "found 1 error(s)" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const expected = `/app/kustomization.yaml:1:1: warning: field "bases" is deprecated; use "resources" instead
+/app/kustomization.yaml:3:1: error: unknown field "namePrefx"; did you mean "namePrefix"?
+`
+	if out.String() != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, out.String())
+	}
+
+	err = fSys.WriteFile("/app/kustomization.yaml", []byte(`bases:
+- ../base
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	err = cmd.RunE(cmd, []string{"/app"})
+	if err != nil {
+		t.Fatalf("warnings alone shouldn't fail: %v", err)
+	}
+}
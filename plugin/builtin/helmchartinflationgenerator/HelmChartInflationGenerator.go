@@ -0,0 +1,159 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Inflates a helm chart by running "helm template".
+type plugin struct {
+	h                *resmap.PluginHelpers
+	types.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+	types.HelmChartArgs
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	h *resmap.PluginHelpers, config []byte) (err error) {
+	p.HelmChartArgs = types.HelmChartArgs{}
+	err = yaml.Unmarshal(config, p)
+	if err != nil {
+		return
+	}
+	if p.Chart == "" {
+		return fmt.Errorf("must specify a chart")
+	}
+	if p.ReleaseName == "" {
+		p.ReleaseName = "release-name"
+	}
+	if p.ReleaseNamespace == "" {
+		p.ReleaseNamespace = p.Namespace
+	}
+	p.h = h
+	return
+}
+
+// helmCommand returns the helm program to run, as
+// configured by whoever runs the build.
+func (p *plugin) helmCommand() string {
+	if pc := p.h.PluginConfig(); pc != nil && pc.HelmCommand != "" {
+		return pc.HelmCommand
+	}
+	return "helm"
+}
+
+func (p *plugin) Generate() (resmap.ResMap, error) {
+	dir, err := ioutil.TempDir("", "kustomize-helm-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	args, err := p.templateArgs(dir)
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(p.helmCommand(), args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		return nil, fmt.Errorf(
+			"unable to inflate chart %s: %v: %s",
+			p.Chart, err, strings.TrimSpace(stderr.String()))
+	}
+	return p.h.ResmapFactory().NewResMapFromBytes(stdout.Bytes())
+}
+
+// templateArgs returns the arguments of "helm template",
+// writing the files they name to dir.  Files are read
+// with the kustomization's loader, so that they are
+// subject to its restrictions.
+func (p *plugin) templateArgs(dir string) ([]string, error) {
+	chart, err := p.chartPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"template", p.ReleaseName, chart}
+	if p.ReleaseNamespace != "" {
+		args = append(args, "--namespace", p.ReleaseNamespace)
+	}
+	if p.ValuesFile != "" {
+		b, err := p.h.Loader().Load(p.ValuesFile)
+		if err != nil {
+			return nil, err
+		}
+		f, err := writeFile(dir, "values.yaml", b)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--values", f)
+	}
+	if len(p.ValuesInline) > 0 {
+		b, err := yaml.Marshal(p.ValuesInline)
+		if err != nil {
+			return nil, err
+		}
+		f, err := writeFile(dir, "values-inline.yaml", b)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--values", f)
+	}
+	if p.IncludeCRDs {
+		args = append(args, "--include-crds")
+	}
+	return args, nil
+}
+
+// chartPath returns the path of the chart to give helm.
+// The chart must be below the kustomization root.  A
+// packaged chart is copied to dir; a chart directory
+// is used in place, once its Chart.yaml is loaded, so
+// that the load restrictions apply to it.
+func (p *plugin) chartPath(dir string) (string, error) {
+	chart := filepath.ToSlash(p.Chart)
+	if filepath.IsAbs(p.Chart) || strings.HasPrefix(chart, "/") {
+		return "", fmt.Errorf(
+			"chart %s must be relative to the kustomization root", p.Chart)
+	}
+	for _, elem := range strings.Split(chart, "/") {
+		if elem == ".." {
+			return "", fmt.Errorf(
+				"chart %s must be below the kustomization root", p.Chart)
+		}
+	}
+	if strings.HasSuffix(p.Chart, ".tgz") ||
+		strings.HasSuffix(p.Chart, ".tar.gz") {
+		b, err := p.h.Loader().Load(p.Chart)
+		if err != nil {
+			return "", err
+		}
+		return writeFile(dir, filepath.Base(p.Chart), b)
+	}
+	_, err := p.h.Loader().Load(filepath.Join(p.Chart, "Chart.yaml"))
+	if err != nil {
+		return "", errors.Wrapf(err, "loading chart %s", p.Chart)
+	}
+	return filepath.Join(p.h.Loader().Root(), p.Chart), nil
+}
+
+func writeFile(dir, name string, content []byte) (string, error) {
+	f := filepath.Join(dir, name)
+	return f, ioutil.WriteFile(f, content, 0600)
+}
@@ -0,0 +1,178 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+// fakeHelm stands in for "helm template <release> <chart> [flags]",
+// rendering a ConfigMap that shows what it was given.
+const fakeHelm = `#!/bin/sh
+release=$2
+chart=$(basename $3)
+shift 3
+flags=""
+values=""
+while [ $# -gt 0 ]; do
+  case $1 in
+    --values) values="$values$(cat $2)
+"; shift 2;;
+    *) flags="$flags $1"; shift;;
+  esac
+done
+if [ "$chart" = "broken" ]; then
+  echo "Error: chart is broken" >&2
+  exit 1
+fi
+cat <<END
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: $release-$chart
+data:
+  flags: "$flags"
+  values: |
+$(echo "$values" | sed 's/^/    /')
+END
+`
+
+func writeFakeHelm(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "kustomize-fake-helm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	helm := filepath.Join(dir, "helm")
+	if err = ioutil.WriteFile(helm, []byte(fakeHelm), 0700); err != nil {
+		t.Fatal(err)
+	}
+	return helm, func() { os.RemoveAll(dir) }
+}
+
+func TestHelmChartInflationGenerator(t *testing.T) {
+	helm, cleanup := writeFakeHelm(t)
+	defer cleanup()
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("HelmChartInflationGenerator")
+	defer th.Reset()
+	th.PluginConfig().HelmCommand = helm
+
+	th.WriteF("minecraft/Chart.yaml", "name: minecraft")
+	th.WriteF("values.yaml", `
+replicas: 3
+`)
+
+	rm := th.LoadAndRunGenerator(`
+apiVersion: builtin
+kind: HelmChartInflationGenerator
+metadata:
+  name: minecraft
+  namespace: games
+chart: minecraft
+releaseName: moria
+valuesFile: values.yaml
+valuesInline:
+  difficulty: hard
+includeCRDs: true
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+data:
+  flags: ' --namespace games --include-crds'
+  values: |2
+
+    replicas: 3
+    difficulty: hard
+kind: ConfigMap
+metadata:
+  name: moria-minecraft
+`)
+}
+
+func TestHelmChartInflationGeneratorPackagedChart(t *testing.T) {
+	helm, cleanup := writeFakeHelm(t)
+	defer cleanup()
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("HelmChartInflationGenerator")
+	defer th.Reset()
+	th.PluginConfig().HelmCommand = helm
+
+	th.WriteF("minecraft-1.2.0.tgz", "not really a tarball")
+
+	rm := th.LoadAndRunGenerator(`
+apiVersion: builtin
+kind: HelmChartInflationGenerator
+metadata:
+  name: minecraft
+chart: minecraft-1.2.0.tgz
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+data:
+  flags: ""
+  values: ""
+kind: ConfigMap
+metadata:
+  name: release-name-minecraft-1.2.0.tgz
+`)
+}
+
+func TestHelmChartInflationGeneratorFailure(t *testing.T) {
+	helm, cleanup := writeFakeHelm(t)
+	defer cleanup()
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("HelmChartInflationGenerator")
+	defer th.Reset()
+	th.PluginConfig().HelmCommand = helm
+
+	th.WriteF("broken/Chart.yaml", "name: broken")
+	err := th.ErrorFromLoadAndRunGenerator(`
+apiVersion: builtin
+kind: HelmChartInflationGenerator
+metadata:
+  name: broken
+chart: broken
+`)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "Error: chart is broken") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestHelmChartInflationGeneratorChartOutsideRoot(t *testing.T) {
+	helm, cleanup := writeFakeHelm(t)
+	defer cleanup()
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("HelmChartInflationGenerator")
+	defer th.Reset()
+	th.PluginConfig().HelmCommand = helm
+
+	for chart, expected := range map[string]string{
+		"/charts/minecraft":    "must be relative to the kustomization root",
+		"../charts/minecraft":  "must be below the kustomization root",
+		"charts/../../secrets": "must be below the kustomization root",
+		"charts/missing":       "loading chart charts/missing",
+	} {
+		err := th.ErrorFromLoadAndRunGenerator(`
+apiVersion: builtin
+kind: HelmChartInflationGenerator
+metadata:
+  name: minecraft
+chart: ` + chart + `
+`)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("%s: expected error %q, got %v", chart, expected, err)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+module sigs.k8s.io/kustomize/plugin/builtin/helmchartinflationgenerator
+
+go 1.13
+
+require (
+	github.com/pkg/errors v0.9.1
+	sigs.k8s.io/kustomize/api v0.3.1
+	sigs.k8s.io/yaml v1.1.0
+)
+
+replace sigs.k8s.io/kustomize/api => ../../../api
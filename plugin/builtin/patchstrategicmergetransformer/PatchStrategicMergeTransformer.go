@@ -73,7 +73,7 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 		if err != nil {
 			return err
 		}
-		err = target.Patch(patch.Kunstructured)
+		err = p.h.ResmapFactory().ApplySmPatch(target, patch)
 		if err != nil {
 			return err
 		}
@@ -16,6 +16,7 @@ import (
 )
 
 type plugin struct {
+	h            *resmap.PluginHelpers
 	loadedPatch  *resource.Resource
 	decodedPatch jsonpatch.Patch
 	Path         string          `json:"path,omitempty" yaml:"path,omitempty"`
@@ -28,6 +29,7 @@ var KustomizePlugin plugin
 
 func (p *plugin) Config(
 	h *resmap.PluginHelpers, c []byte) (err error) {
+	p.h = h
 	err = yaml.Unmarshal(c, p)
 	if err != nil {
 		return err
@@ -80,7 +82,7 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 		if err != nil {
 			return err
 		}
-		err = target.Patch(p.loadedPatch.Kunstructured)
+		err = p.h.ResmapFactory().ApplySmPatch(target, p.loadedPatch)
 		if err != nil {
 			return err
 		}
@@ -116,7 +118,7 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 			patchCopy.SetName(res.GetName())
 			patchCopy.SetNamespace(res.GetNamespace())
 			patchCopy.SetGvk(res.GetGvk())
-			err = res.Patch(patchCopy.Kunstructured)
+			err = p.h.ResmapFactory().ApplySmPatch(res, patchCopy)
 			if err != nil {
 				return err
 			}
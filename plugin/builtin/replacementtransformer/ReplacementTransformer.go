@@ -0,0 +1,203 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/fieldpath"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Copy a value from a field of one resource, or from a
+// literal, into fields of the resources selected by each
+// target.  A field path is dot separated; list items are
+// selected by index, e.g. ports[0], by the value of one of
+// their fields, e.g. containers[name=nginx], or all of them,
+// e.g. containers[*]; see package fieldpath.
+type plugin struct {
+	Replacements []types.Replacement `json:"replacements,omitempty" yaml:"replacements,omitempty"`
+}
+
+// noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.Replacements = nil
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	for i, r := range p.Replacements {
+		if r.Source == nil {
+			return fmt.Errorf("replacement %d has no source", i)
+		}
+		if (r.Source.ObjRef == nil) == (r.Source.Value == "") {
+			return fmt.Errorf(
+				"replacement %d must have exactly one of source objref and value", i)
+		}
+		targets := r.AllTargets()
+		if len(targets) == 0 {
+			return fmt.Errorf("replacement %d has no targets", i)
+		}
+		for _, t := range targets {
+			if t == nil || t.ObjRef == nil {
+				return fmt.Errorf("replacement %d has a target without objref", i)
+			}
+			if len(t.FieldRefs) == 0 {
+				return fmt.Errorf(
+					"replacement %d has a target without fieldrefs", i)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	for _, r := range p.Replacements {
+		value, err := getReplacement(m, r.Source)
+		if err != nil {
+			return err
+		}
+		for _, t := range r.AllTargets() {
+			if err = substitute(m, t, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func getReplacement(
+	m resmap.ResMap, source *types.ReplSource) (interface{}, error) {
+	if source.ObjRef == nil {
+		return source.Value, nil
+	}
+	resources, err := m.Select(types.Selector{
+		Gvk:       source.ObjRef.Gvk,
+		Name:      source.ObjRef.Name,
+		Namespace: source.ObjRef.Namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resources) != 1 {
+		return nil, fmt.Errorf(
+			"expected one source resource matching %v, found %d",
+			source.ObjRef, len(resources))
+	}
+	fieldRef := source.FieldRef
+	if fieldRef == "" {
+		fieldRef = "metadata.name"
+	}
+	path, err := fieldpath.Parse(fieldRef)
+	if err != nil {
+		return nil, err
+	}
+	value, err := path.Get(resources[0].Map())
+	if err != nil {
+		return nil, fmt.Errorf(
+			"source %s in %s: %v", fieldRef, resources[0].CurId(), err)
+	}
+	if source.Options == nil || source.Options.Delimiter == "" {
+		return value, nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf(
+			"source %s in %s must be a string to use a delimiter",
+			fieldRef, resources[0].CurId())
+	}
+	parts := strings.Split(s, source.Options.Delimiter)
+	i := source.Options.Index
+	if i < 0 || i >= len(parts) {
+		return nil, fmt.Errorf(
+			"index %d out of bounds of source %s split on %q",
+			i, fieldRef, source.Options.Delimiter)
+	}
+	return parts[i], nil
+}
+
+func substitute(
+	m resmap.ResMap, target *types.ReplTarget, value interface{}) error {
+	resources, err := m.Select(*target.ObjRef)
+	if err != nil {
+		return err
+	}
+	if len(resources) == 0 {
+		return fmt.Errorf(
+			"no resources match replacement target %v", target.ObjRef)
+	}
+	for _, r := range resources {
+		for _, fieldRef := range target.FieldRefs {
+			path, err := fieldpath.Parse(fieldRef)
+			if err != nil {
+				return err
+			}
+			err = path.Set(r.Map(), target.Options != nil && target.Options.Create,
+				func(old interface{}) (interface{}, error) {
+					return replace(old, value, target.Options)
+				})
+			if err != nil {
+				return fmt.Errorf(
+					"target %s in %s: %v", fieldRef, r.CurId(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// replace returns the new value of a field, given its old value.
+func replace(
+	old, value interface{}, opts *types.FieldOptions) (interface{}, error) {
+	if opts == nil || opts.Delimiter == "" {
+		return deepCopy(value), nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("value %v must be a string to use a delimiter", value)
+	}
+	var parts []string
+	if old != nil {
+		o, ok := old.(string)
+		if !ok {
+			return nil, fmt.Errorf("%v must be a string to use a delimiter", old)
+		}
+		parts = strings.Split(o, opts.Delimiter)
+	}
+	if opts.Index < 0 {
+		return nil, fmt.Errorf("index %d must not be negative", opts.Index)
+	}
+	for len(parts) <= opts.Index {
+		parts = append(parts, "")
+	}
+	parts[opts.Index] = s
+	return strings.Join(parts, opts.Delimiter), nil
+}
+
+// deepCopy copies maps and lists, so that no two targets
+// share, and later mutate, the same value.
+func deepCopy(v interface{}) interface{} {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for k, x := range typed {
+			result[k] = deepCopy(x)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, x := range typed {
+			result[i] = deepCopy(x)
+		}
+		return result
+	default:
+		return v
+	}
+}
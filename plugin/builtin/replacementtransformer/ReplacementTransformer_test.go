@@ -0,0 +1,318 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+const replacementInput = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: source
+data:
+  image: registry.example.com/app:1.2.3
+  replicas: "3"
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deploy
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:latest
+      - name: sidecar
+        image: sidecar:latest
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: sts
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:latest
+`
+
+func TestReplacementTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ReplacementTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ReplacementTransformer
+metadata:
+  name: notImportantHere
+replacements:
+- source:
+    objref:
+      kind: ConfigMap
+      name: source
+    fieldref: data.image
+  targets:
+  - objref:
+      kind: Deployment
+    fieldrefs:
+    - spec.template.spec.containers[name=app].image
+  - objref:
+      kind: StatefulSet
+    fieldrefs:
+    - spec.template.spec.containers[name=app].image
+- source:
+    objref:
+      kind: ConfigMap
+      name: source
+    fieldref: data.image
+    options:
+      delimiter: ':'
+      index: 1
+  target:
+    objref:
+      kind: Deployment
+    fieldrefs:
+    - spec.template.spec.containers[1].image
+    options:
+      delimiter: ':'
+      index: 1
+- source:
+    value: tuesday
+  target:
+    objref:
+      name: sts
+    fieldrefs:
+    - metadata.annotations.day
+    options:
+      create: true
+`, replacementInput)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+data:
+  image: registry.example.com/app:1.2.3
+  replicas: "3"
+kind: ConfigMap
+metadata:
+  name: source
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deploy
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - image: registry.example.com/app:1.2.3
+        name: app
+      - image: sidecar:1.2.3
+        name: sidecar
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  annotations:
+    day: tuesday
+  name: sts
+spec:
+  template:
+    spec:
+      containers:
+      - image: registry.example.com/app:1.2.3
+        name: app
+`)
+}
+
+func TestReplacementTransformerNonStringValue(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ReplacementTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ReplacementTransformer
+metadata:
+  name: notImportantHere
+replacements:
+- source:
+    objref:
+      kind: Deployment
+      name: deploy
+    fieldref: spec.template.spec.containers
+  target:
+    objref:
+      kind: StatefulSet
+    fieldrefs:
+    - spec.template.spec.containers
+`, replacementInput)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+data:
+  image: registry.example.com/app:1.2.3
+  replicas: "3"
+kind: ConfigMap
+metadata:
+  name: source
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deploy
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - image: app:latest
+        name: app
+      - image: sidecar:latest
+        name: sidecar
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: sts
+spec:
+  template:
+    spec:
+      containers:
+      - image: app:latest
+        name: app
+      - image: sidecar:latest
+        name: sidecar
+`)
+}
+
+func TestReplacementTransformerWildcard(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ReplacementTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ReplacementTransformer
+metadata:
+  name: notImportantHere
+replacements:
+- source:
+    value: "2.0"
+  target:
+    objref:
+      kind: Deployment
+    fieldrefs:
+    - spec.template.spec.containers[*].image
+    options:
+      delimiter: ':'
+      index: 1
+`, replacementInput)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+data:
+  image: registry.example.com/app:1.2.3
+  replicas: "3"
+kind: ConfigMap
+metadata:
+  name: source
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deploy
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - image: app:2.0
+        name: app
+      - image: sidecar:2.0
+        name: sidecar
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: sts
+spec:
+  template:
+    spec:
+      containers:
+      - image: app:latest
+        name: app
+`)
+}
+
+func TestReplacementTransformerErrors(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ReplacementTransformer")
+	defer th.Reset()
+
+	for name, tc := range map[string]struct {
+		config string
+		errMsg string
+	}{
+		"unmatchedTarget": {
+			config: `
+- source:
+    value: x
+  target:
+    objref:
+      kind: DaemonSet
+    fieldrefs:
+    - metadata.name
+`,
+			errMsg: "no resources match replacement target",
+		},
+		"missingField": {
+			config: `
+- source:
+    value: x
+  target:
+    objref:
+      kind: Deployment
+    fieldrefs:
+    - spec.paused
+`,
+			errMsg: `field "paused" not found`,
+		},
+		"ambiguousSource": {
+			config: `
+- source:
+    objref:
+      name: deploy|sts
+  target:
+    objref:
+      kind: ConfigMap
+    fieldrefs:
+    - data.name
+`,
+			errMsg: "expected one source resource",
+		},
+	} {
+		err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: ReplacementTransformer
+metadata:
+  name: notImportantHere
+replacements:`+tc.config, replacementInput)
+		if err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+		if !strings.Contains(err.Error(), tc.errMsg) {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+	}
+}
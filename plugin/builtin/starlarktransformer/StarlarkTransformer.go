@@ -0,0 +1,137 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/kyaml/starlark"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// starlarkIdAnnotation holds the index of a resource in the
+// ResMap while the program runs, to match its output
+// to its input.
+const starlarkIdAnnotation = "kustomize.config.k8s.io/id"
+
+// Transforms resources with a Starlark program, which
+// changes, adds or deletes the items of the predeclared
+// resourceList.  The program is hermetic: it can't load
+// modules, so can't reach the network or files.
+type plugin struct {
+	h *resmap.PluginHelpers
+	// Path of the program, relative to the kustomization.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Program is the program itself.
+	Program string `json:"program,omitempty" yaml:"program,omitempty"`
+
+	// The config, which is resourceList.functionConfig.
+	functionConfig *kyaml.RNode
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	h *resmap.PluginHelpers, c []byte) (err error) {
+	p.Path = ""
+	p.Program = ""
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if (p.Path == "") == (p.Program == "") {
+		return fmt.Errorf("must specify one of path and program")
+	}
+	if p.Path != "" {
+		b, err := h.Loader().Load(p.Path)
+		if err != nil {
+			return err
+		}
+		p.Program = string(b)
+	}
+	p.functionConfig, err = kyaml.Parse(string(c))
+	if err != nil {
+		return err
+	}
+	p.h = h
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	resources := m.Resources()
+	var input []*kyaml.RNode
+	for i, r := range resources {
+		b, err := r.AsYAML()
+		if err != nil {
+			return err
+		}
+		node, err := kyaml.Parse(string(b))
+		if err != nil {
+			return err
+		}
+		err = node.PipeE(kyaml.SetAnnotation(starlarkIdAnnotation, strconv.Itoa(i)))
+		if err != nil {
+			return err
+		}
+		input = append(input, node)
+	}
+	name := p.Path
+	if name == "" {
+		name = "program"
+	}
+	f := &starlark.Filter{
+		Name:           name,
+		Program:        p.Program,
+		FunctionConfig: p.functionConfig,
+	}
+	output, err := f.Filter(input)
+	if err != nil {
+		return err
+	}
+	kept := make(map[int]bool)
+	for _, node := range output {
+		s, err := node.String()
+		if err != nil {
+			return err
+		}
+		res, err := p.h.ResmapFactory().RF().FromBytes([]byte(s))
+		if err != nil {
+			return err
+		}
+		annotations := res.GetAnnotations()
+		id, found := annotations[starlarkIdAnnotation]
+		delete(annotations, starlarkIdAnnotation)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		res.SetAnnotations(annotations)
+		if !found {
+			if err = m.Append(res); err != nil {
+				return err
+			}
+			continue
+		}
+		i, err := strconv.Atoi(id)
+		if err != nil || i < 0 || i >= len(resources) {
+			return fmt.Errorf(
+				"the program %s should not change annotation %s", name, starlarkIdAnnotation)
+		}
+		kept[i] = true
+		resources[i].Kunstructured = res.Kunstructured
+	}
+	for i, r := range resources {
+		if kept[i] {
+			continue
+		}
+		if err = m.Remove(r.CurId()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+const deployments = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  annotations:
+    team: a
+spec:
+  replicas: 1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: db
+spec:
+  replicas: 1
+`
+
+func TestStarlarkTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("StarlarkTransformer")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: StarlarkTransformer
+metadata:
+  name: notImportantHere
+replicas: 3
+program: |
+  def run(items, replicas):
+    for r in items:
+      r["spec"]["replicas"] = replicas
+  run(resourceList["items"], resourceList["functionConfig"]["replicas"])
+`, deployments)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    team: a
+  name: app
+spec:
+  replicas: 3
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: db
+spec:
+  replicas: 3
+`)
+}
+
+func TestStarlarkTransformerAddsAndDeletes(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("StarlarkTransformer")
+	defer th.Reset()
+
+	th.WriteF("program.star", `
+def run(items):
+  result = []
+  for r in items:
+    if r["metadata"]["name"] == "db":
+      continue
+    r["metadata"]["name"] = "web"
+    result.append(r)
+  result.append({
+    "apiVersion": "v1",
+    "kind": "Service",
+    "metadata": {"name": "web"},
+  })
+  return result
+
+resourceList["items"] = run(resourceList["items"])
+`)
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: StarlarkTransformer
+metadata:
+  name: notImportantHere
+path: program.star
+`, deployments)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    team: a
+  name: web
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+}
+
+func TestStarlarkTransformerIsHermetic(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("StarlarkTransformer")
+	defer th.Reset()
+
+	err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: StarlarkTransformer
+metadata:
+  name: notImportantHere
+program: |
+  load("http.star", "http")
+`, deployments)
+	if err == nil || !strings.Contains(err.Error(), "load not implemented") {
+		t.Fatalf("expected a load error, got %v", err)
+	}
+
+	err = th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: StarlarkTransformer
+metadata:
+  name: notImportantHere
+path: ../outside.star
+`, deployments)
+	if err == nil || !strings.Contains(err.Error(), "outside") {
+		t.Fatalf("expected the path to be restricted, got %v", err)
+	}
+}
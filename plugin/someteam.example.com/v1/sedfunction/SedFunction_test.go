@@ -0,0 +1,75 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestSedFunction(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepExecPlugin("someteam.example.com", "v1", "SedFunction")
+	defer th.Reset()
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: someteam.example.com/v1
+kind: SedFunction
+metadata:
+  name: notImportantHere
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: example.com/sed-function
+argsOneLiner: s/one/two/g s/$FRUIT/orange/g
+`,
+		`apiVersion: apps/v1
+kind: MeatBall
+metadata:
+  name: notImportantHere
+beans: one one one one
+fruit: $FRUIT
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+beans: two two two two
+fruit: orange
+kind: MeatBall
+metadata:
+  name: notImportantHere
+`)
+}
+
+func TestSedFunctionErrorResult(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepExecPlugin("someteam.example.com", "v1", "SedFunction")
+	defer th.Reset()
+
+	err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: someteam.example.com/v1
+kind: SedFunction
+metadata:
+  name: notImportantHere
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: example.com/sed-function
+argsOneLiner: s/one/two/g
+`,
+		`apiVersion: apps/v1
+kind: MeatBall
+metadata:
+  name: notImportantHere
+fruit: $FRUIT
+`)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "[error] unreplaced variable $FRUIT") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}